@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/taramakage/gon-verifier/internal/verifier"
+)
+
+func newDiscoverCmd(g *globalFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "discover <evidence.xlsx>",
+		Short: "Propose the evidence of every task from the txs sent by the addresses of the Info sheet",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := g.loadCampaign()
+			if err != nil {
+				return err
+			}
+			participants, err := g.loadParticipants()
+			if err != nil {
+				return err
+			}
+			cr, err := g.newChainRegistry()
+			if err != nil {
+				return err
+			}
+			defer cr.Close()
+
+			gv, err := verifier.NewGonVerifier("", cr, c, participants, g.outputs, g.discovery)
+			if err != nil {
+				return err
+			}
+			discoveries, err := gv.Discover(args[0])
+			if err != nil {
+				return err
+			}
+			printDiscoveries(cmd.OutOrStdout(), discoveries)
+			return nil
+		},
+	}
+}
+
+// printDiscoveries prints the rows discovered for each task under how the evidence differs from them.
+func printDiscoveries(w io.Writer, discoveries []verifier.Discovery) {
+	for _, d := range discoveries {
+		switch {
+		case d.Err != nil:
+			fmt.Fprintf(w, "%s: %v\n", d.TaskNo, d.Err)
+			continue
+		case len(d.Rows) == 0:
+			fmt.Fprintf(w, "%s: nothing found on chain\n", d.TaskNo)
+			continue
+		case len(d.Diff) == 0:
+			fmt.Fprintf(w, "%s: evidence matches the chain\n", d.TaskNo)
+		default:
+			fmt.Fprintf(w, "%s: %s\n", d.TaskNo, d.Diff)
+		}
+		for i, row := range d.Rows {
+			fmt.Fprintf(w, "\trow %d: %s\n", i+2, strings.Join(row, "\t"))
+		}
+	}
+}
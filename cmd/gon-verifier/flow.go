@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+)
+
+func newFlowCmd(g *globalFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flow",
+		Short: "Inspect the transfer flows",
+	}
+	cmd.AddCommand(newFlowLintCmd(), newFlowTraceCmd(g))
+	return cmd
+}
+
+func newFlowTraceCmd(g *globalFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "trace <iris-tx-hash> [token-id]",
+		Short: "Follow an nft from its send on iris and print the route taken and the flows it matches",
+		Long:  "Follow an nft from its send on iris and print the route taken and the flows it matches.\nA tx sending several nfts needs the token id of the one to follow.",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cr, err := g.newChainRegistry()
+			if err != nil {
+				return err
+			}
+			defer cr.Close()
+
+			var tokenId string
+			if len(args) > 1 {
+				tokenId = args[1]
+			}
+			trace, err := chain.TraceFlowToken(cr, args[0], tokenId)
+			if err != nil {
+				return err
+			}
+			printTrace(cmd.OutOrStdout(), trace)
+			return nil
+		},
+	}
+}
+
+// printTrace prints the route of a trace, the send tx of each hop and the flow ids of the route.
+func printTrace(w io.Writer, trace *chain.Trace) {
+	fmt.Fprintf(w, "route: %s\n", trace.Flow)
+	for i, hash := range trace.TxHashes {
+		fmt.Fprintf(w, "hop %d: %s %s\n", i+1, trace.Flow.GetSrcChainAbbr(i), hash)
+	}
+	fmt.Fprintf(w, "nft %s rests on %s under %s\n", trace.TokenId, trace.ChainId, trace.ClassId)
+
+	flowIds := chain.MatchFlowIds(trace.Flow)
+	if len(flowIds) == 0 {
+		fmt.Fprintln(w, "flows: none")
+		return
+	}
+	fmt.Fprintf(w, "flows: %s\n", strings.Join(flowIds, " "))
+}
+
+func newFlowLintCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint [flow-id...]",
+		Short: "Check every flow parses and each hop has a port/channel pair, all flows if no id is given",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flowIds := args
+			if len(flowIds) == 0 {
+				flowIds = maps.Keys(chain.FlowStrMap)
+				slices.Sort(flowIds)
+			}
+			if failed := lintFlows(cmd.OutOrStdout(), flowIds); failed != 0 {
+				return fmt.Errorf("%d of %d flow(s) invalid", failed, len(flowIds))
+			}
+			return nil
+		},
+	}
+}
+
+// lintFlows prints the error of every invalid flow with a caret under its column, it returns the number of them.
+func lintFlows(w io.Writer, flowIds []string) int {
+	failed := 0
+	for _, flowId := range flowIds {
+		_, err := chain.LookupFlow(flowId)
+		if err == nil {
+			continue
+		}
+		failed++
+
+		var flowErr *chain.FlowError
+		if !errors.As(err, &flowErr) {
+			fmt.Fprintln(w, err)
+			continue
+		}
+		fmt.Fprintf(w, "%s: col %d: %s\n", flowId, flowErr.Col, flowErr.Msg)
+		fmt.Fprintf(w, "\t%s\n\t%s^\n", flowErr.Flow, strings.Repeat(" ", flowErr.Col-1))
+	}
+	fmt.Fprintf(w, "%d flow(s) checked, %d invalid\n", len(flowIds), failed)
+	return failed
+}
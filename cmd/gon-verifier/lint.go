@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/taramakage/gon-verifier/internal/verifier"
+)
+
+func newLintCmd(g *globalFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint <evidence>",
+		Short: "Check the evidence against the schema of every task before any chain query",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := g.loadCampaign()
+			if err != nil {
+				return err
+			}
+			issues, err := verifier.Lint(args[0], c)
+			if err != nil {
+				return err
+			}
+			for _, issue := range issues {
+				fmt.Fprintln(cmd.OutOrStdout(), issue)
+			}
+			if len(issues) != 0 {
+				return fmt.Errorf("%d issue(s) found", len(issues))
+			}
+			return nil
+		},
+	}
+}
@@ -1,84 +1,111 @@
 package main
 
 import (
-	"errors"
+	"os"
+
 	"github.com/spf13/cobra"
-	"github.com/taramakage/gon-verifier/internal/scorecard"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/participant"
 	"github.com/taramakage/gon-verifier/internal/verifier"
-	"os"
 )
 
+// globalFlags are shared by every subcommand.
+type globalFlags struct {
+	campaignFile string
+	chainFile    string
+	grpcs        map[string]string
+	rpcs         map[string]string
+	proofRPCs    map[string]string
+	fixtureDir   string
+	offline      bool
+	outputs      []string
+	discovery    string
+	participants string
+}
+
 func main() {
+	g := &globalFlags{}
+
 	rootCmd := &cobra.Command{
-		Use:   "gon-verify",
-		Short: "GoN evidence verify tools",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) != 1 {
-				return errors.New("invalid argument")
-			}
-			filePath := args[0]
+		Use:          "gon-verifier",
+		Short:        "GoN evidence verify tools",
+		SilenceUsage: true,
+	}
+	rootCmd.PersistentFlags().StringVar(&g.campaignFile, "campaign", "", "campaign file in yaml, the built-in GoN campaign is used if empty")
+	rootCmd.PersistentFlags().StringVar(&g.chainFile, "chains", "", "chain endpoint file in yaml, overrides the built-in GoN endpoints")
+	rootCmd.PersistentFlags().StringToStringVar(&g.grpcs, "grpc", nil, "override grpc address per chain, e.g. iris=127.0.0.1:9090")
+	rootCmd.PersistentFlags().StringToStringVar(&g.rpcs, "rpc", nil, "override rpc url per chain, e.g. iris=http://127.0.0.1:26657")
+	rootCmd.PersistentFlags().StringToStringVar(&g.proofRPCs, "proof-rpc", nil, "verify the tx proofs of a chain against the headers of another rpc, e.g. iris=https://rpc.example.com")
+	rootCmd.PersistentFlags().StringVar(&g.fixtureDir, "fixtures", "", "directory recording every chain response")
+	rootCmd.PersistentFlags().BoolVar(&g.offline, "offline", false, "serve chain responses from the fixtures directory only")
+	rootCmd.PersistentFlags().StringSliceVar(&g.outputs, "output", verifier.DefaultOutputs, "formats of the task results: xlsx, json (lines) and csv")
+	rootCmd.PersistentFlags().StringVar(&g.participants, "participants", "", "participants registry in csv or json, identifies the participants by id rather than by the directory of their evidence")
+	rootCmd.PersistentFlags().StringVar(&g.discovery, "discovery", verifier.DiscoveryOff, "discover the evidence on chain: suggest fills missing evidence, strict only reports the differences")
 
-			{
-				task := []string{"A1", "A2", "A3", "A4", "A5", "A6"}
-				opt := &verifier.Options{
-					task,
-					scorecard.DefaultStageOneTaskPoint,
-					verifier.VerifyRegistryStageOne,
-				}
-				gv := verifier.NewGonVerifier("", opt)
-				err := gv.Verify(filePath)
-				if err != nil {
-					return err
-				}
-			}
+	rootCmd.AddCommand(
+		newVerifyCmd(g),
+		newVerifyAllCmd(g),
+		newRankCmd(g),
+		newScorecardCmd(g),
+		newFlowCmd(g),
+		newDiscoverCmd(g),
+		newLintCmd(g),
+		newTemplateCmd(g),
+		newParticipantsCmd(),
+		newPipelineCmd(g),
+	)
 
-			{
-				task := []string{"A7", "A8", "A9", "A10", "A11", "A12", "A13", "A14", "A15", "A16", "A17", "A18", "A19", "A20"}
-				opt := &verifier.Options{
-					task,
-					scorecard.DefaultStageTwoTaskPoint,
-					verifier.VerifyRegistryStageTwo,
-				}
-				gv := verifier.NewGonVerifier("", opt)
-				err := gv.Verify(filePath)
-				if err != nil {
-					return err
-				}
-			}
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
 
-			{
-				task := []string{"A7", "A9", "A11", "A16", "A17", "A19"}
-				opt := &verifier.Options{
-					task,
-					scorecard.DefaultStageTwoBTaskPoint,
-					verifier.VerifyRegistryStageTwoShadow,
-				}
-				gv := verifier.NewGonVerifier("", opt)
-				err := gv.Verify(filePath)
-				if err != nil {
-					return err
-				}
-			}
+func (g *globalFlags) loadCampaign() (*campaign.Campaign, error) {
+	return campaign.Load(g.campaignFile)
+}
 
-			{
-				task := []string{"B1", "B2", "B5", "B6", "B7"}
-				opt := &verifier.Options{
-					task,
-					scorecard.DefaultStageThreeTaskPoint,
-					verifier.VerifyRegistryStageThree,
-				}
-				gv := verifier.NewGonVerifier("", opt)
-				err := gv.Verify(filePath)
-				if err != nil {
-					return err
-				}
-			}
+// loadParticipants loads the participants registry, nil without --participants.
+func (g *globalFlags) loadParticipants() (*participant.Registry, error) {
+	if len(g.participants) == 0 {
+		return nil, nil
+	}
+	return participant.Load(g.participants)
+}
 
-			return nil
-		},
+// newChainRegistry dials the chains, the registry is shared by every participant and must be closed.
+func (g *globalFlags) newChainRegistry() (*chain.Registry, error) {
+	cfg, err := loadChainConfig(g.chainFile, g.grpcs, g.rpcs, g.proofRPCs, g.fixtureDir, g.offline)
+	if err != nil {
+		return nil, err
 	}
+	return chain.NewRegistry(cfg)
+}
 
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+// loadChainConfig applies the chain file, the GON_<NAME>_* env and the flags in order.
+func loadChainConfig(file string, grpcs, rpcs, proofRPCs map[string]string, fixtureDir string, offline bool) (*chain.Config, error) {
+	cfg, err := chain.LoadConfig(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.ApplyEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.ApplyOverrides(chain.ConfigKeyGRPC, grpcs); err != nil {
+		return nil, err
+	}
+	if err := cfg.ApplyOverrides(chain.ConfigKeyRPC, rpcs); err != nil {
+		return nil, err
+	}
+	if err := cfg.ApplyOverrides(chain.ConfigKeyProofRPC, proofRPCs); err != nil {
+		return nil, err
+	}
+	if len(fixtureDir) != 0 {
+		cfg.FixtureDir = fixtureDir
+	}
+	if offline {
+		cfg.Offline = true
 	}
+	return cfg, cfg.Validate()
 }
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/taramakage/gon-verifier/internal/participant"
+	"github.com/taramakage/gon-verifier/internal/verifier"
+)
+
+func newParticipantsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "participants <participants-file> [entrance-dir]",
+		Short: "Report the addresses and teams registered by several participants, and the evidence matching none",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			participants, err := participant.Load(args[0])
+			if err != nil {
+				return err
+			}
+			var conflicts []string
+			for _, conflict := range participants.Conflicts() {
+				conflicts = append(conflicts, conflict.String())
+			}
+			if len(args) == 2 {
+				evidence, err := verifier.EvidenceConflicts(args[1], participants)
+				if err != nil {
+					return err
+				}
+				conflicts = append(conflicts, evidence...)
+			}
+
+			for _, conflict := range conflicts {
+				fmt.Fprintln(cmd.OutOrStdout(), conflict)
+			}
+			if len(conflicts) != 0 {
+				return fmt.Errorf("%d conflict(s) found", len(conflicts))
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%d participant(s), no conflict\n", len(participants.All()))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	"golang.org/x/exp/slog"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/scorecard"
+	"github.com/taramakage/gon-verifier/internal/verifier"
+)
+
+func newPipelineCmd(g *globalFlags) *cobra.Command {
+	var workers int
+	cmd := &cobra.Command{
+		Use:   "pipeline <entrance-dir>",
+		Short: "Verify every participant, rank them and generate the scorecard",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entrance := args[0]
+			if !slices.Contains(g.outputs, verifier.OutputXlsx) {
+				return errors.New("pipeline ranks and scores the task point files, xlsx output is required")
+			}
+			c, err := g.loadCampaign()
+			if err != nil {
+				return err
+			}
+			participants, err := g.loadParticipants()
+			if err != nil {
+				return err
+			}
+			// conflicting participants are reported but don't stop the others
+			for _, conflict := range participants.Conflicts() {
+				slog.Warn("participant conflict", "Conflict", conflict.String())
+			}
+			cr, err := g.newChainRegistry()
+			if err != nil {
+				return err
+			}
+			defer cr.Close()
+
+			// a participant that failed is left out of the ranks and the scorecard rather than stopping the others
+			failed, err := verifyAll(cmd.OutOrStdout(), cr, c, participants, entrance, workers, g.outputs, g.discovery)
+			if err != nil {
+				return err
+			}
+			if failed != 0 {
+				slog.Warn("some participants failed to verify", "Failed", failed)
+			}
+
+			// ranks read the race results of stage three and write their points back to it
+			for _, kind := range []string{campaign.RankIndiv, campaign.RankTeam, campaign.RankQuiz} {
+				if err := runRanks(cr, participants, c, entrance, kind); err != nil {
+					return err
+				}
+			}
+
+			return scorecard.NewScoreCard(c, entrance, participants).Generate()
+		},
+	}
+	cmd.Flags().IntVar(&workers, "workers", verifier.DefaultWorkers, "number of participants verified at the same time")
+	return cmd
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/participant"
+	"github.com/taramakage/gon-verifier/internal/rank"
+	"github.com/taramakage/gon-verifier/internal/verifier"
+)
+
+func newRankCmd(g *globalFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rank",
+		Short: "Rank the participants once they are all verified and award the rank tasks",
+	}
+	for _, kind := range []struct {
+		name  string
+		short string
+	}{
+		{campaign.RankIndiv, "Rank the individual races, e.g. B3 and B4"},
+		{campaign.RankTeam, "Rank the team races, e.g. B8"},
+		{campaign.RankQuiz, "Rank the quiz by the nfts held on iris, e.g. B9"},
+	} {
+		kind := kind
+		cmd.AddCommand(&cobra.Command{
+			Use:   kind.name + " <entrance-dir>",
+			Short: kind.short,
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				c, err := g.loadCampaign()
+				if err != nil {
+					return err
+				}
+				if len(c.RanksOf(kind.name)) == 0 {
+					return fmt.Errorf("no %s rank in campaign %s", kind.name, c.Name)
+				}
+				participants, err := g.loadParticipants()
+				if err != nil {
+					return err
+				}
+
+				// only the quiz queries the chains
+				var cr *chain.Registry
+				if kind.name == campaign.RankQuiz {
+					cr, err = g.newChainRegistry()
+					if err != nil {
+						return err
+					}
+					defer cr.Close()
+				}
+				return runRanks(cr, participants, c, args[0], kind.name)
+			},
+		})
+	}
+	return cmd
+}
+
+// runRanks runs every rank of a kind in the campaign, each writes rank<task>.xlsx to the entrance.
+func runRanks(cr *chain.Registry, participants *participant.Registry, c *campaign.Campaign, entrance, kind string) error {
+	var entrants []rank.Entrant
+	if kind == campaign.RankQuiz {
+		var err error
+		entrants, err = readEntrants(entrance, participants)
+		if err != nil {
+			return err
+		}
+	}
+	for _, rk := range c.RanksOf(kind) {
+		ranker, err := rank.NewRanker(cr, participants, entrants, entrance, c, rk)
+		if err != nil {
+			return err
+		}
+		if err := rank.Rank(ranker); err != nil {
+			return fmt.Errorf("rank %s: %w", rk.Task, err)
+		}
+	}
+	return nil
+}
+
+// readEntrants reads the participant of every evidence under the entrance, an evidence that can't be read is left out.
+func readEntrants(entrance string, participants *participant.Registry) ([]rank.Entrant, error) {
+	files, err := verifier.EvidenceFiles(entrance)
+	if err != nil {
+		return nil, err
+	}
+	entrants := make([]rank.Entrant, 0, len(files))
+	for _, file := range files {
+		source, err := verifier.OpenEvidence(file)
+		if err != nil {
+			continue
+		}
+		user, err := verifier.ReadUserInfo(source, filepath.Dir(file), participants)
+		source.Close()
+		if err != nil {
+			continue
+		}
+		entrants = append(entrants, rank.Entrant{
+			Participant: user.Id,
+			TeamName:    user.TeamName,
+			Address:     user.Address,
+			Path:        file,
+		})
+	}
+	return entrants, nil
+}
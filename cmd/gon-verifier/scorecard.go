@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/taramakage/gon-verifier/internal/scorecard"
+)
+
+func newScorecardCmd(g *globalFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "scorecard <entrance-dir>",
+		Short: "Sum the task points of every participant into scorecard.xlsx",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := g.loadCampaign()
+			if err != nil {
+				return err
+			}
+			participants, err := g.loadParticipants()
+			if err != nil {
+				return err
+			}
+			return scorecard.NewScoreCard(c, args[0], participants).Generate()
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/taramakage/gon-verifier/internal/verifier"
+)
+
+func newTemplateCmd(g *globalFlags) *cobra.Command {
+	var stage string
+	cmd := &cobra.Command{
+		Use:   "template <evidence.xlsx>",
+		Short: "Write a blank evidence workbook laid out as the verifiers of the campaign read it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := g.loadCampaign()
+			if err != nil {
+				return err
+			}
+			return verifier.WriteTemplate(args[0], c, stage)
+		},
+	}
+	cmd.Flags().StringVar(&stage, "stage", "", "name of the stage whose tasks get a sheet, every stage if empty")
+	return cmd
+}
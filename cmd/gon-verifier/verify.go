@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/participant"
+	"github.com/taramakage/gon-verifier/internal/verifier"
+)
+
+func newVerifyCmd(g *globalFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify <evidence.xlsx>",
+		Short: "Verify every stage of one participant",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := g.loadCampaign()
+			if err != nil {
+				return err
+			}
+			participants, err := g.loadParticipants()
+			if err != nil {
+				return err
+			}
+			cr, err := g.newChainRegistry()
+			if err != nil {
+				return err
+			}
+			defer cr.Close()
+
+			gv, err := verifier.NewGonVerifier("", cr, c, participants, g.outputs, g.discovery)
+			if err != nil {
+				return err
+			}
+			return gv.Verify(args[0]).Err
+		},
+	}
+}
+
+func newVerifyAllCmd(g *globalFlags) *cobra.Command {
+	var workers int
+	cmd := &cobra.Command{
+		Use:   "verify-all <entrance-dir>",
+		Short: "Verify every <participant>/evidence.xlsx under the entrance directory",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := g.loadCampaign()
+			if err != nil {
+				return err
+			}
+			participants, err := g.loadParticipants()
+			if err != nil {
+				return err
+			}
+			cr, err := g.newChainRegistry()
+			if err != nil {
+				return err
+			}
+			defer cr.Close()
+
+			failed, err := verifyAll(cmd.OutOrStdout(), cr, c, participants, args[0], workers, g.outputs, g.discovery)
+			if err != nil {
+				return err
+			}
+			if failed != 0 {
+				return fmt.Errorf("%d participant(s) failed", failed)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&workers, "workers", verifier.DefaultWorkers, "number of participants verified at the same time")
+	return cmd
+}
+
+// verifyAll verifies every participant under the entrance and prints the summary, it returns the number of failed participants.
+func verifyAll(w io.Writer, cr *chain.Registry, c *campaign.Campaign, participants *participant.Registry, entrance string, workers int, outputs []string, discovery string) (int, error) {
+	gv, err := verifier.NewGonVerifier(entrance, cr, c, participants, outputs, discovery)
+	if err != nil {
+		return 0, err
+	}
+	outcomes, err := gv.VerifyAll(workers)
+	if err != nil {
+		return 0, err
+	}
+	return verifier.WriteSummary(w, outcomes), nil
+}
@@ -0,0 +1,397 @@
+package campaign
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+)
+
+// Kinds of verifier a task can be mapped to.
+const (
+	VerifierA1   = "a1"
+	VerifierA2   = "a2"
+	VerifierA3   = "a3"
+	VerifierA4   = "a4"
+	VerifierA5   = "a5"
+	VerifierA6   = "a6"
+	VerifierFlow = "flow"
+	VerifierRace = "race"
+)
+
+// Kinds of rank a rank task is computed by.
+const (
+	RankIndiv = "indiv"
+	RankTeam  = "team"
+	RankQuiz  = "quiz"
+)
+
+//go:embed gon.yaml
+var defaultCampaign []byte
+
+type (
+	// Campaign describes one event: its stages, the verifier of every task and the points.
+	Campaign struct {
+		Name      string           `yaml:"name"`
+		EndHeight int64            `yaml:"end_height"`
+		Points    map[string]int32 `yaml:"points"`
+		Races     map[string]Race  `yaml:"races"`
+		Stages    []Stage          `yaml:"stages"`
+		Ranks     []Rank           `yaml:"ranks"`
+	}
+
+	// Race is the window and the designated last owner of a race task.
+	Race struct {
+		Denom       string `yaml:"denom"`
+		Owner       string `yaml:"owner"`
+		StartHeight int64  `yaml:"start_height"`
+		EndHeight   int64  `yaml:"end_height"`
+	}
+
+	// Stage is a set of tasks whose results are written to the same task point file.
+	Stage struct {
+		Name          string `yaml:"name"`
+		TaskPointFile string `yaml:"task_point_file"`
+		// Heights pins the state of a chain, keyed by abbreviation, to the height ownership is evaluated at,
+		// e.g. the end of the game. A chain without height is queried at the latest height.
+		Heights map[string]int64 `yaml:"heights,omitempty"`
+		// Window bounds the txs of every task of the stage unless the task has its own.
+		Window *Window `yaml:"window,omitempty"`
+		Tasks  []Task  `yaml:"tasks"`
+	}
+
+	// Task maps an evidence sheet to a verifier.
+	Task struct {
+		No       string  `yaml:"no"`
+		Verifier string  `yaml:"verifier"`
+		Flow     string  `yaml:"flow,omitempty"`
+		Ngb      bool    `yaml:"ngb,omitempty"`
+		Race     string  `yaml:"race,omitempty"`
+		Window   *Window `yaml:"window,omitempty"`
+		// StrictRoute fails a never-go-back task whose route traced isn't the flow, or can't be traced.
+		StrictRoute bool `yaml:"strict_route,omitempty"`
+		// FeePayer fails a tx of the task whose fees aren't paid by the participant.
+		FeePayer bool `yaml:"fee_payer,omitempty"`
+	}
+
+	// Window bounds the txs of a task by heights, keyed by chain abbreviation, and by block times.
+	// A bound left out is open, both bounds are inclusive.
+	Window struct {
+		StartHeights map[string]int64 `yaml:"start_heights,omitempty"`
+		EndHeights   map[string]int64 `yaml:"end_heights,omitempty"`
+		StartTime    time.Time        `yaml:"start_time,omitempty"`
+		EndTime      time.Time        `yaml:"end_time,omitempty"`
+		// EndTimeAt ends the window at the time of the block at a height, keyed by chain abbreviation,
+		// the earliest of these times and EndTime is the end time.
+		EndTimeAt map[string]int64 `yaml:"end_time_at,omitempty"`
+	}
+
+	// Rank is a task whose point is awarded by ranking the results of its target tasks once every participant is verified.
+	Rank struct {
+		Task    string   `yaml:"task"`
+		Kind    string   `yaml:"kind"`
+		Targets []string `yaml:"targets,omitempty"`
+	}
+)
+
+// Default returns the built-in GoN campaign.
+func Default() (*Campaign, error) {
+	return Parse(defaultCampaign)
+}
+
+// Load reads and validates a campaign file, the built-in campaign is returned if file is empty.
+func Load(file string) (*Campaign, error) {
+	if len(file) == 0 {
+		return Default()
+	}
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Parse(bz)
+	if err != nil {
+		return nil, fmt.Errorf("campaign %s: %w", file, err)
+	}
+	return c, nil
+}
+
+// Parse decodes and validates a campaign in yaml.
+func Parse(bz []byte) (*Campaign, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(bz))
+	dec.KnownFields(true)
+
+	var c Campaign
+	if err := dec.Decode(&c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Validate checks that every task can be built into a verifier.
+func (c *Campaign) Validate() error {
+	if len(c.Stages) == 0 {
+		return errors.New("no stage defined")
+	}
+
+	for name, race := range c.Races {
+		if err := race.Validate(); err != nil {
+			return fmt.Errorf("race %s: %w", name, err)
+		}
+	}
+
+	files := make(map[string]bool)
+	for _, stage := range c.Stages {
+		if len(stage.TaskPointFile) == 0 {
+			return fmt.Errorf("stage %s: task point file is empty", stage.Name)
+		}
+		if files[stage.TaskPointFile] {
+			return fmt.Errorf("stage %s: task point file %s is used by another stage", stage.Name, stage.TaskPointFile)
+		}
+		files[stage.TaskPointFile] = true
+
+		for abbr, height := range stage.Heights {
+			if _, ok := chain.ChainNames[abbr]; !ok {
+				return fmt.Errorf("stage %s: unknown chain %q", stage.Name, abbr)
+			}
+			if height <= 0 {
+				return fmt.Errorf("stage %s: invalid height %d of chain %s", stage.Name, height, abbr)
+			}
+		}
+
+		if stage.Window != nil {
+			if err := stage.Window.Validate(); err != nil {
+				return fmt.Errorf("stage %s: window: %w", stage.Name, err)
+			}
+		}
+		if len(stage.Tasks) == 0 {
+			return fmt.Errorf("stage %s: no task defined", stage.Name)
+		}
+		taskNos := make(map[string]bool)
+		for _, task := range stage.Tasks {
+			if taskNos[task.No] {
+				return fmt.Errorf("stage %s: task %s is defined twice", stage.Name, task.No)
+			}
+			taskNos[task.No] = true
+
+			if err := c.validateTask(task); err != nil {
+				return fmt.Errorf("stage %s: task %s: %w", stage.Name, task.No, err)
+			}
+		}
+	}
+
+	for _, rank := range c.Ranks {
+		if err := c.validateRank(rank); err != nil {
+			return fmt.Errorf("rank %s: %w", rank.Task, err)
+		}
+	}
+	return nil
+}
+
+func (c *Campaign) validateTask(task Task) error {
+	if len(task.No) == 0 {
+		return errors.New("task no is empty")
+	}
+	if _, ok := c.Points[task.No]; !ok {
+		return errors.New("point not found")
+	}
+
+	if task.Window != nil {
+		if err := task.Window.Validate(); err != nil {
+			return fmt.Errorf("window: %w", err)
+		}
+	}
+
+	switch task.Verifier {
+	case VerifierA1, VerifierA2, VerifierA3, VerifierA4, VerifierA5, VerifierA6:
+	case VerifierFlow:
+		if _, err := chain.LookupFlow(task.Flow); err != nil {
+			return err
+		}
+		if task.StrictRoute && !task.Ngb {
+			return errors.New("strict route without ngb")
+		}
+	case VerifierRace:
+		if _, ok := c.Races[task.Race]; !ok {
+			return fmt.Errorf("unknown race %q", task.Race)
+		}
+	default:
+		return fmt.Errorf("unknown verifier %q", task.Verifier)
+	}
+	return nil
+}
+
+func (c *Campaign) validateRank(rank Rank) error {
+	if _, ok := c.Points[rank.Task]; !ok {
+		return errors.New("point not found")
+	}
+
+	switch rank.Kind {
+	case RankIndiv:
+		if len(rank.Targets) != 1 {
+			return fmt.Errorf("want 1 target, got %d", len(rank.Targets))
+		}
+	case RankTeam:
+		if len(rank.Targets) == 0 {
+			return errors.New("no target defined")
+		}
+	case RankQuiz:
+		if len(rank.Targets) != 0 {
+			return errors.New("quiz has no target")
+		}
+	default:
+		return fmt.Errorf("unknown kind %q", rank.Kind)
+	}
+
+	for _, target := range rank.Targets {
+		task, ok := c.Task(target)
+		if !ok {
+			return fmt.Errorf("target %s is not a task", target)
+		}
+		if task.Verifier != VerifierRace {
+			return fmt.Errorf("target %s is not a race", target)
+		}
+	}
+	stage, ok := c.RankStage(rank)
+	if !ok {
+		return errors.New("no stage holds a race")
+	}
+	for _, target := range rank.Targets {
+		if other, _ := c.StageOf(target); other != stage {
+			return fmt.Errorf("target %s is not in stage %s", target, stage.Name)
+		}
+	}
+	return nil
+}
+
+// Task returns the first task with the number among the stages.
+func (c *Campaign) Task(taskNo string) (Task, bool) {
+	for _, stage := range c.Stages {
+		for _, task := range stage.Tasks {
+			if task.No == taskNo {
+				return task, true
+			}
+		}
+	}
+	return Task{}, false
+}
+
+// StageOf returns the first stage holding a task.
+func (c *Campaign) StageOf(taskNo string) (*Stage, bool) {
+	for i := range c.Stages {
+		for _, task := range c.Stages[i].Tasks {
+			if task.No == taskNo {
+				return &c.Stages[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// RankStage returns the stage whose task point file a rank reads and writes: the stage of its first target,
+// or the first stage holding a race for a rank without target.
+func (c *Campaign) RankStage(rank Rank) (*Stage, bool) {
+	if len(rank.Targets) != 0 {
+		return c.StageOf(rank.Targets[0])
+	}
+	for i := range c.Stages {
+		for _, task := range c.Stages[i].Tasks {
+			if task.Verifier == VerifierRace {
+				return &c.Stages[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// TaskPointFiles returns the task point file of every stage in order.
+func (c *Campaign) TaskPointFiles() []string {
+	files := make([]string, 0, len(c.Stages))
+	for _, stage := range c.Stages {
+		files = append(files, stage.TaskPointFile)
+	}
+	return files
+}
+
+// Stage returns a stage by name.
+func (c *Campaign) Stage(name string) (*Stage, bool) {
+	for i := range c.Stages {
+		if c.Stages[i].Name == name {
+			return &c.Stages[i], true
+		}
+	}
+	return nil, false
+}
+
+// RanksOf returns the rank tasks of a kind in order.
+func (c *Campaign) RanksOf(kind string) []Rank {
+	ranks := make([]Rank, 0)
+	for _, rank := range c.Ranks {
+		if rank.Kind == kind {
+			ranks = append(ranks, rank)
+		}
+	}
+	return ranks
+}
+
+// Validate checks the race window and the designated owner.
+func (r Race) Validate() error {
+	if len(r.Denom) == 0 {
+		return errors.New("denom is empty")
+	}
+	if len(r.Owner) == 0 {
+		return errors.New("owner is empty")
+	}
+	if r.StartHeight <= 0 || r.EndHeight < r.StartHeight {
+		return fmt.Errorf("invalid window [%d, %d]", r.StartHeight, r.EndHeight)
+	}
+	return nil
+}
+
+// Validate checks the chains of the heights and that no bound ends before it starts.
+func (w *Window) Validate() error {
+	for _, heights := range []map[string]int64{w.StartHeights, w.EndHeights, w.EndTimeAt} {
+		for abbr, height := range heights {
+			if _, ok := chain.ChainNames[abbr]; !ok {
+				return fmt.Errorf("unknown chain %q", abbr)
+			}
+			if height <= 0 {
+				return fmt.Errorf("invalid height %d of chain %s", height, abbr)
+			}
+		}
+	}
+	for abbr, start := range w.StartHeights {
+		if end, ok := w.EndHeights[abbr]; ok && end < start {
+			return fmt.Errorf("chain %s ends at %d before it starts at %d", abbr, end, start)
+		}
+	}
+	if !w.StartTime.IsZero() && !w.EndTime.IsZero() && w.EndTime.Before(w.StartTime) {
+		return fmt.Errorf("ends at %s before it starts at %s", w.EndTime.Format(time.RFC3339), w.StartTime.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// WindowOf returns the window of a task of the stage, nil if neither the task nor the stage has one.
+func (s *Stage) WindowOf(task Task) *Window {
+	if task.Window != nil {
+		return task.Window
+	}
+	return s.Window
+}
+
+// TaskNos returns the task numbers of the stage in order.
+func (s *Stage) TaskNos() []string {
+	taskNos := make([]string, 0, len(s.Tasks))
+	for _, task := range s.Tasks {
+		taskNos = append(taskNos, task.No)
+	}
+	return taskNos
+}
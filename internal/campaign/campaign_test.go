@@ -0,0 +1,259 @@
+package campaign
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefault(t *testing.T) {
+	c, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the points and stages of the campaign before it was read from gon.yaml
+	points := map[string]int32{
+		"A1": 1, "A2": 1, "A3": 1, "A4": 1, "A5": 1, "A6": 1,
+		"A7": 2, "A8": 2, "A9": 2, "A10": 2, "A11": 3, "A12": 3,
+		"A13": 2, "A14": 2, "A15": 2, "A16": 2, "A17": 2, "A18": 2, "A19": 3, "A20": 3,
+		"B1": 5, "B2": 5, "B3": 50, "B4": 50, "B5": 10, "B6": 10, "B7": 10, "B8": 150, "B9": 10,
+	}
+	if !reflect.DeepEqual(c.Points, points) {
+		t.Errorf("points: want %v, got %v", points, c.Points)
+	}
+	for _, tc := range []struct {
+		name          string
+		taskPointFile string
+		taskNos       []string
+	}{
+		{"one", "taskpoint1.xlsx", []string{"A1", "A2", "A3", "A4", "A5", "A6"}},
+		{"two", "taskpoint2.xlsx", []string{"A7", "A8", "A9", "A10", "A11", "A12", "A13", "A14", "A15", "A16", "A17", "A18", "A19", "A20"}},
+		{"two-b", "taskpoint2b.xlsx", []string{"A7", "A9", "A11", "A16", "A17", "A19"}},
+		{"three", "taskpoint3.xlsx", []string{"B1", "B2", "B5", "B6", "B7"}},
+	} {
+		stage, ok := c.Stage(tc.name)
+		if !ok {
+			t.Errorf("stage %s not found", tc.name)
+			continue
+		}
+		if stage.TaskPointFile != tc.taskPointFile {
+			t.Errorf("stage %s: task point file: want %s, got %s", tc.name, tc.taskPointFile, stage.TaskPointFile)
+		}
+		if !reflect.DeepEqual(stage.TaskNos(), tc.taskNos) {
+			t.Errorf("stage %s: tasks: want %v, got %v", tc.name, tc.taskNos, stage.TaskNos())
+		}
+	}
+
+	// the flows and never-go-back tasks of the shadow stage
+	stage, _ := c.Stage("two-b")
+	flows := make([]string, 0, len(stage.Tasks))
+	for _, task := range stage.Tasks {
+		flows = append(flows, task.Flow)
+		if want := strings.HasPrefix(task.Flow, "a"); task.Ngb != want {
+			t.Errorf("task %s: ngb: want %v, got %v", task.No, want, task.Ngb)
+		}
+	}
+	if want := []string{"a01b", "a03b", "a05b", "b04b", "c01b", "c03b"}; !reflect.DeepEqual(flows, want) {
+		t.Errorf("stage two-b: flows: want %v, got %v", want, flows)
+	}
+
+	race := c.Races["indiv1"]
+	if race.Denom != "gonIndivRace1" || race.StartHeight != 473000 || race.EndHeight != 516223 {
+		t.Errorf("race indiv1: got %+v", race)
+	}
+	if stage, ok := c.RankStage(Rank{Kind: RankQuiz}); !ok || stage.Name != "three" {
+		t.Errorf("quiz stage: want three, got %v", stage)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	c, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "Game of NFTs" {
+		t.Fatalf("empty file: want the default campaign, got %q", c.Name)
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	for _, tc := range []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			file: write("valid.yaml", `
+name: small
+points: { A1: 1 }
+stages:
+  - { name: one, task_point_file: points.xlsx, tasks: [{ no: A1, verifier: a1 }] }
+`),
+		},
+		{
+			name:    "missing",
+			file:    filepath.Join(dir, "missing.yaml"),
+			wantErr: "no such file",
+		},
+		{
+			name:    "unknown field",
+			file:    write("unknown.yaml", "name: small\nstagez: []\n"),
+			wantErr: "field stagez not found",
+		},
+		{
+			name:    "invalid",
+			file:    write("invalid.yaml", "name: small\n"),
+			wantErr: "no stage defined",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := Load(tc.file)
+			if len(tc.wantErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("want error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Name != "small" || len(c.Stages) != 1 {
+				t.Fatalf("got %+v", c)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	at := func(s string) time.Time {
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	task := func(c *Campaign, stage int, no string) *Task {
+		for i := range c.Stages[stage].Tasks {
+			if c.Stages[stage].Tasks[i].No == no {
+				return &c.Stages[stage].Tasks[i]
+			}
+		}
+		t.Fatalf("task %s not in stage %d", no, stage)
+		return nil
+	}
+
+	for _, tc := range []struct {
+		name    string
+		mutate  func(c *Campaign)
+		wantErr string
+	}{
+		{name: "default", mutate: func(c *Campaign) {}},
+		{name: "no stage", mutate: func(c *Campaign) { c.Stages = nil }, wantErr: "no stage defined"},
+
+		// races
+		{name: "race without denom", mutate: func(c *Campaign) {
+			r := c.Races["indiv1"]
+			r.Denom = ""
+			c.Races["indiv1"] = r
+		}, wantErr: "race indiv1: denom is empty"},
+		{name: "race without owner", mutate: func(c *Campaign) {
+			r := c.Races["team1"]
+			r.Owner = ""
+			c.Races["team1"] = r
+		}, wantErr: "race team1: owner is empty"},
+		{name: "race ending before it starts", mutate: func(c *Campaign) {
+			r := c.Races["indiv2"]
+			r.EndHeight = r.StartHeight - 1
+			c.Races["indiv2"] = r
+		}, wantErr: "race indiv2: invalid window"},
+
+		// stages
+		{name: "stage without task point file", mutate: func(c *Campaign) { c.Stages[1].TaskPointFile = "" }, wantErr: "stage two: task point file is empty"},
+		{name: "task point file of two stages", mutate: func(c *Campaign) {
+			c.Stages[2].TaskPointFile = c.Stages[1].TaskPointFile
+		}, wantErr: "stage two-b: task point file taskpoint2.xlsx is used by another stage"},
+		{name: "stage without task", mutate: func(c *Campaign) { c.Stages[0].Tasks = nil }, wantErr: "stage one: no task defined"},
+		{name: "task defined twice", mutate: func(c *Campaign) {
+			c.Stages[0].Tasks = append(c.Stages[0].Tasks, c.Stages[0].Tasks[0])
+		}, wantErr: "stage one: task A1 is defined twice"},
+
+		// heights
+		{name: "height of an unknown chain", mutate: func(c *Campaign) { c.Stages[0].Heights["x"] = 1 }, wantErr: `stage one: unknown chain "x"`},
+		{name: "negative height", mutate: func(c *Campaign) { c.Stages[3].Heights["i"] = -1 }, wantErr: "stage three: invalid height -1 of chain i"},
+
+		// windows
+		{name: "window of an unknown chain", mutate: func(c *Campaign) {
+			c.Stages[0].Window = &Window{EndHeights: map[string]int64{"x": 10}}
+		}, wantErr: `stage one: window: unknown chain "x"`},
+		{name: "window ending before its start height", mutate: func(c *Campaign) {
+			c.Stages[0].Window = &Window{StartHeights: map[string]int64{"i": 20}, EndHeights: map[string]int64{"i": 10}}
+		}, wantErr: "stage one: window: chain i ends at 10 before it starts at 20"},
+		{name: "window ending before its start time", mutate: func(c *Campaign) {
+			c.Stages[1].Window = &Window{StartTime: at("2023-02-01T00:00:00Z"), EndTime: at("2023-01-01T00:00:00Z")}
+		}, wantErr: "stage two: window: ends at 2023-01-01T00:00:00Z before it starts at 2023-02-01T00:00:00Z"},
+		{name: "window ending at the time of an invalid block", mutate: func(c *Campaign) {
+			c.Stages[1].Window = &Window{EndTimeAt: map[string]int64{"i": 0}}
+		}, wantErr: "stage two: window: invalid height 0 of chain i"},
+		{name: "invalid window of a task", mutate: func(c *Campaign) {
+			task(c, 0, "A2").Window = &Window{StartHeights: map[string]int64{"u": 0}}
+		}, wantErr: "stage one: task A2: window: invalid height 0 of chain u"},
+
+		// tasks
+		{name: "task without no", mutate: func(c *Campaign) { task(c, 0, "A3").No = "" }, wantErr: "stage one: task : task no is empty"},
+		{name: "task without point", mutate: func(c *Campaign) { delete(c.Points, "A4") }, wantErr: "stage one: task A4: point not found"},
+		{name: "unknown verifier", mutate: func(c *Campaign) { task(c, 0, "A5").Verifier = "a9" }, wantErr: `stage one: task A5: unknown verifier "a9"`},
+		{name: "unknown flow", mutate: func(c *Campaign) { task(c, 1, "A8").Flow = "z99" }, wantErr: "stage two: task A8:"},
+		{name: "strict route without ngb", mutate: func(c *Campaign) { task(c, 1, "A13").StrictRoute = true }, wantErr: "stage two: task A13: strict route without ngb"},
+		{name: "strict route", mutate: func(c *Campaign) { task(c, 1, "A7").StrictRoute = true }},
+		{name: "unknown race", mutate: func(c *Campaign) { task(c, 3, "B1").Race = "indiv9" }, wantErr: `stage three: task B1: unknown race "indiv9"`},
+
+		// ranks
+		{name: "rank without point", mutate: func(c *Campaign) { delete(c.Points, "B3") }, wantErr: "rank B3: point not found"},
+		{name: "indiv rank of two targets", mutate: func(c *Campaign) { c.Ranks[0].Targets = []string{"B1", "B2"} }, wantErr: "rank B3: want 1 target, got 2"},
+		{name: "team rank without target", mutate: func(c *Campaign) { c.Ranks[2].Targets = nil }, wantErr: "rank B8: no target defined"},
+		{name: "quiz with a target", mutate: func(c *Campaign) { c.Ranks[3].Targets = []string{"B1"} }, wantErr: "rank B9: quiz has no target"},
+		{name: "unknown rank kind", mutate: func(c *Campaign) { c.Ranks[3].Kind = "duel" }, wantErr: `rank B9: unknown kind "duel"`},
+		{name: "target is not a task", mutate: func(c *Campaign) { c.Ranks[0].Targets = []string{"B0"} }, wantErr: "rank B3: target B0 is not a task"},
+		{name: "target is not a race", mutate: func(c *Campaign) { c.Ranks[1].Targets = []string{"A7"} }, wantErr: "rank B4: target A7 is not a race"},
+		{name: "targets in two stages", mutate: func(c *Campaign) {
+			b7 := *task(c, 3, "B7")
+			c.Stages[3].Tasks = c.Stages[3].Tasks[:len(c.Stages[3].Tasks)-1]
+			c.Stages = append(c.Stages, Stage{Name: "four", TaskPointFile: "taskpoint4.xlsx", Tasks: []Task{b7}})
+		}, wantErr: "rank B8: target B7 is not in stage three"},
+		{name: "quiz without race stage", mutate: func(c *Campaign) {
+			c.Stages = c.Stages[:3]
+			c.Ranks = c.Ranks[3:]
+		}, wantErr: "rank B9: no stage holds a race"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := Default()
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.mutate(c)
+			err = c.Validate()
+			if len(tc.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("want error %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type (
+	// BlockTimer returns the time of the block at a height.
+	BlockTimer interface {
+		BlockTime(height int64) (time.Time, error)
+	}
+
+	// Header is the part of a block header the verifier reads.
+	Header struct {
+		ChainId  string    `json:"chain_id"`
+		Height   string    `json:"height"`
+		Time     time.Time `json:"time"`
+		DataHash string    `json:"data_hash"`
+	}
+
+	headerResponse struct {
+		Result struct {
+			Block struct {
+				Header Header `json:"header"`
+			} `json:"block"`
+		} `json:"result"`
+	}
+)
+
+// getHeader returns the header of the block at a height with the block endpoint of a node.
+func getHeader(client *http.Client, rpc string, height int64) (*Header, error) {
+	body, err := getRespWithRetry(client, fmt.Sprintf(rpc+"block?height=%d", height))
+	if err != nil {
+		return nil, err
+	}
+	var data headerResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	return &data.Result.Block.Header, nil
+}
+
+// blockTime returns the time of the block at a height.
+func blockTime(client *http.Client, rpc string, height int64) (time.Time, error) {
+	header, err := getHeader(client, rpc, height)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if header.Time.IsZero() {
+		return time.Time{}, fmt.Errorf("block %d has no time", height)
+	}
+	return header.Time, nil
+}
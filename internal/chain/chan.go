@@ -2,32 +2,41 @@ package chain
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
-var PortChanPairStrMap = map[string]string{
-	"is-1": "nft-transfer/channel-22 <> wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-207",
-	"is-2": "nft-transfer/channel-23 <> wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-208",
-	"ij-1": "nft-transfer/channel-24 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-89",
-	"ij-2": "nft-transfer/channel-25 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-90",
-	"iu-1": "nft-transfer/channel-17 <> nft-transfer/channel-3",
-	"iu-2": "nft-transfer/channel-19 <> nft-transfer/channel-4",
-	"io-1": "nft-transfer/channel-0 <> nft-transfer/channel-24",
-	"io-2": "nft-transfer/channel-1 <> nft-transfer/channel-25",
-	"sj-1": "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-211 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-93",
-	"sj-2": "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-213 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-94",
-	"sj-3": "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-230 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-120",
-	"sj-4": "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-234 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-122",
-	"su-1": "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-203 <> nft-transfer/channel-6",
-	"su-2": "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-206 <> nft-transfer/channel-12",
-	"so-1": "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-209 <> nft-transfer/channel-44",
-	"so-2": "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-210 <> nft-transfer/channel-45",
-	"ju-1": "wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-86 <> nft-transfer/channel-7",
-	"ju-2": "wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-88 <> nft-transfer/channel-13",
-	"jo-1": "wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-91 <> nft-transfer/channel-46",
-	"jo-2": "wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-92 <> nft-transfer/channel-47",
-	"uo-1": "nft-transfer/channel-5 <> nft-transfer/channel-41",
-	"uo-2": "nft-transfer/channel-9 <> nft-transfer/channel-42",
+// PortChanPairKey identifies the port/channel pair id between the chains src and dest, the ports and channels of
+// src come first in PortChanPairStrMap. Chain abbreviations may be several characters long.
+type PortChanPairKey struct {
+	Src  string
+	Dest string
+	Id   string
+}
+
+var PortChanPairStrMap = map[PortChanPairKey]string{
+	{"i", "s", "1"}: "nft-transfer/channel-22 <> wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-207",
+	{"i", "s", "2"}: "nft-transfer/channel-23 <> wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-208",
+	{"i", "j", "1"}: "nft-transfer/channel-24 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-89",
+	{"i", "j", "2"}: "nft-transfer/channel-25 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-90",
+	{"i", "u", "1"}: "nft-transfer/channel-17 <> nft-transfer/channel-3",
+	{"i", "u", "2"}: "nft-transfer/channel-19 <> nft-transfer/channel-4",
+	{"i", "o", "1"}: "nft-transfer/channel-0 <> nft-transfer/channel-24",
+	{"i", "o", "2"}: "nft-transfer/channel-1 <> nft-transfer/channel-25",
+	{"s", "j", "1"}: "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-211 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-93",
+	{"s", "j", "2"}: "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-213 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-94",
+	{"s", "j", "3"}: "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-230 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-120",
+	{"s", "j", "4"}: "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-234 <> wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-122",
+	{"s", "u", "1"}: "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-203 <> nft-transfer/channel-6",
+	{"s", "u", "2"}: "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-206 <> nft-transfer/channel-12",
+	{"s", "o", "1"}: "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-209 <> nft-transfer/channel-44",
+	{"s", "o", "2"}: "wasm.stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh/channel-210 <> nft-transfer/channel-45",
+	{"j", "u", "1"}: "wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-86 <> nft-transfer/channel-7",
+	{"j", "u", "2"}: "wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-88 <> nft-transfer/channel-13",
+	{"j", "o", "1"}: "wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-91 <> nft-transfer/channel-46",
+	{"j", "o", "2"}: "wasm.juno1stv6sk0mvku34fj2mqrlyru6683866n306mfv52tlugtl322zmks26kg7a/channel-92 <> nft-transfer/channel-47",
+	{"u", "o", "1"}: "nft-transfer/channel-5 <> nft-transfer/channel-41",
+	{"u", "o", "2"}: "nft-transfer/channel-9 <> nft-transfer/channel-42",
 }
 
 type PortChan struct {
@@ -43,9 +52,7 @@ type PortChanPair struct {
 }
 
 func NewPortChanPair(src, dest, chanPairId string) (*PortChanPair, error) {
-	key := src + dest + "-" + chanPairId
-	if _, ok := PortChanPairStrMap[key]; ok {
-		value := PortChanPairStrMap[key]
+	if value, ok := PortChanPairStrMap[PortChanPairKey{Src: src, Dest: dest, Id: chanPairId}]; ok {
 		parts := strings.Split(value, " <> ")
 		spc := strings.Split(parts[0], "/")
 		dpc := strings.Split(parts[1], "/")
@@ -64,9 +71,7 @@ func NewPortChanPair(src, dest, chanPairId string) (*PortChanPair, error) {
 		}, nil
 	}
 
-	keyr := dest + src + "-" + chanPairId
-	if _, ok := PortChanPairStrMap[keyr]; ok {
-		value := PortChanPairStrMap[keyr]
+	if value, ok := PortChanPairStrMap[PortChanPairKey{Src: dest, Dest: src, Id: chanPairId}]; ok {
 		parts := strings.Split(value, " <> ")
 		dpc := strings.Split(parts[0], "/")
 		spc := strings.Split(parts[1], "/")
@@ -102,3 +107,33 @@ func (p *PortChanPair) GetSrcPortChan() PortChan {
 func (p *PortChanPair) GetDestPortChan() PortChan {
 	return p.dest
 }
+
+// FindPortChanPair returns the pair sending from the port and channel of a chain.
+func FindPortChanPair(src, port, channel string) (*PortChanPair, error) {
+	for key := range PortChanPairStrMap {
+		for _, dest := range []string{key.Dest, key.Src} {
+			pcp, err := NewPortChanPair(src, dest, key.Id)
+			if err != nil {
+				continue
+			}
+			if pcp.src.Port == port && pcp.src.Channel == channel {
+				return pcp, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no port channel pair from %s/%s on %s", port, channel, src)
+}
+
+// IsKnownChain tells whether a chain abbreviation is a side of a port/channel pair.
+func IsKnownChain(abbr string) bool {
+	for key := range PortChanPairStrMap {
+		if key.Src == abbr || key.Dest == abbr {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PortChanPair) GetId() string {
+	return p.id
+}
@@ -0,0 +1,303 @@
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultTimeout = 30 * time.Second
+
+	ConfigKeyGRPC     = "grpc"
+	ConfigKeyRPC      = "rpc"
+	ConfigKeyProofRPC = "proof_rpc"
+	ConfigKeyTLS      = "tls"
+	ConfigKeyTimeout  = "timeout"
+)
+
+// Abbreviations are the abbreviations of the GoN chains in the order of the evidence and participant columns.
+var Abbreviations = []string{
+	ChainIdAbbreviationIris,
+	ChainIdAbbreviationStars,
+	ChainIdAbbreviationJuno,
+	ChainIdAbbreviationUptick,
+	ChainIdAbbreviationOmniflix,
+}
+
+// ChainNames maps a chain abbreviation to the name used by env overrides.
+var ChainNames = map[string]string{
+	ChainIdAbbreviationIris:     "iris",
+	ChainIdAbbreviationStars:    "stars",
+	ChainIdAbbreviationJuno:     "juno",
+	ChainIdAbbreviationUptick:   "uptick",
+	ChainIdAbbreviationOmniflix: "omniflix",
+}
+
+// Bech32Prefixes maps a chain abbreviation to the bech32 prefix of its account and contract addresses.
+var Bech32Prefixes = map[string]string{
+	ChainIdAbbreviationIris:     "iaa",
+	ChainIdAbbreviationStars:    "stars",
+	ChainIdAbbreviationJuno:     "juno",
+	ChainIdAbbreviationUptick:   "uptick",
+	ChainIdAbbreviationOmniflix: "omniflix",
+}
+
+type (
+	// EndpointConfig is where and how a chain is queried.
+	EndpointConfig struct {
+		ChainId      string        `yaml:"chain_id"`
+		Abbreviation string        `yaml:"abbreviation"`
+		GRPC         string        `yaml:"grpc"`
+		RPC          string        `yaml:"rpc"`
+		TLS          bool          `yaml:"tls"`
+		Timeout      time.Duration `yaml:"timeout"`
+		// ICS721Bridge is the ics721 bridge contract of a wasm chain, the one of its ports in PortChanPairStrMap if empty.
+		ICS721Bridge string `yaml:"ics721_bridge"`
+		// ProofRPC is another node of the chain the tx proofs are verified against, no proof is verified if empty.
+		ProofRPC string `yaml:"proof_rpc"`
+		// Disabled leaves the chain with the abbreviation out, the other fields of the entry are ignored.
+		Disabled bool `yaml:"disabled"`
+	}
+
+	// Config lists the endpoints a Registry is built against.
+	Config struct {
+		Chains []EndpointConfig `yaml:"chains"`
+		// FixtureDir records every response of the chains if set, see FixtureStore.
+		FixtureDir string `yaml:"fixture_dir"`
+		// Offline serves the responses from FixtureDir without querying any node.
+		Offline bool `yaml:"offline"`
+	}
+)
+
+// DefaultConfig returns the GoN testnet endpoints.
+func DefaultConfig() *Config {
+	return &Config{
+		Chains: []EndpointConfig{
+			defaultEndpoint(ChainIdValueIirs, ChainIdAbbreviationIris, ChainGRPCIris, ChainRPCIris),
+			defaultEndpoint(ChainIdValueStars, ChainIdAbbreviationStars, ChainGRPCStars, ChainRPCStars),
+			defaultEndpoint(ChainIdValueJuno, ChainIdAbbreviationJuno, ChainGRPCJuno, ChainRPCJuno),
+			defaultEndpoint(ChainIdValueUptick, ChainIdAbbreviationUptick, ChainGRPCUptick, ChainRPCUptick),
+			defaultEndpoint(ChainIdValueOmniflix, ChainIdAbbreviationOmniflix, ChainGRPCOmniflix, ChainRPCOmnilfix),
+		},
+	}
+}
+
+func defaultEndpoint(chainId, abbreviation, grpc, rpc string) EndpointConfig {
+	return EndpointConfig{
+		ChainId:      chainId,
+		Abbreviation: abbreviation,
+		GRPC:         grpc,
+		RPC:          rpc,
+		Timeout:      DefaultTimeout,
+	}
+}
+
+// LoadConfig reads the endpoints from a yaml file on top of the default ones,
+// an entry of the file replaces the default entry with the same abbreviation or removes it if disabled.
+// The default config is returned if file is empty.
+func LoadConfig(file string) (*Config, error) {
+	cfg := DefaultConfig()
+	if len(file) == 0 {
+		return cfg, nil
+	}
+
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(bz))
+	dec.KnownFields(true)
+
+	var fc Config
+	if err := dec.Decode(&fc); err != nil {
+		return nil, fmt.Errorf("chain config %s: %w", file, err)
+	}
+	if len(fc.FixtureDir) != 0 {
+		cfg.FixtureDir = fc.FixtureDir
+	}
+	cfg.Offline = fc.Offline
+	for _, ec := range fc.Chains {
+		if ec.Disabled {
+			if _, ok := ChainNames[ec.Abbreviation]; !ok {
+				return nil, fmt.Errorf("chain config %s: chain %s: unknown abbreviation", file, ec.Abbreviation)
+			}
+			cfg.remove(ec.Abbreviation)
+			continue
+		}
+		if ec.Timeout == 0 {
+			ec.Timeout = DefaultTimeout
+		}
+		cfg.set(ec)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("chain config %s: %w", file, err)
+	}
+	return cfg, nil
+}
+
+// ApplyEnv overrides endpoints with GON_<NAME>_GRPC, GON_<NAME>_RPC, GON_<NAME>_PROOF_RPC, GON_<NAME>_TLS
+// and GON_<NAME>_TIMEOUT, e.g. GON_IRIS_GRPC=127.0.0.1:9090.
+func (c *Config) ApplyEnv() error {
+	for _, ec := range c.Chains {
+		name := strings.ToUpper(ChainNames[ec.Abbreviation])
+		for _, key := range []string{ConfigKeyGRPC, ConfigKeyRPC, ConfigKeyProofRPC, ConfigKeyTLS, ConfigKeyTimeout} {
+			value, ok := os.LookupEnv("GON_" + name + "_" + strings.ToUpper(key))
+			if !ok {
+				continue
+			}
+			if err := c.Override(ec.Abbreviation, key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyOverrides overrides one field of several chains, overrides are keyed by chain name or abbreviation.
+func (c *Config) ApplyOverrides(key string, overrides map[string]string) error {
+	for chain, value := range overrides {
+		if err := c.Override(chain, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Override sets one field of a chain endpoint, chain is either a name or an abbreviation.
+func (c *Config) Override(chain, key, value string) error {
+	ec := c.Get(chain)
+	if ec == nil {
+		return fmt.Errorf("chain %s not configured", chain)
+	}
+
+	switch key {
+	case ConfigKeyGRPC:
+		ec.GRPC = value
+	case ConfigKeyRPC:
+		ec.RPC = value
+	case ConfigKeyProofRPC:
+		ec.ProofRPC = value
+	case ConfigKeyTLS:
+		tls, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("chain %s: invalid tls %q", chain, value)
+		}
+		ec.TLS = tls
+	case ConfigKeyTimeout:
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("chain %s: invalid timeout %q", chain, value)
+		}
+		ec.Timeout = timeout
+	default:
+		return fmt.Errorf("unknown config key %s", key)
+	}
+	return nil
+}
+
+// Get returns the endpoint of a chain by name or abbreviation.
+func (c *Config) Get(chain string) *EndpointConfig {
+	for i := range c.Chains {
+		if c.Chains[i].Abbreviation == chain || ChainNames[c.Chains[i].Abbreviation] == chain {
+			return &c.Chains[i]
+		}
+	}
+	return nil
+}
+
+func (c *Config) set(ec EndpointConfig) {
+	for i := range c.Chains {
+		if c.Chains[i].Abbreviation == ec.Abbreviation {
+			c.Chains[i] = ec
+			return
+		}
+	}
+	c.Chains = append(c.Chains, ec)
+}
+
+func (c *Config) remove(abbreviation string) {
+	for i := range c.Chains {
+		if c.Chains[i].Abbreviation == abbreviation {
+			c.Chains = append(c.Chains[:i], c.Chains[i+1:]...)
+			return
+		}
+	}
+}
+
+// Validate checks every endpoint is complete.
+func (c *Config) Validate() error {
+	if len(c.Chains) == 0 {
+		return errors.New("no chain configured")
+	}
+	if c.Offline && len(c.FixtureDir) == 0 {
+		return errors.New("offline requires a fixture directory")
+	}
+	for _, ec := range c.Chains {
+		if err := ec.Validate(); err != nil {
+			return fmt.Errorf("chain %s: %w", ec.Abbreviation, err)
+		}
+	}
+	return nil
+}
+
+func (ec EndpointConfig) Validate() error {
+	if _, ok := ChainNames[ec.Abbreviation]; !ok {
+		return errors.New("unknown abbreviation")
+	}
+	if len(ec.ChainId) == 0 {
+		return errors.New("chain id is empty")
+	}
+	if len(ec.GRPC) == 0 {
+		return errors.New("grpc address is empty")
+	}
+	if !validURL(ec.RPC) {
+		return fmt.Errorf("invalid rpc url %q", ec.RPC)
+	}
+	if len(ec.ProofRPC) != 0 {
+		if !validURL(ec.ProofRPC) {
+			return fmt.Errorf("invalid proof rpc url %q", ec.ProofRPC)
+		}
+		if strings.TrimSuffix(ec.ProofRPC, "/") == strings.TrimSuffix(ec.RPC, "/") {
+			return errors.New("proof rpc must be another node than rpc")
+		}
+	}
+	if ec.Timeout < 0 {
+		return errors.New("timeout is negative")
+	}
+	return nil
+}
+
+func validURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && len(u.Host) != 0
+}
+
+// rpcBase returns the rpc url ending with a slash.
+func (ec EndpointConfig) rpcBase() string {
+	return strings.TrimSuffix(ec.RPC, "/") + "/"
+}
+
+// proofRPCBase returns the proof rpc url ending with a slash, empty if proofs aren't verified.
+func (ec EndpointConfig) proofRPCBase() string {
+	if len(ec.ProofRPC) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(ec.ProofRPC, "/") + "/"
+}
+
+// ics721Bridge returns the configured bridge contract or the one of the ports of the chain.
+func (ec EndpointConfig) ics721Bridge() string {
+	if len(ec.ICS721Bridge) != 0 {
+		return ec.ICS721Bridge
+	}
+	return ics721Bridge(ec.Abbreviation)
+}
@@ -0,0 +1,240 @@
+package chain
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taramakage/gon-verifier/internal/types"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "chains.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestDefaultConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	var abbrs []string
+	for _, ec := range cfg.Chains {
+		abbrs = append(abbrs, ec.Abbreviation)
+		if ec.Timeout != DefaultTimeout {
+			t.Errorf("chain %s: timeout: want %s, got %s", ec.Abbreviation, DefaultTimeout, ec.Timeout)
+		}
+	}
+	if !reflect.DeepEqual(abbrs, Abbreviations) {
+		t.Fatalf("chains: want %v, got %v", Abbreviations, abbrs)
+	}
+
+	loaded, err := LoadConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, cfg) {
+		t.Fatalf("no file: want the default config, got %+v", loaded)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		check   func(t *testing.T, cfg *Config)
+		wantErr string
+	}{
+		{
+			name: "entry replaces the default",
+			content: `
+fixture_dir: fixtures
+chains:
+  - { chain_id: gon-irishub-1, abbreviation: i, grpc: 127.0.0.1:9090, rpc: "http://127.0.0.1:26657/", tls: true }
+`,
+			check: func(t *testing.T, cfg *Config) {
+				want := EndpointConfig{ChainId: ChainIdValueIirs, Abbreviation: ChainIdAbbreviationIris, GRPC: "127.0.0.1:9090",
+					RPC: "http://127.0.0.1:26657/", TLS: true, Timeout: DefaultTimeout}
+				if got := *cfg.Get("iris"); !reflect.DeepEqual(got, want) {
+					t.Errorf("iris: want %+v, got %+v", want, got)
+				}
+				if got := *cfg.Get("stars"); !reflect.DeepEqual(got, DefaultConfig().Chains[1]) {
+					t.Errorf("stars: want the default endpoint, got %+v", got)
+				}
+				if len(cfg.Chains) != len(Abbreviations) || cfg.FixtureDir != "fixtures" {
+					t.Errorf("got %+v", cfg)
+				}
+			},
+		},
+		{
+			name: "timeout kept",
+			content: `
+chains:
+  - { chain_id: uni-6, abbreviation: j, grpc: "127.0.0.1:9090", rpc: "http://127.0.0.1:26657/", timeout: 5s }
+`,
+			check: func(t *testing.T, cfg *Config) {
+				if timeout := cfg.Get("j").Timeout; timeout != 5*time.Second {
+					t.Errorf("timeout: want 5s, got %s", timeout)
+				}
+			},
+		},
+		{
+			name: "chain left out",
+			content: `
+chains:
+  - { abbreviation: o, disabled: true }
+  - { abbreviation: u, disabled: true }
+`,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Get("omniflix") != nil || cfg.Get("u") != nil {
+					t.Errorf("omniflix and uptick: want left out, got %+v", cfg.Chains)
+				}
+				if len(cfg.Chains) != 3 {
+					t.Errorf("want 3 chains, got %d", len(cfg.Chains))
+				}
+			},
+		},
+		{
+			name:    "unknown chain left out",
+			content: "chains:\n  - { abbreviation: x, disabled: true }\n",
+			wantErr: "chain x: unknown abbreviation",
+		},
+		{
+			name: "every chain left out",
+			content: `
+chains:
+  - { abbreviation: i, disabled: true }
+  - { abbreviation: s, disabled: true }
+  - { abbreviation: j, disabled: true }
+  - { abbreviation: u, disabled: true }
+  - { abbreviation: o, disabled: true }
+`,
+			wantErr: "no chain configured",
+		},
+		{
+			name:    "unknown field",
+			content: "chainz: []\n",
+			wantErr: "field chainz not found",
+		},
+		{
+			name:    "invalid endpoint",
+			content: "chains:\n  - { chain_id: gon-irishub-1, abbreviation: i, grpc: 127.0.0.1:9090, rpc: 127.0.0.1 }\n",
+			wantErr: `chain i: invalid rpc url "127.0.0.1"`,
+		},
+		{
+			name:    "offline without fixtures",
+			content: "offline: true\n",
+			wantErr: "offline requires a fixture directory",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := LoadConfig(writeConfig(t, tc.content))
+			if len(tc.wantErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("want error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.check(t, cfg)
+		})
+	}
+
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("missing file: want error")
+	}
+}
+
+// TestConfigPrecedence applies the layers in the order of the command: file, GON_<NAME>_* env, flags.
+func TestConfigPrecedence(t *testing.T) {
+	file := writeConfig(t, `
+chains:
+  - { chain_id: gon-irishub-1, abbreviation: i, grpc: "file:9090", rpc: "http://file:26657/" }
+  - { chain_id: elgafar-1, abbreviation: s, grpc: "file:9090", rpc: "http://file:26657/" }
+`)
+	t.Setenv("GON_IRIS_GRPC", "env:9090")
+	t.Setenv("GON_IRIS_RPC", "http://env:26657/")
+	t.Setenv("GON_STARS_TLS", "true")
+	t.Setenv("GON_STARS_TIMEOUT", "10s")
+	t.Setenv("GON_JUNO_PROOF_RPC", "http://env-proof:26657/")
+
+	cfg, err := LoadConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iris := cfg.Get("i"); iris.GRPC != "file:9090" || iris.RPC != "http://file:26657/" {
+		t.Fatalf("file: got %+v", iris)
+	}
+
+	if err := cfg.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if iris := cfg.Get("i"); iris.GRPC != "env:9090" || iris.RPC != "http://env:26657/" {
+		t.Fatalf("env: got %+v", iris)
+	}
+	if stars := cfg.Get("s"); !stars.TLS || stars.Timeout != 10*time.Second || stars.GRPC != "file:9090" {
+		t.Fatalf("env: got %+v", stars)
+	}
+	if juno := cfg.Get("j"); juno.ProofRPC != "http://env-proof:26657/" || juno.GRPC != ChainGRPCJuno {
+		t.Fatalf("env over the default: got %+v", juno)
+	}
+
+	if err := cfg.ApplyOverrides(ConfigKeyGRPC, map[string]string{"iris": "flag:9090", "u": "flag:9090"}); err != nil {
+		t.Fatal(err)
+	}
+	if iris := cfg.Get("i"); iris.GRPC != "flag:9090" || iris.RPC != "http://env:26657/" {
+		t.Fatalf("flags: got %+v", iris)
+	}
+	if uptick := cfg.Get("uptick"); uptick.GRPC != "flag:9090" || uptick.RPC != ChainRPCUptick {
+		t.Fatalf("flags over the default: got %+v", uptick)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.ApplyOverrides(ConfigKeyGRPC, map[string]string{"x": "flag:9090"}); err == nil {
+		t.Fatal("unknown chain: want error")
+	}
+	t.Setenv("GON_IRIS_TIMEOUT", "soon")
+	if err := cfg.ApplyEnv(); err == nil || !strings.Contains(err.Error(), `invalid timeout "soon"`) {
+		t.Fatalf("invalid env: want error, got %v", err)
+	}
+}
+
+func TestConfigLeftOutChain(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "chains:\n  - { abbreviation: o, disabled: true }\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the env of a chain left out is ignored, its flags fail
+	t.Setenv("GON_OMNIFLIX_GRPC", "env:9090")
+	if err := cfg.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.ApplyOverrides(ConfigKeyRPC, map[string]string{"omniflix": "http://flag:26657/"}); err == nil {
+		t.Fatal("flag of a chain left out: want error")
+	}
+
+	cfg.FixtureDir, cfg.Offline = t.TempDir(), true
+	r, err := NewRegistry(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if len(r.GetChains()) != 4 {
+		t.Fatalf("want 4 chains, got %d", len(r.GetChains()))
+	}
+	if _, err := r.GetChain(ChainIdAbbreviationOmniflix).GetTx("ABCDEF", types.TxResultTypeBasic); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Fatalf("chain left out: want error, got %v", err)
+	}
+}
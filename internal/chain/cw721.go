@@ -0,0 +1,169 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	wasmtype "github.com/taramakage/gon-verifier/internal/types/wasm"
+)
+
+type (
+	// CW721 queries the cw721 contracts of a wasm chain.
+	CW721 interface {
+		OwnerOf(contract, tokenId string) (*CW721OwnerOf, error)
+		NftInfo(contract, tokenId string) (*CW721NftInfo, error)
+		AllNftInfo(contract, tokenId string) (*CW721AllNftInfo, error)
+		ContractInfo(contract string) (*CW721ContractInfo, error)
+		NumTokens(contract string) (uint64, error)
+	}
+
+	CW721OwnerOf struct {
+		Owner     string          `json:"owner"`
+		Approvals []CW721Approval `json:"approvals"`
+	}
+
+	CW721Approval struct {
+		Spender string          `json:"spender"`
+		Expires json.RawMessage `json:"expires,omitempty"`
+	}
+
+	CW721NftInfo struct {
+		TokenUri  string          `json:"token_uri"`
+		Extension json.RawMessage `json:"extension,omitempty"`
+	}
+
+	CW721AllNftInfo struct {
+		Access CW721OwnerOf `json:"access"`
+		Info   CW721NftInfo `json:"info"`
+	}
+
+	CW721ContractInfo struct {
+		Name   string `json:"name"`
+		Symbol string `json:"symbol"`
+	}
+
+	cw721NumTokens struct {
+		Count uint64 `json:"count"`
+	}
+
+	cw721TokenQuery struct {
+		TokenId string `json:"token_id"`
+	}
+
+	// cw721Client implements CW721 with smart queries, it is embedded by the wasm chains.
+	cw721Client struct {
+		wasmClient wasmtype.QueryClient
+		height     int64 // height of the queries, the latest if 0
+	}
+)
+
+var _ CW721 = cw721Client{}
+
+func (c cw721Client) OwnerOf(contract, tokenId string) (*CW721OwnerOf, error) {
+	var res CW721OwnerOf
+	err := c.query(contract, map[string]any{"owner_of": cw721TokenQuery{tokenId}}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c cw721Client) NftInfo(contract, tokenId string) (*CW721NftInfo, error) {
+	var res CW721NftInfo
+	err := c.query(contract, map[string]any{"nft_info": cw721TokenQuery{tokenId}}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c cw721Client) AllNftInfo(contract, tokenId string) (*CW721AllNftInfo, error) {
+	var res CW721AllNftInfo
+	err := c.query(contract, map[string]any{"all_nft_info": cw721TokenQuery{tokenId}}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c cw721Client) ContractInfo(contract string) (*CW721ContractInfo, error) {
+	var res CW721ContractInfo
+	err := c.query(contract, map[string]any{"contract_info": struct{}{}}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c cw721Client) NumTokens(contract string) (uint64, error) {
+	var res cw721NumTokens
+	err := c.query(contract, map[string]any{"num_tokens": struct{}{}}, &res)
+	if err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// getNFT maps all_nft_info of a token to an nft.
+func (c cw721Client) getNFT(contract, tokenId string) (*NFT, error) {
+	info, err := c.AllNftInfo(contract, tokenId)
+	if err != nil {
+		return nil, err
+	}
+
+	data := ""
+	if len(info.Info.Extension) != 0 && string(info.Info.Extension) != "null" {
+		data = string(info.Info.Extension)
+	}
+	return &NFT{
+		ID:    tokenId,
+		URI:   info.Info.TokenUri,
+		Data:  data,
+		Owner: info.Access.Owner,
+	}, nil
+}
+
+// getClass maps contract_info and num_tokens of a contract to a class.
+func (c cw721Client) getClass(contract string) (*Class, error) {
+	info, err := c.ContractInfo(contract)
+	if err != nil {
+		return nil, err
+	}
+	count, err := c.NumTokens(contract)
+	if err != nil {
+		return nil, err
+	}
+	return &Class{
+		ID:        contract,
+		Name:      info.Name,
+		Symbol:    info.Symbol,
+		NumTokens: count,
+	}, nil
+}
+
+// query sends a smart query to a contract and decodes the response into res.
+func (c cw721Client) query(contract string, msg, res any) error {
+	bz, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req := &wasmtype.QuerySmartContractStateRequest{
+		Address:   contract,
+		QueryData: bz,
+	}
+	resi, err := withGrpcRetry(func() (interface{}, error) {
+		return c.wasmClient.SmartContractState(heightContext(c.height), req)
+	})
+	if err != nil {
+		return err
+	}
+	resp, ok := resi.(*wasmtype.QuerySmartContractStateResponse)
+	if !ok {
+		return fmt.Errorf("unexpected response %T of contract %s", resi, contract)
+	}
+	if err := json.Unmarshal(resp.Data, res); err != nil {
+		return fmt.Errorf("decode response of contract %s: %w", contract, err)
+	}
+	return nil
+}
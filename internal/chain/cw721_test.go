@@ -0,0 +1,76 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	wasmtype "github.com/taramakage/gon-verifier/internal/types/wasm"
+)
+
+// fakeWasm answers smart queries of one cw721 contract by query name.
+type fakeWasm struct {
+	wasmtype.QueryClient
+	contract string
+	answers  map[string]string
+}
+
+func (w fakeWasm) SmartContractState(_ context.Context, in *wasmtype.QuerySmartContractStateRequest, _ ...grpc.CallOption) (*wasmtype.QuerySmartContractStateResponse, error) {
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(in.QueryData, &msg); err != nil {
+		return nil, err
+	}
+	for name := range msg {
+		if answer, ok := w.answers[name]; ok && in.Address == w.contract {
+			return &wasmtype.QuerySmartContractStateResponse{Data: []byte(answer)}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "not found")
+}
+
+func TestCW721(t *testing.T) {
+	c := cw721Client{wasmClient: fakeWasm{
+		contract: "stars1contract",
+		answers: map[string]string{
+			"owner_of":      `{"owner":"stars1owner","approvals":[]}`,
+			"nft_info":      `{"token_uri":"ipfs://nft","extension":{"name":"nft1"}}`,
+			"all_nft_info":  `{"access":{"owner":"stars1owner","approvals":[]},"info":{"token_uri":"ipfs://nft","extension":null}}`,
+			"contract_info": `{"name":"gon","symbol":"GON"}`,
+			"num_tokens":    `{"count":3}`,
+		},
+	}}
+
+	owner, err := c.OwnerOf("stars1contract", "nft1")
+	if err != nil || owner.Owner != "stars1owner" {
+		t.Fatalf("owner_of: unexpected %+v, %v", owner, err)
+	}
+	info, err := c.NftInfo("stars1contract", "nft1")
+	if err != nil || info.TokenUri != "ipfs://nft" || string(info.Extension) != `{"name":"nft1"}` {
+		t.Fatalf("nft_info: unexpected %+v, %v", info, err)
+	}
+
+	nft, err := c.getNFT("stars1contract", "nft1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&NFT{ID: "nft1", URI: "ipfs://nft", Owner: "stars1owner"}); !reflect.DeepEqual(nft, want) {
+		t.Errorf("want nft %+v, got %+v", want, nft)
+	}
+
+	class, err := c.getClass("stars1contract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&Class{ID: "stars1contract", Name: "gon", Symbol: "GON", NumTokens: 3}); !reflect.DeepEqual(class, want) {
+		t.Errorf("want class %+v, got %+v", want, class)
+	}
+
+	if _, err := c.getNFT("stars1other", "nft1"); err == nil {
+		t.Error("want error for unknown contract")
+	}
+}
@@ -0,0 +1,564 @@
+// Package fake implements chain.Chain in memory for deterministic tests.
+//
+// A Network holds one Chain per abbreviation. Its builders change the state of
+// the chains the way the real modules would and record the tx with the events
+// the verifiers read, so a scenario is written as the participant would have
+// played it: issue a denom, mint, then transfer over ics-721.
+package fake
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	proto "github.com/gogo/protobuf/proto"
+	nfttypes "github.com/irisnet/irismod/modules/nft/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/types"
+	"github.com/taramakage/gon-verifier/internal/types/wasm"
+)
+
+var (
+	ErrTxNotFound    = errors.New("tx not found")
+	ErrClassNotFound = errors.New("class not found")
+	ErrNftNotFound   = errors.New("nft not found")
+)
+
+// Chain is an in-memory chain, it serves every tx type whatever the chain it stands for.
+type Chain struct {
+	mu        sync.RWMutex
+	chainId   string
+	abbr      string
+	height    int64
+	seq       int
+	classes   map[string]chain.Class
+	nfts      map[string]map[string]chain.NFT
+	traces    map[string]string // ibc class id or cw721 contract -> full class trace
+	contracts map[string]string // full class trace -> cw721 contract, on wasm chains
+	txs       map[string]types.TxResponse
+	// heights of the versions of the nfts and of the classes, for the queries at a height
+	nftVersions  map[string]map[string][]nftVersion
+	classHeights map[string]int64
+	// proofs verifies the tx proofs against dataHashes, the headers of another node
+	proofs     bool
+	dataHashes map[int64][]byte
+	// feePayer pays the fees of the next txs, their signer if empty
+	feePayer string
+}
+
+var _ chain.Chain = (*Chain)(nil)
+var _ chain.ClassTracer = (*Chain)(nil)
+var _ chain.Collector = (*Chain)(nil)
+var _ chain.ICS721 = (*Chain)(nil)
+var _ chain.PacketTracer = (*Chain)(nil)
+var _ chain.TxSearcher = (*Chain)(nil)
+var _ chain.BlockTimer = (*Chain)(nil)
+
+// GenesisTime is the time of the block at height 0 of every chain, a block is produced every BlockInterval.
+var (
+	GenesisTime   = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	BlockInterval = 5 * time.Second
+)
+
+// NewChain creates an empty chain at height 1.
+func NewChain(chainId, abbr string) *Chain {
+	return &Chain{
+		chainId:      chainId,
+		abbr:         abbr,
+		height:       1,
+		classes:      make(map[string]chain.Class),
+		nfts:         make(map[string]map[string]chain.NFT),
+		traces:       make(map[string]string),
+		contracts:    make(map[string]string),
+		txs:          make(map[string]types.TxResponse),
+		dataHashes:   make(map[int64][]byte),
+		nftVersions:  make(map[string]map[string][]nftVersion),
+		classHeights: make(map[string]int64),
+	}
+}
+
+func (c *Chain) ChainId() string {
+	return c.chainId
+}
+
+func (c *Chain) Abbreviation() string {
+	return c.abbr
+}
+
+// SetHeight sets the height of the next tx.
+func (c *Chain) SetHeight(height int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.height = height
+}
+
+// Height returns the height of the next tx.
+func (c *Chain) Height() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.height
+}
+
+// AddClass stores a class without any tx.
+func (c *Chain) AddClass(class chain.Class) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setClass(class)
+}
+
+// AddNFT stores an nft without any tx.
+func (c *Chain) AddNFT(classID string, nft chain.NFT) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setNFT(classID, nft)
+}
+
+// AddTx stores a tx built by NewTxResponse, it doesn't change the state.
+func (c *Chain) AddTx(tx types.TxResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.txs[strings.ToUpper(tx.Result.Hash)] = tx
+}
+
+// PayFees makes payer pay the fees of the next txs, their signer pays them again if payer is empty.
+func (c *Chain) PayFees(payer string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.feePayer = payer
+}
+
+// Record stores a successful tx carrying msgs and events without changing the state, it returns the hash of the tx.
+func (c *Chain) Record(msgs []proto.Message, events ...Event) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.record(msgs, events...)
+}
+
+// SetTxCode overrides the result code of a recorded tx.
+func (c *Chain) SetTxCode(txHash string, code int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	txHash = strings.ToUpper(txHash)
+	tx, ok := c.txs[txHash]
+	if !ok {
+		return
+	}
+	tx.Result.TxResult.Code = code
+	c.txs[txHash] = tx
+}
+
+// VerifyProofs makes GetTx verify the proof of every tx against the data hash of its block, as a chain with a proof rpc.
+func (c *Chain) VerifyProofs() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.proofs = true
+}
+
+// SetDataHash overrides the data hash of the block of a recorded tx, as served by the node the proofs are verified against.
+func (c *Chain) SetDataHash(txHash string, dataHash []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tx, ok := c.txs[strings.ToUpper(txHash)]
+	if !ok {
+		return
+	}
+	c.dataHashes[tx.BlockHeight()] = dataHash
+}
+
+func (c *Chain) GetTx(txHash, txType string) (any, error) {
+	c.mu.RLock()
+	tx, ok := c.txs[strings.ToUpper(txHash)]
+	proofs, dataHash := c.proofs, c.dataHashes[tx.BlockHeight()]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTxNotFound, txHash)
+	}
+	if proofs {
+		if err := chain.VerifyTxProof(&tx, dataHash); err != nil {
+			return nil, err
+		}
+	}
+	return chain.ParseTxResult(&tx, txType)
+}
+
+func (c *Chain) GetNFT(classID, nftID string) (*chain.NFT, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	nft, ok := c.nfts[classID][nftID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNftNotFound, classID, nftID)
+	}
+	return &nft, nil
+}
+
+func (c *Chain) HasNFT(classID, nftID string) bool {
+	nft, _ := c.GetNFT(classID, nftID)
+	return nft != nil
+}
+
+func (c *Chain) GetClass(classID string) (*chain.Class, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	class, ok := c.classes[classID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrClassNotFound, classID)
+	}
+	return &class, nil
+}
+
+func (c *Chain) HasClass(classID string) bool {
+	class, _ := c.GetClass(classID)
+	return class != nil
+}
+
+func (c *Chain) GetOriginalClassId(ibcClassId string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	trace, ok := c.traces[ibcClassId]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrClassNotFound, ibcClassId)
+	}
+	elements := strings.Split(trace, "/")
+	return elements[len(elements)-1], nil
+}
+
+func (c *Chain) NftContract(classId string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	contract, ok := c.contracts[classId]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrClassNotFound, classId)
+	}
+	return contract, nil
+}
+
+func (c *Chain) ClassId(contract string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	trace, ok := c.traces[contract]
+	if !ok || c.contracts[trace] != contract {
+		return "", fmt.Errorf("%w: %s", ErrClassNotFound, contract)
+	}
+	return trace, nil
+}
+
+func (c *Chain) GetPacketTx(event string, p chain.Packet) (*types.TxResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sequence := strconv.FormatUint(p.Sequence, 10)
+	for _, tx := range c.txs {
+		tx := tx
+		if tx.EventAttributeValueByKey(event, types.AttributeKeySequence) == sequence &&
+			tx.EventAttributeValueByKey(event, types.AttributeKeySrcPort) == p.SrcPort &&
+			tx.EventAttributeValueByKey(event, types.AttributeKeySrcChan) == p.SrcChan {
+			return &tx, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s/%s/%s", chain.ErrPacketTxNotFound, event, p.SrcPort, p.SrcChan, sequence)
+}
+
+// SearchTxs supports the conditions "<event>.<key>=<value>", with the value quoted or not,
+// and "<event>.<key> EXISTS" joined by AND.
+func (c *Chain) SearchTxs(query string) ([]types.TxResponse, error) {
+	type condition struct {
+		event, key, value string
+		exists            bool
+	}
+	var conds []condition
+	for _, part := range strings.Split(query, " AND ") {
+		part = strings.TrimSpace(part)
+		var cond condition
+		attr, value, ok := strings.Cut(part, "=")
+		if !ok {
+			if !strings.HasSuffix(part, " EXISTS") {
+				return nil, fmt.Errorf("unsupported condition %q", part)
+			}
+			attr = strings.TrimSuffix(part, " EXISTS")
+			cond.exists = true
+		}
+		cond.event, cond.key, ok = strings.Cut(attr, ".")
+		if !ok {
+			return nil, fmt.Errorf("unsupported condition %q", part)
+		}
+		cond.value = strings.Trim(value, "'")
+		conds = append(conds, cond)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var txs []types.TxResponse
+	for _, tx := range c.txs {
+		matched := true
+		for _, cond := range conds {
+			if !hasAttribute(tx, cond.event, cond.key, cond.value, cond.exists) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			txs = append(txs, tx)
+		}
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].BlockHeight() < txs[j].BlockHeight()
+	})
+	return txs, nil
+}
+
+// hasAttribute tells whether an event of a tx has the attribute key with value, any value if exists is set.
+func hasAttribute(tx types.TxResponse, event, key, value string, exists bool) bool {
+	for _, e := range tx.Result.TxResult.Events {
+		if e.Type != event {
+			continue
+		}
+		for _, attr := range e.Attributes {
+			k, _ := base64.StdEncoding.DecodeString(attr.Key)
+			v, _ := base64.StdEncoding.DecodeString(attr.Value)
+			if string(k) == key && (exists || string(v) == value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *Chain) GetCollection(classID string) (*nfttypes.QueryCollectionResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	class, ok := c.classes[classID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrClassNotFound, classID)
+	}
+
+	tokenIds := make([]string, 0, len(c.nfts[classID]))
+	for id := range c.nfts[classID] {
+		tokenIds = append(tokenIds, id)
+	}
+	sort.Strings(tokenIds)
+
+	nfts := make([]nfttypes.BaseNFT, 0, len(tokenIds))
+	for _, id := range tokenIds {
+		nft := c.nfts[classID][id]
+		nfts = append(nfts, nfttypes.BaseNFT{
+			Id:      nft.ID,
+			Name:    nft.Name,
+			URI:     nft.URI,
+			Data:    nft.Data,
+			Owner:   nft.Owner,
+			UriHash: nft.URIHash,
+		})
+	}
+
+	return &nfttypes.QueryCollectionResponse{
+		Collection: &nfttypes.Collection{
+			Denom: nfttypes.Denom{
+				Id:      class.ID,
+				Name:    class.Name,
+				Schema:  class.Schema,
+				Creator: class.Creator,
+				Uri:     class.Uri,
+				UriHash: class.UriHash,
+				Data:    class.Data,
+			},
+			NFTs: nfts,
+		},
+	}, nil
+}
+
+func (c *Chain) BlockTime(height int64) (time.Time, error) {
+	return GenesisTime.Add(time.Duration(height) * BlockInterval), nil
+}
+
+func (c *Chain) AtHeight(height int64) chain.Chain {
+	if height <= 0 {
+		return c
+	}
+	return &pinnedChain{Chain: c, height: height}
+}
+
+func (c *Chain) Close() {}
+
+// IssueDenom stores the class and returns the hash of the issue_denom tx sent by sender.
+func (c *Chain) IssueDenom(class chain.Class, sender string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setClass(class)
+	msg := &nfttypes.MsgIssueDenom{Id: class.ID, Name: class.Name, Sender: sender, Uri: class.Uri, Data: class.Data}
+	return c.record([]proto.Message{msg},
+		messageEvent(sender),
+		NewEvent(types.EventTypeIssueDenom,
+			types.AttributeDenomId, class.ID,
+			types.AttributeDenomName, class.Name,
+			types.AttributeDenomCreator, class.Creator,
+		),
+	)
+}
+
+// MintNFT stores the nft and returns the hash of the mint_nft tx sent by sender, the nft owner is the recipient.
+func (c *Chain) MintNFT(classID string, nft chain.NFT, sender string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setNFT(classID, nft)
+	msg := &nfttypes.MsgMintNFT{Id: nft.ID, DenomId: classID, URI: nft.URI, Data: nft.Data, Sender: sender, Recipient: nft.Owner}
+	return c.record([]proto.Message{msg},
+		messageEvent(sender),
+		NewEvent(types.EventTypeNftMint,
+			types.AttributeKeyTokenId, nft.ID,
+			types.AttributeDenomId, classID,
+			types.AttributeKeyRecipient, nft.Owner,
+		),
+	)
+}
+
+// TransferNFT changes the owner of an nft and returns the hash of the transfer_nft tx,
+// the tx executes transfer_nft on the cw721 contract of the class on wasm chains.
+func (c *Chain) TransferNFT(classID, nftID, sender, recipient string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nft, ok := c.nfts[classID][nftID]; ok {
+		nft.Owner = recipient
+		c.setNFT(classID, nft)
+	}
+	var msg proto.Message = &nfttypes.MsgTransferNFT{Id: nftID, DenomId: classID, Sender: sender, Recipient: recipient}
+	if trace, ok := c.traces[classID]; ok && c.contracts[trace] == classID {
+		msg = &wasm.MsgExecuteContract{
+			Sender:   sender,
+			Contract: classID,
+			Msg:      []byte(fmt.Sprintf(`{"transfer_nft":{"recipient":%q,"token_id":%q}}`, recipient, nftID)),
+		}
+	}
+	return c.record([]proto.Message{msg},
+		messageEvent(sender),
+		NewEvent(types.EventTypeNftTransfer,
+			types.AttributeKeyTokenId, nftID,
+			types.AttributeDenomId, classID,
+			types.AttributeKeySender, sender,
+			types.AttributeKeyRecipient, recipient,
+		),
+	)
+}
+
+func (c *Chain) setClass(class chain.Class) {
+	c.classes[class.ID] = class
+	if _, ok := c.classHeights[class.ID]; !ok {
+		c.classHeights[class.ID] = c.height
+	}
+}
+
+func (c *Chain) setNFT(classID string, nft chain.NFT) {
+	if _, ok := c.nfts[classID]; !ok {
+		c.nfts[classID] = make(map[string]chain.NFT)
+	}
+	c.nfts[classID][nft.ID] = nft
+	c.addNFTVersion(classID, nft.ID, &nft)
+}
+
+func (c *Chain) deleteNFT(classID, nftID string) {
+	delete(c.nfts[classID], nftID)
+	c.addNFTVersion(classID, nftID, nil)
+}
+
+// addNFTVersion records the nft as of the current height, nil once it left the chain.
+func (c *Chain) addNFTVersion(classID, nftID string, nft *chain.NFT) {
+	if _, ok := c.nftVersions[classID]; !ok {
+		c.nftVersions[classID] = make(map[string][]nftVersion)
+	}
+	c.nftVersions[classID][nftID] = append(c.nftVersions[classID][nftID], nftVersion{height: c.height, nft: nft})
+}
+
+// classTrace returns the full class trace of a class on the chain.
+func (c *Chain) classTrace(classID string) string {
+	if trace, ok := c.traces[classID]; ok {
+		return trace
+	}
+	return classID
+}
+
+// record stores a successful tx carrying msgs at the current height, the height is bumped afterwards.
+// The txs relaying packets carry no msg. The tx is the only one of its block, so its proof is the data hash of the block.
+func (c *Chain) record(msgs []proto.Message, events ...Event) string {
+	c.seq++
+	tx := Tx{Msgs: msgs, FeePayer: c.feePayer, FeeDenom: "fee" + c.abbr, Sequence: uint64(c.seq)}
+	txs := tmtypes.Txs{tx.Encode()}
+	hash := fmt.Sprintf("%X", txs[0].Hash())
+
+	res := NewTxResponse(hash, c.height, 0, events...)
+	proof := txs.Proof(0)
+	dataHash := txs.Hash()
+	res.Result.Tx = base64.StdEncoding.EncodeToString(txs[0])
+	res.Result.Proof.RootHash = fmt.Sprintf("%X", proof.RootHash)
+	res.Result.Proof.Data = res.Result.Tx
+	res.Result.Proof.Proof.Total = strconv.FormatInt(proof.Proof.Total, 10)
+	res.Result.Proof.Proof.Index = strconv.FormatInt(proof.Proof.Index, 10)
+	res.Result.Proof.Proof.LeafHash = base64.StdEncoding.EncodeToString(proof.Proof.LeafHash)
+	res.Result.Proof.Proof.Aunts = []any{}
+
+	c.txs[hash] = res
+	c.dataHashes[c.height] = dataHash
+	c.height++
+	return hash
+}
+
+func messageEvent(sender string) Event {
+	return NewEvent(types.EventTypeMessage, types.AttributeMsgSender, sender)
+}
+
+// nftVersion is an nft as of a height, nil once it left the chain.
+type nftVersion struct {
+	height int64
+	nft    *chain.NFT
+}
+
+// pinnedChain serves the nfts and classes of a chain as of a height, the state changed by the txs after it is ignored.
+type pinnedChain struct {
+	*Chain
+	height int64
+}
+
+func (p *pinnedChain) GetNFT(classID, nftID string) (*chain.NFT, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var nft *chain.NFT
+	for _, v := range p.nftVersions[classID][nftID] {
+		if v.height > p.height {
+			break
+		}
+		nft = v.nft
+	}
+	if nft == nil {
+		return nil, fmt.Errorf("%w: %s/%s at height %d", ErrNftNotFound, classID, nftID, p.height)
+	}
+	pinned := *nft
+	return &pinned, nil
+}
+
+func (p *pinnedChain) HasNFT(classID, nftID string) bool {
+	nft, _ := p.GetNFT(classID, nftID)
+	return nft != nil
+}
+
+func (p *pinnedChain) GetClass(classID string) (*chain.Class, error) {
+	p.mu.RLock()
+	height, ok := p.classHeights[classID]
+	p.mu.RUnlock()
+	if !ok || height > p.height {
+		return nil, fmt.Errorf("%w: %s at height %d", ErrClassNotFound, classID, p.height)
+	}
+	return p.Chain.GetClass(classID)
+}
+
+func (p *pinnedChain) HasClass(classID string) bool {
+	class, _ := p.GetClass(classID)
+	return class != nil
+}
+
+func (p *pinnedChain) AtHeight(height int64) chain.Chain {
+	return p.Chain.AtHeight(height)
+}
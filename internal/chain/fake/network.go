@@ -0,0 +1,277 @@
+package fake
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	proto "github.com/gogo/protobuf/proto"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/types"
+	"github.com/taramakage/gon-verifier/internal/types/ics721"
+	"github.com/taramakage/gon-verifier/internal/types/wasm"
+)
+
+// Network is the set of GoN chains connected by the channels of chain.PortChanPairStrMap.
+type Network struct {
+	chains map[string]*Chain
+	seq    uint64
+}
+
+// NewNetwork creates the five GoN chains.
+func NewNetwork() *Network {
+	n := &Network{chains: make(map[string]*Chain)}
+	for abbr, chainId := range map[string]string{
+		chain.ChainIdAbbreviationIris:     chain.ChainIdValueIirs,
+		chain.ChainIdAbbreviationStars:    chain.ChainIdValueStars,
+		chain.ChainIdAbbreviationJuno:     chain.ChainIdValueJuno,
+		chain.ChainIdAbbreviationUptick:   chain.ChainIdValueUptick,
+		chain.ChainIdAbbreviationOmniflix: chain.ChainIdValueOmniflix,
+	} {
+		n.chains[abbr] = NewChain(chainId, abbr)
+	}
+	return n
+}
+
+// Chain returns a chain by abbreviation.
+func (n *Network) Chain(abbr string) *Chain {
+	return n.chains[abbr]
+}
+
+// Registry returns a chain.Registry serving the network.
+func (n *Network) Registry() *chain.Registry {
+	chains := make(map[string]chain.Chain, len(n.chains))
+	for abbr, c := range n.chains {
+		chains[abbr] = c
+	}
+	return chain.NewRegistryFromChains(chains)
+}
+
+// Transfer sends an nft from src to dest over the channel pair pairId of chain.PortChanPairStrMap,
+// e.g. Transfer("i", "s", "1", ...) uses the pair 1 from i to s. The nft leaves src and is received by receiver on dest,
+// the packet is received on dest with a successful ack which is relayed back to src.
+// It returns the hash of the send tx on src and the class id of the nft on dest, a cw721 contract on wasm chains.
+func (n *Network) Transfer(src, dest, pairId, classID, nftID, sender, receiver string) (string, string) {
+	return n.TransferBatch(src, dest, pairId, classID, []string{nftID}, sender, receiver)
+}
+
+// TransferBatch sends several nfts of a class like Transfer in a single packet.
+func (n *Network) TransferBatch(src, dest, pairId, classID string, nftIDs []string, sender, receiver string) (string, string) {
+	t := n.send(src, dest, pairId, classID, nftIDs, sender, receiver)
+	destClassID := t.receive(successAck)
+	t.acknowledge(successAck)
+	return t.hash, destClassID
+}
+
+// TransferTimeout sends an nft like Transfer but the packet times out, the nft is refunded to sender on src.
+// It returns the hash of the send tx on src.
+func (n *Network) TransferTimeout(src, dest, pairId, classID, nftID, sender, receiver string) string {
+	t := n.send(src, dest, pairId, classID, []string{nftID}, sender, receiver)
+	t.timeout()
+	return t.hash
+}
+
+// TransferAckError sends an nft like Transfer but dest fails to receive it with ackErr,
+// the nft is refunded to sender on src once the ack is relayed. It returns the hash of the send tx on src.
+func (n *Network) TransferAckError(src, dest, pairId, classID, nftID, sender, receiver, ackErr string) string {
+	t := n.send(src, dest, pairId, classID, []string{nftID}, sender, receiver)
+	bz, err := json.Marshal(types.IbcAck{Error: ackErr})
+	if err != nil {
+		panic(err)
+	}
+	t.receive(string(bz))
+	t.acknowledge(string(bz))
+	return t.hash
+}
+
+// successAck is the ack of a packet received by the ics721 module, the base64 of 0x01.
+const successAck = `{"result":"AQ=="}`
+
+// transfer is a batch of nfts sent over ibc but not acknowledged yet.
+type transfer struct {
+	src, dest        *Chain
+	spc, dpc         chain.PortChan
+	sequence         uint64
+	classID, trace   string
+	class            chain.Class
+	nfts             []chain.NFT
+	sender, receiver string
+	hash             string
+}
+
+// send records the send_packet tx on src, the nfts leave src.
+func (n *Network) send(src, dest, pairId, classID string, nftIDs []string, sender, receiver string) *transfer {
+	pcp, err := chain.NewPortChanPair(src, dest, pairId)
+	if err != nil {
+		panic(fmt.Sprintf("transfer %s%s-%s: %v", src, dest, pairId, err))
+	}
+	n.seq++
+	t := &transfer{
+		src:      n.chains[src],
+		dest:     n.chains[dest],
+		spc:      pcp.GetSrcPortChan(),
+		dpc:      pcp.GetDestPortChan(),
+		sequence: n.seq,
+		classID:  classID,
+		sender:   sender,
+		receiver: receiver,
+	}
+
+	s := t.src
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.trace = s.classTrace(classID)
+	t.class = s.classes[classID]
+	packet := types.IbcNftPacket{
+		ClassId:   t.trace,
+		ClassUri:  t.class.Uri,
+		ClassData: t.class.Data,
+		Sender:    sender,
+		Receiver:  receiver,
+	}
+	for _, nftID := range nftIDs {
+		nft := s.nfts[classID][nftID]
+		nft.ID = nftID
+		s.deleteNFT(classID, nftID)
+		t.nfts = append(t.nfts, nft)
+		packet.TokenIds = append(packet.TokenIds, nftID)
+		packet.TokenUris = append(packet.TokenUris, nft.URI)
+		packet.TokenData = append(packet.TokenData, base64.StdEncoding.EncodeToString([]byte(nft.Data)))
+	}
+	bz, err := json.Marshal(packet)
+	if err != nil {
+		panic(err)
+	}
+	t.hash = s.record(t.sendMsgs(nftIDs),
+		messageEvent(sender),
+		t.packetEvent(types.EventTypeIbcSendPacket, types.AttributeKeyIbcPackageData, string(bz)),
+	)
+	return t
+}
+
+// sendMsgs returns the msgs sending the nfts: an ics721 transfer, or send_nft of each nft to the ics721 bridge
+// executed on the cw721 contract of the class on wasm chains.
+func (t *transfer) sendMsgs(nftIDs []string) []proto.Message {
+	bridge := strings.TrimPrefix(t.spc.Port, "wasm.")
+	if bridge == t.spc.Port {
+		return []proto.Message{&ics721.MsgTransfer{
+			SourcePort:    t.spc.Port,
+			SourceChannel: t.spc.Channel,
+			ClassId:       t.trace,
+			TokenIds:      nftIDs,
+			Sender:        t.sender,
+			Receiver:      t.receiver,
+		}}
+	}
+	msgs := make([]proto.Message, 0, len(nftIDs))
+	for _, nftID := range nftIDs {
+		ibcMsg := fmt.Sprintf(`{"receiver":%q,"channel_id":%q}`, t.receiver, t.spc.Channel)
+		msgs = append(msgs, &wasm.MsgExecuteContract{
+			Sender:   t.sender,
+			Contract: t.classID,
+			Msg: []byte(fmt.Sprintf(`{"send_nft":{"contract":%q,"token_id":%q,"msg":%q}}`,
+				bridge, nftID, base64.StdEncoding.EncodeToString([]byte(ibcMsg)))),
+		})
+	}
+	return msgs
+}
+
+// receive records the recv_packet tx on dest writing ack, the nfts are received by receiver if the ack is a success.
+// It returns the class id of the nft on dest.
+func (t *transfer) receive(ack string) string {
+	d := t.dest
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.record(nil,
+		t.packetEvent(types.EventTypeIbcRecvPacket),
+		t.packetEvent(types.EventTypeIbcWriteAck, types.AttributeKeyPacketAck, ack),
+	)
+	if ack != successAck {
+		return ""
+	}
+
+	// the class goes back through the channel it came from, or one more hop is prefixed
+	destTrace := t.dpc.Port + "/" + t.dpc.Channel + "/" + t.trace
+	if prefix := t.spc.Port + "/" + t.spc.Channel + "/"; strings.HasPrefix(t.trace, prefix) {
+		destTrace = strings.TrimPrefix(t.trace, prefix)
+	}
+
+	destClassID := destTrace
+	if strings.Contains(destTrace, "/") {
+		destClassID = IbcClassId(destTrace)
+		if bridge := strings.TrimPrefix(t.dpc.Port, "wasm."); bridge != t.dpc.Port {
+			// the ics721 bridge of a wasm chain instantiates a cw721 contract per class
+			destClassID = cw721Contract(bridge, destTrace)
+			d.contracts[destTrace] = destClassID
+		}
+		d.traces[destClassID] = destTrace
+	}
+	if _, ok := d.classes[destClassID]; !ok {
+		d.setClass(chain.Class{
+			ID:   destClassID,
+			Uri:  t.class.Uri,
+			Data: t.class.Data,
+		})
+	}
+	for _, nft := range t.nfts {
+		nft.Owner = t.receiver
+		d.setNFT(destClassID, nft)
+	}
+	return destClassID
+}
+
+// acknowledge records the acknowledge_packet tx on src, the nfts are refunded if dest failed to receive them.
+func (t *transfer) acknowledge(ack string) {
+	s := t.src
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.record(nil, t.packetEvent(types.EventTypeIbcAckPacket))
+	if ack != successAck {
+		t.refund()
+	}
+}
+
+// timeout records the timeout_packet tx on src and refunds the nfts.
+func (t *transfer) timeout() {
+	s := t.src
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.record(nil, t.packetEvent(types.EventTypeIbcTimeoutPacket))
+	t.refund()
+}
+
+// refund gives the nfts back to sender on src, the lock of src must be held.
+func (t *transfer) refund() {
+	for _, nft := range t.nfts {
+		nft.Owner = t.sender
+		t.src.setNFT(t.classID, nft)
+	}
+}
+
+// packetEvent builds an event of the packet lifecycle carrying the packet attributes and kvs.
+func (t *transfer) packetEvent(typ string, kvs ...string) Event {
+	return NewEvent(typ, append([]string{
+		types.AttributeKeySequence, strconv.FormatUint(t.sequence, 10),
+		types.AttributeKeySrcPort, t.spc.Port,
+		types.AttributeKeySrcChan, t.spc.Channel,
+		types.AttributeKeyDestPort, t.dpc.Port,
+		types.AttributeKeyDestChan, t.dpc.Channel,
+	}, kvs...)...)
+}
+
+// cw721Contract returns the address of the contract instantiated by a bridge for a class trace.
+func cw721Contract(bridge, trace string) string {
+	hrp, _, _ := strings.Cut(bridge, "1")
+	sum := sha256.Sum256([]byte(bridge + "/" + trace))
+	return fmt.Sprintf("%s1%x", hrp, sum[:20])
+}
+
+// IbcClassId returns the ibc class id of a full class trace as computed by chain.Flow.
+func IbcClassId(trace string) string {
+	sum := sha256.Sum256([]byte(trace))
+	return fmt.Sprintf("ibc/%X", sum[:])
+}
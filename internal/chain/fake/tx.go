@@ -0,0 +1,128 @@
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	proto "github.com/gogo/protobuf/proto"
+
+	"github.com/taramakage/gon-verifier/internal/types"
+)
+
+type (
+	Attribute struct {
+		Key   string
+		Value string
+	}
+
+	Event struct {
+		Type       string
+		Attributes []Attribute
+	}
+
+	// Tx is a tx to encode, the fee denom and the sequence of the signer make its bytes unique.
+	Tx struct {
+		Msgs     []proto.Message
+		Memo     string
+		FeePayer string // the signer of the first msg if empty
+		FeeDenom string
+		Sequence uint64
+	}
+)
+
+// Encode returns the bytes of the tx as broadcast, the signatures are left empty.
+func (tx Tx) Encode() []byte {
+	body := txtypes.TxBody{Memo: tx.Memo}
+	for _, msg := range tx.Msgs {
+		any, err := codectypes.NewAnyWithValue(msg)
+		if err != nil {
+			panic(err)
+		}
+		body.Messages = append(body.Messages, any)
+	}
+	authInfo := txtypes.AuthInfo{
+		SignerInfos: []*txtypes.SignerInfo{{Sequence: tx.Sequence}},
+		Fee: &txtypes.Fee{
+			Amount:   sdk.Coins{{Denom: tx.FeeDenom, Amount: sdk.NewInt(1)}},
+			GasLimit: 200000,
+			Payer:    tx.FeePayer,
+		},
+	}
+
+	bodyBytes, err := body.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	authInfoBytes, err := authInfo.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	bz, err := (&txtypes.TxRaw{BodyBytes: bodyBytes, AuthInfoBytes: authInfoBytes, Signatures: [][]byte{{}}}).Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// NewEvent builds an event from key value pairs.
+func NewEvent(typ string, kvs ...string) Event {
+	e := Event{Type: typ}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		e.Attributes = append(e.Attributes, Attribute{Key: kvs[i], Value: kvs[i+1]})
+	}
+	return e
+}
+
+// NewTxResponse builds the response of the rpc tx endpoint, attributes are base64 encoded as the nodes do.
+func NewTxResponse(hash string, height int64, code int, events ...Event) types.TxResponse {
+	type attribute struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+		Index bool   `json:"index"`
+	}
+	type event struct {
+		Type       string      `json:"type"`
+		Attributes []attribute `json:"attributes"`
+	}
+
+	evs := make([]event, 0, len(events))
+	for _, e := range events {
+		ev := event{Type: e.Type}
+		for _, attr := range e.Attributes {
+			ev.Attributes = append(ev.Attributes, attribute{
+				Key:   base64.StdEncoding.EncodeToString([]byte(attr.Key)),
+				Value: base64.StdEncoding.EncodeToString([]byte(attr.Value)),
+				Index: true,
+			})
+		}
+		evs = append(evs, ev)
+	}
+
+	raw := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      -1,
+		"result": map[string]any{
+			"hash":   hash,
+			"height": strconv.FormatInt(height, 10),
+			"tx_result": map[string]any{
+				"code":   code,
+				"events": evs,
+			},
+		},
+	}
+
+	// the response has anonymous nested structs, round trip json rather than building them
+	bz, err := json.Marshal(raw)
+	if err != nil {
+		panic(err)
+	}
+	var tx types.TxResponse
+	if err := json.Unmarshal(bz, &tx); err != nil {
+		panic(err)
+	}
+	return tx
+}
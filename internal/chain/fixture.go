@@ -0,0 +1,217 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var ErrFixtureNotFound = errors.New("fixture not found")
+
+type (
+	// FixtureStore records every rpc and grpc response of the chains in a directory,
+	// and serves them instead of the nodes when offline.
+	// A fixture is addressed by the sha256 of the chain id and the request, so that
+	// fixtures stay valid when the endpoints change.
+	FixtureStore struct {
+		dir     string
+		offline bool
+	}
+
+	fixture struct {
+		Request string `json:"request"`
+		Data    []byte `json:"data,omitempty"`
+		Code    uint32 `json:"code,omitempty"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	fixtureTransport struct {
+		fs      *FixtureStore
+		chainId string
+		next    http.RoundTripper
+	}
+)
+
+// NewFixtureStore creates a store in dir, responses are only read from dir if offline.
+func NewFixtureStore(dir string, offline bool) (*FixtureStore, error) {
+	if len(dir) == 0 {
+		return nil, errors.New("fixture directory is empty")
+	}
+	if offline {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, err
+		}
+	}
+	return &FixtureStore{dir: dir, offline: offline}, nil
+}
+
+func (fs *FixtureStore) Offline() bool {
+	return fs.offline
+}
+
+func (fs *FixtureStore) path(chainId, request string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(chainId))
+	h.Write([]byte{0})
+	h.Write([]byte(request))
+	h.Write([]byte{0})
+	h.Write(body)
+	return filepath.Join(fs.dir, chainId, hex.EncodeToString(h.Sum(nil))+".json")
+}
+
+func (fs *FixtureStore) load(chainId, request string, body []byte) (*fixture, error) {
+	bz, err := os.ReadFile(fs.path(chainId, request, body))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s", ErrFixtureNotFound, chainId, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f fixture
+	if err := json.Unmarshal(bz, &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (fs *FixtureStore) save(chainId, request string, body []byte, f *fixture) error {
+	file := fs.path(chainId, request, body)
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	bz, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	// participants verified concurrently may record the same response, rename so a reader never sees a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if _, err := tmp.Write(bz); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// httpClient returns a client recording to or replaying from the store, a nil store queries the node only.
+func (fs *FixtureStore) httpClient(cfg EndpointConfig) *http.Client {
+	client := &http.Client{Timeout: cfg.Timeout}
+	if fs != nil {
+		client.Transport = &fixtureTransport{
+			fs:      fs,
+			chainId: cfg.ChainId,
+			next:    http.DefaultTransport,
+		}
+	}
+	return client
+}
+
+// RoundTrip keys a request by its path and query, the host is left out.
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	request := req.Method + " " + req.URL.RequestURI()
+
+	if t.fs.offline {
+		f, err := t.fs.load(t.chainId, request, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        make(http.Header),
+			Body:          io.NopCloser(bytes.NewReader(f.Data)),
+			ContentLength: int64(len(f.Data)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := t.fs.save(t.chainId, request, nil, &fixture{Request: request, Data: body}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// interceptor records grpc replies, a NotFound or InvalidArgument status is recorded as well
+// as it is an answer of the node rather than a failure to reach it. A query at a height is keyed by its height too.
+func (fs *FixtureStore) interceptor(chainId string) grpc.UnaryClientInterceptor {
+	codec := encoding.GetCodec("proto")
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		reqBz, err := codec.Marshal(req)
+		if err != nil {
+			return err
+		}
+		request := method
+		if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(GRPCBlockHeightHeader)) != 0 {
+			request += "@" + md.Get(GRPCBlockHeightHeader)[0]
+		}
+
+		if fs.offline {
+			f, err := fs.load(chainId, request, reqBz)
+			if err != nil {
+				return status.Error(codes.FailedPrecondition, err.Error())
+			}
+			if f.Code != 0 {
+				return status.Error(codes.Code(f.Code), f.Error)
+			}
+			return codec.Unmarshal(f.Data, reply)
+		}
+
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		switch status.Code(err) {
+		case codes.OK:
+			data, err := codec.Marshal(reply)
+			if err != nil {
+				return err
+			}
+			return fs.save(chainId, request, reqBz, &fixture{Request: request, Data: data})
+		case codes.NotFound, codes.InvalidArgument:
+			s := status.Convert(err)
+			if err := fs.save(chainId, request, reqBz, &fixture{Request: request, Code: uint32(s.Code()), Error: s.Message()}); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+}
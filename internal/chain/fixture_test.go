@@ -0,0 +1,123 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	wasmtype "github.com/taramakage/gon-verifier/internal/types/wasm"
+)
+
+func TestFixtureRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	cfg := EndpointConfig{ChainId: "gon-irishub-1", Timeout: time.Second}
+	get := func(client *http.Client, url string) (string, error) {
+		resp, err := client.Get(url)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		bz, err := io.ReadAll(resp.Body)
+		return string(bz), err
+	}
+
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"request":%q}`, r.URL.RequestURI())
+	}))
+	online, err := NewFixtureStore(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := get(online.httpClient(cfg), node.URL+"/block?height=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.Close()
+
+	offline, err := NewFixtureStore(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the host is left out of the key, the node may move
+	replayed, err := get(offline.httpClient(cfg), "http://127.0.0.1:1/block?height=1")
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayed != recorded {
+		t.Errorf("want %s, got %s", recorded, replayed)
+	}
+	if _, err := get(offline.httpClient(cfg), "http://127.0.0.1:1/block?height=2"); !errors.Is(err, ErrFixtureNotFound) {
+		t.Errorf("want %v, got %v", ErrFixtureNotFound, err)
+	}
+
+	// a NotFound answer of the node is replayed as well
+	method := "/cosmwasm.wasm.v1.Query/SmartContractState"
+	req := &wasmtype.QuerySmartContractStateRequest{Address: "juno1contract", QueryData: []byte(`{}`)}
+	notFound := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return status.Error(codes.NotFound, "no such contract")
+	}
+	if err := online.interceptor("juno-1")(context.Background(), method, req, &wasmtype.QuerySmartContractStateResponse{}, nil, notFound); status.Code(err) != codes.NotFound {
+		t.Fatalf("want NotFound, got %v", err)
+	}
+	if err := offline.interceptor("juno-1")(context.Background(), method, req, &wasmtype.QuerySmartContractStateResponse{}, nil, nil); status.Code(err) != codes.NotFound {
+		t.Errorf("replay: want NotFound, got %v", err)
+	}
+	other := &wasmtype.QuerySmartContractStateRequest{Address: "juno1other", QueryData: []byte(`{}`)}
+	if err := offline.interceptor("juno-1")(context.Background(), method, other, &wasmtype.QuerySmartContractStateResponse{}, nil, nil); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("want FailedPrecondition for a request never recorded, got %v", err)
+	}
+}
+
+func TestHeightContext(t *testing.T) {
+	if _, ok := metadata.FromOutgoingContext(heightContext(0)); ok {
+		t.Error("want no metadata at the latest height")
+	}
+	md, _ := metadata.FromOutgoingContext(heightContext(42))
+	if got := md.Get(GRPCBlockHeightHeader); len(got) != 1 || got[0] != "42" {
+		t.Errorf("want height 42, got %v", got)
+	}
+}
+
+func TestFixtureInterceptorHeight(t *testing.T) {
+	dir := t.TempDir()
+	method := "/cosmwasm.wasm.v1.Query/SmartContractState"
+	req := &wasmtype.QuerySmartContractStateRequest{Address: "juno1contract", QueryData: []byte(`{}`)}
+
+	online, err := NewFixtureStore(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		reply.(*wasmtype.QuerySmartContractStateResponse).Data = []byte(`"at 42"`)
+		return nil
+	}
+	reply := &wasmtype.QuerySmartContractStateResponse{}
+	if err := online.interceptor("juno-1")(heightContext(42), method, req, reply, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+
+	offline, err := NewFixtureStore(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply = &wasmtype.QuerySmartContractStateResponse{}
+	if err := offline.interceptor("juno-1")(heightContext(42), method, req, reply, nil, nil); err != nil {
+		t.Fatalf("replay at height 42: %v", err)
+	}
+	if string(reply.Data) != `"at 42"` {
+		t.Errorf("unexpected reply %s", reply.Data)
+	}
+	if err := offline.interceptor("juno-1")(heightContext(0), method, req, reply, nil, nil); err == nil {
+		t.Error("want the query at the latest height not recorded")
+	}
+}
@@ -2,8 +2,11 @@ package chain
 
 import (
 	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
-	"unicode"
 )
 
 const (
@@ -119,38 +122,97 @@ var FlowStrMap = map[string]string{
 }
 
 type Flow struct {
-	flow      string
-	transfers [][3]rune // ["src-abbr", "dest-abbr", "pc-id"]
-	maxHop    int
-	curr      int
+	flow   string
+	hops   []flowHop
+	maxHop int
+	curr   int
+}
+
+// flowHop is a transfer from src to dest over a port/channel pair.
+type flowHop struct {
+	src     string
+	dest    string
+	pair    string
+	col     int // column of the pair in the flow
+	srcCol  int // column of the src chain
+	destCol int // column of the dest chain
 }
 
+// NewFlow parses a flow and checks every hop against the port/channel pairs.
 func NewFlow(flowString string) (*Flow, error) {
-	chanIds := make([]rune, 0)
-	chains := make([]rune, 0)
-	for _, c := range flowString {
-		if unicode.IsDigit(c) {
-			chanIds = append(chanIds, c)
-		}
-		if unicode.IsLetter(c) {
-			chains = append(chains, c)
-		}
+	hops, err := parseFlow(flowString)
+	if err != nil {
+		return nil, err
 	}
-
-	transfers := make([][3]rune, 0)
-	for i, c := range chanIds {
-		transfer := [3]rune{chains[i], chains[i+1], c}
-		transfers = append(transfers, transfer)
+	if err := validateFlowHops(flowString, hops); err != nil {
+		return nil, err
 	}
 
 	return &Flow{
-		flow:      flowString,
-		transfers: transfers,
-		maxHop:    len(chanIds),
-		curr:      0,
+		flow:   flowString,
+		hops:   hops,
+		maxHop: len(hops),
+		curr:   0,
 	}, nil
 }
 
+// LookupFlow returns the flow of a flow id in FlowStrMap.
+func LookupFlow(flowId string) (*Flow, error) {
+	flowStr, ok := FlowStrMap[flowId]
+	if !ok {
+		return nil, fmt.Errorf("unknown flow %q", flowId)
+	}
+	f, err := NewFlow(flowStr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", flowId, err)
+	}
+	return f, nil
+}
+
+// IbcClassId returns the class id of a class trace on the chain holding it, ibc/<hash of the trace>
+// or the class itself when it is native.
+func IbcClassId(trace string) string {
+	if !strings.Contains(trace, "/") {
+		return trace
+	}
+	hash := sha256.Sum256([]byte(trace))
+	return "ibc/" + tmbytes.HexBytes(hash[:]).String()
+}
+
+// MatchFlowIds returns the ids of FlowStrMap taking the same route as a flow, sorted.
+func MatchFlowIds(f *Flow) []string {
+	var flowIds []string
+	for flowId := range FlowStrMap {
+		g, err := LookupFlow(flowId)
+		if err != nil {
+			continue
+		}
+		if f.SameRoute(g) {
+			flowIds = append(flowIds, flowId)
+		}
+	}
+	sort.Strings(flowIds)
+	return flowIds
+}
+
+// String returns the flow as it was parsed.
+func (f *Flow) String() string {
+	return f.flow
+}
+
+// SameRoute tells whether two flows take the same hops over the same port/channel pairs.
+func (f *Flow) SameRoute(g *Flow) bool {
+	if len(f.hops) != len(g.hops) {
+		return false
+	}
+	for i := range f.hops {
+		if f.hops[i].src != g.hops[i].src || f.hops[i].dest != g.hops[i].dest || f.hops[i].pair != g.hops[i].pair {
+			return false
+		}
+	}
+	return true
+}
+
 func (f *Flow) GetFlowHops() int {
 	return f.maxHop
 }
@@ -161,10 +223,8 @@ func (f *Flow) Next() (*PortChanPair, bool) {
 		return nil, false
 	}
 
-	transfer := f.transfers[f.curr]
-
-	pcp, err := NewPortChanPair(string(transfer[0]), string(transfer[1]), string(transfer[2]))
-	if err != nil {
+	pcp := f.GetPortChanPairByIdx(f.curr)
+	if pcp == nil {
 		return nil, false
 	}
 	f.curr++
@@ -180,14 +240,14 @@ func (f *Flow) GetSrcChainAbbr(idx int) string {
 	if idx >= f.maxHop {
 		return ""
 	}
-	return string(f.transfers[idx][0])
+	return f.hops[idx].src
 }
 
 func (f *Flow) GetDestChainAbbr(idx int) string {
 	if idx >= f.maxHop {
 		return ""
 	}
-	return string(f.transfers[idx][1])
+	return f.hops[idx].dest
 }
 
 // GetFinalIbcHash calculates hash of trace/classId
@@ -213,8 +273,8 @@ func (f *Flow) GetFinalClassTrace() (string, error) {
 	return f.buildFinalClassTrace()
 }
 
-func (f *Flow) GetPortChanPair(transfer [3]rune) *PortChanPair {
-	pcp, err := NewPortChanPair(string(transfer[0]), string(transfer[1]), string(transfer[2]))
+func (f *Flow) getPortChanPair(h flowHop) *PortChanPair {
+	pcp, err := NewPortChanPair(h.src, h.dest, h.pair)
 	if err != nil {
 		return nil
 	}
@@ -222,8 +282,7 @@ func (f *Flow) GetPortChanPair(transfer [3]rune) *PortChanPair {
 }
 
 func (f *Flow) GetPortChanPairByIdx(idx int) *PortChanPair {
-	transfer := [3]rune{f.transfers[idx][0], f.transfers[idx][1], f.transfers[idx][2]}
-	return f.GetPortChanPair(transfer)
+	return f.getPortChanPair(f.hops[idx])
 }
 
 // buildFinalClassTrace returns the final ibc class trace in the flow
@@ -232,23 +291,23 @@ func (f *Flow) GetPortChanPairByIdx(idx int) *PortChanPair {
 // a -> b -> (c -> b)       p1/c1/class
 // a -> b -> c -> (b -> d)  p3/c3/p1/c1/class
 func (f *Flow) buildFinalClassTrace() (string, error) {
-	transferTrim := make([][3]rune, 0)
-	for i, transfer := range f.transfers {
+	hopsTrim := make([]flowHop, 0)
+	for i, h := range f.hops {
 		if i == 0 {
-			transferTrim = append(transferTrim, f.transfers[i])
+			hopsTrim = append(hopsTrim, h)
 			continue
 		}
-		k := len(transferTrim)
-		if transferTrim[k-1][2] == transfer[2] && transferTrim[k-1][0] == transfer[1] && transferTrim[k-1][1] == transfer[0] {
-			transferTrim = transferTrim[:k-1]
+		k := len(hopsTrim)
+		if hopsTrim[k-1].pair == h.pair && hopsTrim[k-1].src == h.dest && hopsTrim[k-1].dest == h.src {
+			hopsTrim = hopsTrim[:k-1]
 		} else {
-			transferTrim = append(transferTrim, f.transfers[i])
+			hopsTrim = append(hopsTrim, h)
 		}
 	}
 
 	trace := ""
-	for _, t := range transferTrim {
-		pcp := f.GetPortChanPair(t)
+	for _, h := range hopsTrim {
+		pcp := f.getPortChanPair(h)
 		trace = string(pcp.dest.Port) + "/" + string(pcp.dest.Channel) + "/" + trace
 	}
 	return trace, nil
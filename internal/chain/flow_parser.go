@@ -0,0 +1,168 @@
+package chain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A flow is written as chains joined by the port/channel pair id of each hop, e.g. "i --(1)--> s --(3)--> j".
+//
+//	flow  = chain hop { hop }
+//	hop   = "--(" pair ")-->" chain
+//	chain = ident
+//	pair  = ident
+//	ident = letter or digit { letter or digit }
+//
+// Spaces between tokens are ignored.
+const (
+	flowArrowOpen  = "--("
+	flowArrowClose = ")-->"
+)
+
+// FlowError is a syntax or validation error at a column of a flow.
+type FlowError struct {
+	Flow string
+	Col  int // 1-based
+	Msg  string
+}
+
+func (e *FlowError) Error() string {
+	return fmt.Sprintf("flow %q: col %d: %s", e.Flow, e.Col, e.Msg)
+}
+
+type flowTokenKind int
+
+const (
+	flowTokenEOF flowTokenKind = iota
+	flowTokenIdent
+	flowTokenArrowOpen
+	flowTokenArrowClose
+)
+
+func (k flowTokenKind) String() string {
+	switch k {
+	case flowTokenIdent:
+		return "id"
+	case flowTokenArrowOpen:
+		return fmt.Sprintf("%q", flowArrowOpen)
+	case flowTokenArrowClose:
+		return fmt.Sprintf("%q", flowArrowClose)
+	}
+	return "end of flow"
+}
+
+type flowToken struct {
+	kind flowTokenKind
+	text string
+	col  int
+}
+
+// tokenizeFlow splits a flow into ids and arrows.
+func tokenizeFlow(flow string) ([]flowToken, error) {
+	tokens := make([]flowToken, 0)
+	for i := 0; i < len(flow); {
+		c := flow[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.HasPrefix(flow[i:], flowArrowOpen):
+			tokens = append(tokens, flowToken{kind: flowTokenArrowOpen, text: flowArrowOpen, col: i + 1})
+			i += len(flowArrowOpen)
+		case strings.HasPrefix(flow[i:], flowArrowClose):
+			tokens = append(tokens, flowToken{kind: flowTokenArrowClose, text: flowArrowClose, col: i + 1})
+			i += len(flowArrowClose)
+		case isFlowIdentChar(c):
+			start := i
+			for i < len(flow) && isFlowIdentChar(flow[i]) {
+				i++
+			}
+			tokens = append(tokens, flowToken{kind: flowTokenIdent, text: flow[start:i], col: start + 1})
+		default:
+			return nil, &FlowError{Flow: flow, Col: i + 1, Msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	return append(tokens, flowToken{kind: flowTokenEOF, col: len(flow) + 1}), nil
+}
+
+func isFlowIdentChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// parseFlow parses a flow into its hops, the pairs of the hops are not validated.
+func parseFlow(flow string) ([]flowHop, error) {
+	tokens, err := tokenizeFlow(flow)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := 0
+	expect := func(kind flowTokenKind) (flowToken, error) {
+		tok := tokens[pos]
+		if tok.kind != kind {
+			found := tok.kind.String()
+			if tok.kind == flowTokenIdent {
+				found = fmt.Sprintf("id %q", tok.text)
+			}
+			return tok, &FlowError{Flow: flow, Col: tok.col, Msg: fmt.Sprintf("want %s, got %s", kind, found)}
+		}
+		pos++
+		return tok, nil
+	}
+
+	src, err := expect(flowTokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	hops := make([]flowHop, 0)
+	for {
+		if _, err := expect(flowTokenArrowOpen); err != nil {
+			// a flow ends after at least one hop
+			if len(hops) != 0 && tokens[pos].kind == flowTokenEOF {
+				return hops, nil
+			}
+			return nil, err
+		}
+		pair, err := expect(flowTokenIdent)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := expect(flowTokenArrowClose); err != nil {
+			return nil, err
+		}
+		dest, err := expect(flowTokenIdent)
+		if err != nil {
+			return nil, err
+		}
+		hops = append(hops, flowHop{
+			src:     src.text,
+			dest:    dest.text,
+			pair:    pair.text,
+			col:     pair.col,
+			srcCol:  src.col,
+			destCol: dest.col,
+		})
+		src = dest
+	}
+}
+
+// validateFlowHops checks the chains of every hop are known and have a port/channel pair.
+func validateFlowHops(flow string, hops []flowHop) error {
+	for _, h := range hops {
+		for _, c := range []struct {
+			abbr string
+			col  int
+		}{{h.src, h.srcCol}, {h.dest, h.destCol}} {
+			if !IsKnownChain(c.abbr) {
+				return &FlowError{Flow: flow, Col: c.col, Msg: fmt.Sprintf("unknown chain %q", c.abbr)}
+			}
+		}
+		if _, err := NewPortChanPair(h.src, h.dest, h.pair); err != nil {
+			return &FlowError{
+				Flow: flow,
+				Col:  h.col,
+				Msg:  fmt.Sprintf("no port/channel pair %s between %s and %s", h.pair, h.src, h.dest),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package chain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFlow(t *testing.T) {
+	hops, err := parseFlow("iris --(12)--> stars --(3)-->juno")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []flowHop{
+		{src: "iris", dest: "stars", pair: "12", col: 9, srcCol: 1, destCol: 16},
+		{src: "stars", dest: "juno", pair: "3", col: 25, srcCol: 16, destCol: 30},
+	}
+	if !reflect.DeepEqual(hops, want) {
+		t.Fatalf("want %+v, got %+v", want, hops)
+	}
+}
+
+func TestNewFlowError(t *testing.T) {
+	for _, tc := range []struct {
+		flow string
+		col  int
+	}{
+		{"", 1},
+		{"i", 2},
+		{"i --(1)-->", 11},
+		{"i --(1)--> s --(1)", 18},
+		{"i --()--> s", 6},
+		{"i --(1)--> s s", 14},
+		{"i -(1)-> s", 3},
+		{"i --(1)--> s --(9)--> j", 17},
+		{"i --(1)--> x", 12},
+		{"x --(1)--> i", 1},
+	} {
+		_, err := NewFlow(tc.flow)
+		var flowErr *FlowError
+		if !errors.As(err, &flowErr) {
+			t.Errorf("%q: want flow error, got %v", tc.flow, err)
+			continue
+		}
+		if flowErr.Col != tc.col {
+			t.Errorf("%q: want col %d, got %v", tc.flow, tc.col, err)
+		}
+	}
+}
+
+func TestLookupFlow(t *testing.T) {
+	for flowId := range FlowStrMap {
+		if _, err := LookupFlow(flowId); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := LookupFlow("z99"); err == nil {
+		t.Error("want error for unknown flow")
+	}
+
+	f, err := LookupFlow("a01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.GetFlowHops() != 3 || f.GetSrcChainAbbr(1) != "s" || f.GetDestChainAbbr(1) != "j" {
+		t.Errorf("unexpected hops of a01: %+v", f.hops)
+	}
+}
+
+func TestMultiCharChains(t *testing.T) {
+	// "ab" to "c" and "a" to "bc" must not share a key
+	key := PortChanPairKey{Src: "ab", Dest: "c", Id: "1"}
+	PortChanPairStrMap[key] = "nft-transfer/channel-1 <> nft-transfer/channel-2"
+	defer delete(PortChanPairStrMap, key)
+
+	f, err := NewFlow("ab --(1)--> c --(1)--> ab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.GetSrcChainAbbr(0) != "ab" || f.GetDestChainAbbr(1) != "ab" {
+		t.Errorf("unexpected hops %+v", f.hops)
+	}
+	if pcp, err := FindPortChanPair("c", "nft-transfer", "channel-2"); err != nil || pcp.GetDestPortChan().ChainAbbr != "ab" {
+		t.Errorf("want the pair to ab, got %+v %v", pcp, err)
+	}
+
+	_, err = NewFlow("a --(1)--> bc")
+	var flowErr *FlowError
+	if !errors.As(err, &flowErr) || flowErr.Col != 1 || !strings.Contains(flowErr.Msg, "unknown chain") {
+		t.Errorf("want unknown chain a at col 1, got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/taramakage/gon-verifier/internal/types"
+)
+
+// maxTraceHops bounds a trace, the longest flow has 7 hops.
+const maxTraceHops = 16
+
+// Trace is the route an nft took from a send on iris to where it rests.
+type Trace struct {
+	Flow     *Flow
+	TxHashes []string // send tx of each hop
+	Heights  []int64  // height of the send tx of each hop
+	ChainId  string   // abbreviation of the chain holding the nft
+	ClassId  string   // class trace of the nft on that chain
+	TokenId  string
+}
+
+// TraceFlow follows the nft sent by a tx on iris hop by hop: a hop is a packet received with a successful ack,
+// the next hop is the first later send of the same token by its receiver whose packet is delivered.
+// The sends on a chain are followed up to the height r queries it at, see Registry.AtHeights.
+// Every chain must implement TxSearcher and PacketTracer. The tx must send a single token, see TraceFlowToken.
+func TraceFlow(r *Registry, txHash string) (*Trace, error) {
+	return TraceFlowToken(r, txHash, "")
+}
+
+// TraceFlowToken follows one token of a tx on iris sending several, the token of the tx if tokenId is empty.
+func TraceFlowToken(r *Registry, txHash, tokenId string) (*Trace, error) {
+	return traceFlow(r, txHash, tokenId, nil)
+}
+
+// traceFlow follows a token hop by hop, the sends on a chain up to its height in until or in r, whichever is lower.
+func traceFlow(r *Registry, txHash, tokenId string, until map[string]int64) (*Trace, error) {
+	src := ChainIdAbbreviationIris
+	txi, err := r.GetChain(src).GetTx(txHash, types.TxResultTypeIbcNft)
+	if err != nil {
+		return nil, err
+	}
+	tx, ok := txi.(types.TxResultIbcNft)
+	if !ok {
+		return nil, fmt.Errorf("tx %s is not an ibc nft transfer", txHash)
+	}
+	if tx.TxCode != 0 {
+		return nil, fmt.Errorf("tx %s is unsuccessful", txHash)
+	}
+	if !beforeLimit(r, until, src, tx.Height) {
+		return nil, fmt.Errorf("tx %s is after height %d", txHash, traceLimit(r, until, src))
+	}
+	if len(tokenId) == 0 {
+		if len(tx.Tokens) != 1 {
+			return nil, fmt.Errorf("tx %s sends %d tokens, name the one to trace", txHash, len(tx.Tokens))
+		}
+		tokenId = tx.TokenId
+	}
+	if tx.CountToken(tokenId) != 1 {
+		return nil, fmt.Errorf("tx %s doesn't send %s once", txHash, tokenId)
+	}
+
+	t := &Trace{TokenId: tokenId}
+	hops := make([]string, 0, maxTraceHops)
+	for {
+		pcp, err := FindPortChanPair(src, tx.SrcPort, tx.SrcChan)
+		if err != nil {
+			return nil, err
+		}
+		dest := pcp.GetDestPortChan().ChainAbbr
+		recv, err := deliveredPacket(r, src, dest, tx)
+		if err != nil {
+			return nil, fmt.Errorf("hop %d: %w", len(t.TxHashes)+1, err)
+		}
+		if recv == nil {
+			return nil, fmt.Errorf("hop %d: packet %s/%s/%d not delivered", len(t.TxHashes)+1, tx.SrcPort, tx.SrcChan, tx.Sequence)
+		}
+
+		if len(hops) == 0 {
+			hops = append(hops, src)
+		}
+		hops = append(hops, fmt.Sprintf("--(%s)--> %s", pcp.GetId(), dest))
+		t.TxHashes = append(t.TxHashes, txHash)
+		t.Heights = append(t.Heights, tx.Height)
+		t.ChainId = dest
+		t.ClassId = tx.DestClassTrace()
+		if len(t.TxHashes) == maxTraceHops {
+			break
+		}
+
+		next, nextHash, err := nextSend(r, dest, tx.Receiver, t.ClassId, t.TokenId, recv.BlockHeight(), until)
+		if err != nil {
+			return nil, fmt.Errorf("hop %d: %w", len(t.TxHashes)+1, err)
+		}
+		if next == nil {
+			break
+		}
+		src, tx, txHash = dest, *next, nextHash
+	}
+
+	t.Flow, err = NewFlow(strings.Join(hops, " "))
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// deliveredPacket returns the recv_packet tx of a transfer, nil if the packet timed out or was acknowledged with an error.
+func deliveredPacket(r *Registry, src, dest string, tx types.TxResultIbcNft) (*types.TxResponse, error) {
+	srcTracer, ok := r.GetChain(src).(PacketTracer)
+	if !ok {
+		return nil, fmt.Errorf("chain %s can't trace packets", src)
+	}
+	destTracer, ok := r.GetChain(dest).(PacketTracer)
+	if !ok {
+		return nil, fmt.Errorf("chain %s can't trace packets", dest)
+	}
+	packet := PacketOf(tx)
+
+	_, err := srcTracer.GetPacketTx(types.EventTypeIbcTimeoutPacket, packet)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, ErrPacketTxNotFound) {
+		return nil, err
+	}
+	recv, err := destTracer.GetPacketTx(types.EventTypeIbcRecvPacket, packet)
+	if errors.Is(err, ErrPacketTxNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ack, err := recv.IbcAck()
+	if err != nil || len(ack.Error) != 0 {
+		return nil, nil
+	}
+	return recv, nil
+}
+
+// nextSend returns the first successful send of a token by owner on a chain from height on whose packet is delivered,
+// nil if the token didn't leave the chain before the trace limit.
+func nextSend(r *Registry, abbr, owner, classTrace, tokenId string, height int64, until map[string]int64) (*types.TxResultIbcNft, string, error) {
+	searcher, ok := r.GetChain(abbr).(TxSearcher)
+	if !ok {
+		return nil, "", fmt.Errorf("chain %s can't search txs", abbr)
+	}
+	txs, err := searcher.SearchTxs(sendQuery(owner))
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i := range txs {
+		if txs[i].Result.TxResult.Code != 0 || txs[i].BlockHeight() < height || !beforeLimit(r, until, abbr, txs[i].BlockHeight()) {
+			continue
+		}
+		txi, err := txs[i].IbcNftPkg()
+		if err != nil {
+			continue
+		}
+		tx := txi.(types.TxResultIbcNft)
+		if tx.CountToken(tokenId) != 1 || tx.ClassId != classTrace {
+			continue
+		}
+		pcp, err := FindPortChanPair(abbr, tx.SrcPort, tx.SrcChan)
+		if err != nil {
+			continue
+		}
+		recv, err := deliveredPacket(r, abbr, pcp.GetDestPortChan().ChainAbbr, tx)
+		if err != nil {
+			return nil, "", err
+		}
+		if recv != nil {
+			return &tx, txs[i].Result.Hash, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// TraceToken traces the latest journey of a token sent from iris by owner under its original class.
+// The sends on a chain are followed up to its height in until or the height r queries it at, whichever is lower,
+// and up to its latest height without both.
+func TraceToken(r *Registry, owner, classId, tokenId string, until map[string]int64) (*Trace, error) {
+	searcher, ok := r.GetChain(ChainIdAbbreviationIris).(TxSearcher)
+	if !ok {
+		return nil, errors.New("iris can't search txs")
+	}
+	txs, err := searcher.SearchTxs(sendQuery(owner))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(txs) - 1; i >= 0; i-- {
+		if txs[i].Result.TxResult.Code != 0 || !beforeLimit(r, until, ChainIdAbbreviationIris, txs[i].BlockHeight()) {
+			continue
+		}
+		txi, err := txs[i].IbcNftPkg()
+		if err != nil {
+			continue
+		}
+		tx := txi.(types.TxResultIbcNft)
+		if tx.CountToken(tokenId) == 1 && tx.ClassId == classId {
+			return traceFlow(r, txs[i].Result.Hash, tokenId, until)
+		}
+	}
+	return nil, fmt.Errorf("no send of %s/%s by %s on iris", classId, tokenId, owner)
+}
+
+// traceLimit returns the last height a chain is traced at, the lower of its height in until and in r, 0 if none.
+func traceLimit(r *Registry, until map[string]int64, abbr string) int64 {
+	limit := r.Height(abbr)
+	if height, ok := until[abbr]; ok && height > 0 && (limit == 0 || height < limit) {
+		limit = height
+	}
+	return limit
+}
+
+// beforeLimit reports whether a height of a chain is inside the trace.
+func beforeLimit(r *Registry, until map[string]int64, abbr string, height int64) bool {
+	limit := traceLimit(r, until, abbr)
+	return limit == 0 || height <= limit
+}
+
+// sendQuery searches the packets sent by an owner.
+func sendQuery(owner string) string {
+	return fmt.Sprintf("%s.%s='%s' AND %s.%s EXISTS",
+		types.EventTypeMessage, types.AttributeMsgSender, owner,
+		types.EventTypeIbcSendPacket, types.AttributeKeySequence,
+	)
+}
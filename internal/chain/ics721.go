@@ -0,0 +1,76 @@
+package chain
+
+import (
+	"fmt"
+	"strings"
+)
+
+const wasmPortPrefix = "wasm."
+
+type (
+	// ICS721 resolves the cw721 contracts instantiated by the ics721 bridge of a wasm chain for the classes it received.
+	ICS721 interface {
+		// NftContract returns the cw721 contract of a class trace, e.g. wasm.stars1.../channel-207/denom.
+		NftContract(classId string) (string, error)
+		// ClassId returns the class trace of a cw721 contract.
+		ClassId(contract string) (string, error)
+	}
+
+	// ics721Client implements ICS721 with smart queries to the bridge, it is embedded by the wasm chains.
+	ics721Client struct {
+		cw721Client
+		bridge string
+	}
+
+	ics721NftContractQuery struct {
+		ClassId string `json:"class_id"`
+	}
+
+	ics721ClassIdQuery struct {
+		Contract string `json:"contract"`
+	}
+)
+
+var _ ICS721 = ics721Client{}
+
+func (c ics721Client) NftContract(classId string) (string, error) {
+	var contract *string
+	err := c.query(c.bridge, map[string]any{"nft_contract": ics721NftContractQuery{classId}}, &contract)
+	if err != nil {
+		return "", err
+	}
+	if contract == nil || len(*contract) == 0 {
+		return "", fmt.Errorf("no nft contract for class %s on bridge %s", classId, c.bridge)
+	}
+	return *contract, nil
+}
+
+func (c ics721Client) ClassId(contract string) (string, error) {
+	var classId *string
+	err := c.query(c.bridge, map[string]any{"class_id": ics721ClassIdQuery{contract}}, &classId)
+	if err != nil {
+		return "", err
+	}
+	if classId == nil || len(*classId) == 0 {
+		return "", fmt.Errorf("no class for nft contract %s on bridge %s", contract, c.bridge)
+	}
+	return *classId, nil
+}
+
+// ics721Bridge returns the bridge contract of a wasm chain, the one bound to its ports in PortChanPairStrMap.
+func ics721Bridge(abbr string) string {
+	for key, value := range PortChanPairStrMap {
+		sides := strings.Split(value, " <> ")
+		for i, chainAbbr := range []string{key.Src, key.Dest} {
+			if chainAbbr != abbr || i >= len(sides) {
+				continue
+			}
+			side := sides[i]
+			port := strings.Split(side, "/")[0]
+			if strings.HasPrefix(port, wasmPortPrefix) {
+				return strings.TrimPrefix(port, wasmPortPrefix)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,33 @@
+package chain
+
+import (
+	"testing"
+)
+
+func TestICS721(t *testing.T) {
+	bridge := ics721Bridge(ChainIdAbbreviationStars)
+	if bridge != "stars1ve46fjrhcrum94c7d8yc2wsdz8cpuw73503e8qn9r44spr6dw0lsvmvtqh" {
+		t.Fatalf("unexpected bridge of stargaze %q", bridge)
+	}
+	if bridge := ics721Bridge(ChainIdAbbreviationIris); bridge != "" {
+		t.Fatalf("want no bridge on iris, got %q", bridge)
+	}
+
+	c := ics721Client{
+		cw721Client: cw721Client{wasmClient: fakeWasm{
+			contract: bridge,
+			answers: map[string]string{
+				"nft_contract": `"stars1contract"`,
+				"class_id":     `null`,
+			},
+		}},
+		bridge: bridge,
+	}
+	contract, err := c.NftContract("wasm." + bridge + "/channel-207/denom1")
+	if err != nil || contract != "stars1contract" {
+		t.Fatalf("nft_contract: unexpected %q, %v", contract, err)
+	}
+	if _, err := c.ClassId("stars1contract"); err == nil {
+		t.Fatal("class_id: want error for a contract unknown to the bridge")
+	}
+}
@@ -1,45 +1,49 @@
 package chain
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	nfttypes "github.com/irisnet/irismod/modules/nft/types"
 	"github.com/taramakage/gon-verifier/internal/types"
 	ics721types "github.com/taramakage/gon-verifier/internal/types/ics721"
 	"google.golang.org/grpc"
+	"net/http"
+	"time"
 )
 
 type (
 	Iris struct {
+		rpc          string
+		httpClient   *http.Client
+		proof        *ProofVerifier
 		conn         *grpc.ClientConn
+		height       int64 // height of the state queries, the latest if 0
 		nftClient    nfttypes.QueryClient
 		ics721Client ics721types.QueryClient
 	}
 )
 
-func NewIris() *Iris {
-	conn, err := grpc.Dial(
-		ChainGRPCIris,
-		grpc.WithInsecure(),
-		grpc.WithDefaultCallOptions(),
-	)
+func NewIris(cfg EndpointConfig, fs *FixtureStore) (*Iris, error) {
+	conn, err := dial(cfg, fs)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return &Iris{
+		rpc:          cfg.rpcBase(),
+		httpClient:   fs.httpClient(cfg),
+		proof:        newProofVerifier(cfg, fs),
 		conn:         conn, // NOTE: Close this connection when the program exits
 		nftClient:    nfttypes.NewQueryClient(conn),
 		ics721Client: ics721types.NewQueryClient(conn),
-	}
+	}, nil
 }
 
 // GetTx returns the transaction result
 func (i *Iris) GetTx(txHash, txType string) (any, error) {
 	txHash = "0x" + txHash
-	url := fmt.Sprintf(ChainRPCIris+"tx?hash=%s&prove=true", txHash)
-	body, err := getRespWithRetry(url)
+	url := fmt.Sprintf(i.rpc+"tx?hash=%s&prove=true", txHash)
+	body, err := getRespWithRetry(i.httpClient, url)
 	if err != nil {
 		return nil, err
 	}
@@ -48,50 +52,85 @@ func (i *Iris) GetTx(txHash, txType string) (any, error) {
 	if err := json.Unmarshal(body, &data); err != nil {
 		return nil, err
 	}
+	if err := i.proof.Verify(&data); err != nil {
+		return nil, err
+	}
+
+	return ParseTxResult(&data, txType)
+}
 
+// ParseTxResult converts a tx response of iris to the result of txType.
+func ParseTxResult(data *types.TxResponse, txType string) (any, error) {
 	switch txType {
 	case types.TxResultTypeRaw:
-		return data, nil
+		return *data, nil
 	case types.TxResultTypeBasic:
-		return i.getTxResultBasic(&data)
+		return getTxResultBasic(data)
 	case types.TxResultTypeIssueDenom:
-		return i.getTxResultIssueDenom(&data)
+		return getTxResultIssueDenom(data)
 	case types.TxResultTypeMintNft:
-		return i.getTxResultMintNft(&data)
+		return getTxResultMintNft(data)
 	case types.TxResultTypeIbcNft:
-		return i.getTxResultIbcNft(&data)
+		return getTxResultIbcNft(data)
 	}
 
 	return nil, fmt.Errorf("unknown tx type: %s", txType)
 }
 
-func (i *Iris) getTxResultBasic(data *types.TxResponse) (any, error) {
+// msgSender returns the signer of the first msg of the types, the first sender attribute if the tx has no body.
+// A tx may carry several sender attributes, e.g. the ones of the fee transfer, the signer is the one to trust.
+func msgSender(data *types.TxResponse, body *types.TxBody, typeUrls ...string) string {
+	if body == nil {
+		return data.AttributeValueByKey(types.AttributeMsgSender)
+	}
+	return body.Signer(typeUrls...)
+}
+
+func getTxResultBasic(data *types.TxResponse) (any, error) {
+	body, err := data.Body()
+	if err != nil {
+		return nil, err
+	}
 	return types.TxResultBasic{
-		Sender: data.AttributeValueByKey(types.AttributeMsgSender),
+		Sender: msgSender(data, body),
 		TxCode: data.Result.TxResult.Code,
+		Height: data.BlockHeight(),
+		Body:   body,
 	}, nil
 }
 
-func (i *Iris) getTxResultIssueDenom(data *types.TxResponse) (any, error) {
+func getTxResultIssueDenom(data *types.TxResponse) (any, error) {
+	body, err := data.Body()
+	if err != nil {
+		return nil, err
+	}
 	return types.TxResultIssueDenom{
-		Sender:  data.AttributeValueByKey(types.AttributeMsgSender),
+		Sender:  msgSender(data, body, types.MsgTypesIssueDenom...),
 		Creator: data.EventAttributeValueByKey(types.EventTypeIssueDenom, types.AttributeDenomCreator),
 		DenomId: data.EventAttributeValueByKey(types.EventTypeIssueDenom, types.AttributeDenomId),
 		TxCode:  data.Result.TxResult.Code,
+		Height:  data.BlockHeight(),
+		Body:    body,
 	}, nil
 }
 
-func (i *Iris) getTxResultMintNft(data *types.TxResponse) (any, error) {
+func getTxResultMintNft(data *types.TxResponse) (any, error) {
+	body, err := data.Body()
+	if err != nil {
+		return nil, err
+	}
 	return types.TxResultMintNft{
-		Sender:    data.AttributeValueByKey(types.AttributeMsgSender),
+		Sender:    msgSender(data, body, types.MsgTypesMintNft...),
 		DenomId:   data.EventAttributeValueByKey(types.EventTypeNftMint, types.AttributeDenomId),
 		TokenId:   data.EventAttributeValueByKey(types.EventTypeNftMint, types.AttributeKeyTokenId),
 		Recipient: data.EventAttributeValueByKey(types.EventTypeNftMint, types.AttributeKeyRecipient),
 		TxCode:    data.Result.TxResult.Code,
+		Height:    data.BlockHeight(),
+		Body:      body,
 	}, nil
 }
 
-func (i *Iris) getTxResultIbcNft(data *types.TxResponse) (any, error) {
+func getTxResultIbcNft(data *types.TxResponse) (any, error) {
 	return data.IbcNftPkg()
 }
 
@@ -101,7 +140,7 @@ func (i *Iris) GetCollection(classID string) (*nfttypes.QueryCollectionResponse,
 	}
 
 	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return i.nftClient.Collection(context.Background(), &req)
+		return i.nftClient.Collection(heightContext(i.height), &req)
 	})
 	if err != nil {
 		return nil, err
@@ -120,7 +159,7 @@ func (i *Iris) GetNFT(classID, nftID string) (*NFT, error) {
 	}
 
 	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return i.nftClient.NFT(context.Background(), req)
+		return i.nftClient.NFT(heightContext(i.height), req)
 	})
 	if err != nil {
 		return nil, err
@@ -152,7 +191,7 @@ func (i *Iris) GetClass(classID string) (*Class, error) {
 	}
 
 	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return i.nftClient.Denom(context.Background(), req)
+		return i.nftClient.Denom(heightContext(i.height), req)
 	})
 	if err != nil {
 		return nil, err
@@ -184,7 +223,7 @@ func (i *Iris) HasClass(classID string) bool {
 func (i *Iris) GetOriginalClassId(ibcClassId string) (string, error) {
 	req := &ics721types.QueryClassTraceRequest{Hash: ibcClassId}
 	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return i.ics721Client.ClassTrace(context.Background(), req)
+		return i.ics721Client.ClassTrace(heightContext(i.height), req)
 	})
 	if err != nil {
 		return "", err
@@ -196,8 +235,30 @@ func (i *Iris) GetOriginalClassId(ibcClassId string) (string, error) {
 	return res.ClassTrace.BaseClassId, nil
 }
 
+// AtHeight returns the chain whose state is queried at a height.
+func (i *Iris) AtHeight(height int64) Chain {
+	pinned := *i
+	pinned.height = height
+	return &pinned
+}
+
 func (i *Iris) Close() {
 	if i.conn != nil {
 		i.conn.Close()
 	}
 }
+
+// GetPacketTx returns the tx emitting the event of a packet.
+func (i *Iris) GetPacketTx(event string, p Packet) (*types.TxResponse, error) {
+	return searchPacketTx(i.httpClient, i.rpc, event, p)
+}
+
+// SearchTxs returns the txs matching a tendermint query.
+func (i *Iris) SearchTxs(query string) ([]types.TxResponse, error) {
+	return searchTxs(i.httpClient, i.rpc, query, searchMaxTxs)
+}
+
+// BlockTime returns the time of the block at a height.
+func (i *Iris) BlockTime(height int64) (time.Time, error) {
+	return blockTime(i.httpClient, i.rpc, height)
+}
@@ -1,39 +1,45 @@
 package chain
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/taramakage/gon-verifier/internal/types"
 	wasmtype "github.com/taramakage/gon-verifier/internal/types/wasm"
 	"google.golang.org/grpc"
+	"net/http"
+	"time"
 )
 
 type Juno struct {
+	rpc        string
+	httpClient *http.Client
+	proof      *ProofVerifier
 	conn       *grpc.ClientConn
-	wasmClient wasmtype.QueryClient
+	ics721Client
 }
 
-func NewJuno() *Juno {
-	conn, err := grpc.Dial(
-		ChainGRPCJuno,
-		grpc.WithInsecure(),
-		grpc.WithDefaultCallOptions(),
-	)
+func NewJuno(cfg EndpointConfig, fs *FixtureStore) (*Juno, error) {
+	conn, err := dial(cfg, fs)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return &Juno{
+		rpc:        cfg.rpcBase(),
+		httpClient: fs.httpClient(cfg),
+		proof:      newProofVerifier(cfg, fs),
 		conn:       conn, // NOTE: Close this connection when the program exits
-		wasmClient: wasmtype.NewQueryClient(conn),
-	}
+		ics721Client: ics721Client{
+			cw721Client: cw721Client{wasmClient: wasmtype.NewQueryClient(conn)},
+			bridge:      cfg.ics721Bridge(),
+		},
+	}, nil
 }
 
 func (j *Juno) GetTx(txHash, txType string) (any, error) {
 	txHash = "0x" + txHash
-	url := fmt.Sprintf(ChainRPCJuno+"tx?hash=%s&prove=true", txHash)
-	body, err := getRespWithRetry(url)
+	url := fmt.Sprintf(j.rpc+"tx?hash=%s&prove=true", txHash)
+	body, err := getRespWithRetry(j.httpClient, url)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +48,9 @@ func (j *Juno) GetTx(txHash, txType string) (any, error) {
 	if err := json.Unmarshal(body, &data); err != nil {
 		return nil, err
 	}
+	if err := j.proof.Verify(&data); err != nil {
+		return nil, err
+	}
 
 	switch txType {
 	case types.TxResultTypeIbcNft:
@@ -55,34 +64,9 @@ func (j *Juno) getTxResultIbcNft(data *types.TxResponse) (any, error) {
 	return data.IbcNftPkg()
 }
 
+// GetNFT returns the token of a cw721 contract with its owner, uri and extension.
 func (j *Juno) GetNFT(classID, nftID string) (*NFT, error) {
-	wq := WasmQueryNFT{
-		NftInfo: NftInfo{nftID},
-	}
-	// convert wq to json string
-	bz, err := json.Marshal(wq)
-	if err != nil {
-		return nil, err
-	}
-
-	req := &wasmtype.QuerySmartContractStateRequest{
-		Address:   classID,
-		QueryData: bz,
-	}
-	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return j.wasmClient.SmartContractState(context.Background(), req)
-	})
-	if err != nil {
-		return nil, err
-	}
-	_, ok := resi.(*wasmtype.QuerySmartContractStateResponse)
-	if !ok {
-		return nil, err
-	}
-
-	return &NFT{
-		ID: nftID,
-	}, nil
+	return j.getNFT(classID, nftID)
 }
 
 func (j *Juno) HasNFT(classID, nftID string) bool {
@@ -92,36 +76,10 @@ func (j *Juno) HasNFT(classID, nftID string) bool {
 	}
 	return true
 }
-func (j *Juno) GetClass(classID string) (*Class, error) {
-	wq := WasmQueryClass{}
-	// convert wq to json string
-	bz, err := json.Marshal(wq)
-	if err != nil {
-		return nil, err
-	}
-
-	req := &wasmtype.QuerySmartContractStateRequest{
-		Address:   classID,
-		QueryData: bz,
-	}
-	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return j.wasmClient.SmartContractState(context.Background(), req)
-	})
-	if err != nil {
-		return nil, err
-	}
-	res, ok := resi.(*wasmtype.QuerySmartContractStateResponse)
-	if !ok {
-		return nil, err
-	}
-
-	wr := WasmRespClass{}
-	err = json.Unmarshal(res.Data, &wr)
-	if err != nil {
-		return nil, nil
-	}
 
-	return &Class{}, nil
+// GetClass returns the name, symbol and number of tokens of a cw721 contract.
+func (j *Juno) GetClass(classID string) (*Class, error) {
+	return j.getClass(classID)
 }
 
 func (j *Juno) HasClass(classID string) bool {
@@ -132,8 +90,30 @@ func (j *Juno) HasClass(classID string) bool {
 	return true
 }
 
+// AtHeight returns the chain whose contracts are queried at a height.
+func (j *Juno) AtHeight(height int64) Chain {
+	pinned := *j
+	pinned.height = height
+	return &pinned
+}
+
 func (j *Juno) Close() {
 	if j.conn != nil {
 		j.conn.Close()
 	}
 }
+
+// GetPacketTx returns the tx emitting the event of a packet.
+func (j *Juno) GetPacketTx(event string, p Packet) (*types.TxResponse, error) {
+	return searchPacketTx(j.httpClient, j.rpc, event, p)
+}
+
+// SearchTxs returns the txs matching a tendermint query.
+func (j *Juno) SearchTxs(query string) ([]types.TxResponse, error) {
+	return searchTxs(j.httpClient, j.rpc, query, searchMaxTxs)
+}
+
+// BlockTime returns the time of the block at a height.
+func (j *Juno) BlockTime(height int64) (time.Time, error) {
+	return blockTime(j.httpClient, j.rpc, height)
+}
@@ -1,39 +1,43 @@
 package chain
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	nfttypes "github.com/OmniFlix/onft/types"
 	"github.com/taramakage/gon-verifier/internal/types"
 	"google.golang.org/grpc"
+	"net/http"
+	"time"
 )
 
 type Omniflix struct {
-	conn      *grpc.ClientConn
-	nftClient nfttypes.QueryClient
+	rpc        string
+	httpClient *http.Client
+	proof      *ProofVerifier
+	conn       *grpc.ClientConn
+	height     int64 // height of the state queries, the latest if 0
+	nftClient  nfttypes.QueryClient
 }
 
-func NewOmniflix() *Omniflix {
-	conn, err := grpc.Dial(
-		ChainGRPCOmniflix,
-		grpc.WithInsecure(),
-		grpc.WithDefaultCallOptions(),
-	)
+func NewOmniflix(cfg EndpointConfig, fs *FixtureStore) (*Omniflix, error) {
+	conn, err := dial(cfg, fs)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return &Omniflix{
-		conn:      conn,
-		nftClient: nfttypes.NewQueryClient(conn),
-	}
+		rpc:        cfg.rpcBase(),
+		httpClient: fs.httpClient(cfg),
+		proof:      newProofVerifier(cfg, fs),
+		conn:       conn,
+		nftClient:  nfttypes.NewQueryClient(conn),
+	}, nil
 }
 
 func (o *Omniflix) GetTx(txHash, txType string) (any, error) {
 	txHash = "0x" + txHash
-	url := fmt.Sprintf(ChainRPCOmnilfix+"tx?hash=%s&prove=true", txHash)
-	body, err := getRespWithRetry(url)
+	url := fmt.Sprintf(o.rpc+"tx?hash=%s&prove=true", txHash)
+	body, err := getRespWithRetry(o.httpClient, url)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +46,9 @@ func (o *Omniflix) GetTx(txHash, txType string) (any, error) {
 	if err := json.Unmarshal(body, &data); err != nil {
 		return nil, err
 	}
+	if err := o.proof.Verify(&data); err != nil {
+		return nil, err
+	}
 
 	switch txType {
 	case types.TxResultTypeIbcNft:
@@ -62,7 +69,7 @@ func (o *Omniflix) GetNFT(classID, nftID string) (*NFT, error) {
 	}
 
 	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return o.nftClient.ONFT(context.Background(), req)
+		return o.nftClient.ONFT(heightContext(o.height), req)
 	})
 	if err != nil {
 		return nil, err
@@ -94,7 +101,7 @@ func (o *Omniflix) GetClass(classID string) (*Class, error) {
 	}
 
 	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return o.nftClient.Denom(context.Background(), req)
+		return o.nftClient.Denom(heightContext(o.height), req)
 	})
 	if err != nil {
 		return nil, err
@@ -123,8 +130,30 @@ func (o *Omniflix) HasClass(classID string) bool {
 	return true
 }
 
+// AtHeight returns the chain whose state is queried at a height.
+func (o *Omniflix) AtHeight(height int64) Chain {
+	pinned := *o
+	pinned.height = height
+	return &pinned
+}
+
 func (o *Omniflix) Close() {
 	if o.conn != nil {
 		o.conn.Close()
 	}
 }
+
+// GetPacketTx returns the tx emitting the event of a packet.
+func (o *Omniflix) GetPacketTx(event string, p Packet) (*types.TxResponse, error) {
+	return searchPacketTx(o.httpClient, o.rpc, event, p)
+}
+
+// SearchTxs returns the txs matching a tendermint query.
+func (o *Omniflix) SearchTxs(query string) ([]types.TxResponse, error) {
+	return searchTxs(o.httpClient, o.rpc, query, searchMaxTxs)
+}
+
+// BlockTime returns the time of the block at a height.
+func (o *Omniflix) BlockTime(height int64) (time.Time, error) {
+	return blockTime(o.httpClient, o.rpc, height)
+}
@@ -0,0 +1,53 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/taramakage/gon-verifier/internal/types"
+)
+
+// ErrPacketTxNotFound is returned when no tx emitted the event of a packet.
+var ErrPacketTxNotFound = errors.New("packet tx not found")
+
+type (
+	// Packet identifies an ibc packet by its sequence and the port and channel it was sent from.
+	Packet struct {
+		Sequence uint64
+		SrcPort  string
+		SrcChan  string
+	}
+
+	// PacketTracer finds the txs of the lifecycle of a packet, e.g. recv_packet on the receiving chain
+	// or acknowledge_packet and timeout_packet on the sending chain.
+	PacketTracer interface {
+		GetPacketTx(event string, p Packet) (*types.TxResponse, error)
+	}
+)
+
+// PacketOf returns the packet sent by an ibc nft transfer.
+func PacketOf(tx types.TxResultIbcNft) Packet {
+	return Packet{
+		Sequence: tx.Sequence,
+		SrcPort:  tx.SrcPort,
+		SrcChan:  tx.SrcChan,
+	}
+}
+
+// searchPacketTx finds the first tx emitting the event of a packet with the tx_search endpoint of a node.
+func searchPacketTx(client *http.Client, rpc, event string, p Packet) (*types.TxResponse, error) {
+	query := fmt.Sprintf("%s.%s=%d AND %s.%s='%s' AND %s.%s='%s'",
+		event, types.AttributeKeySequence, p.Sequence,
+		event, types.AttributeKeySrcPort, p.SrcPort,
+		event, types.AttributeKeySrcChan, p.SrcChan,
+	)
+	txs, err := searchTxs(client, rpc, query, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(txs) == 0 {
+		return nil, fmt.Errorf("%w: %s %s/%s/%d", ErrPacketTxNotFound, event, p.SrcPort, p.SrcChan, p.Sequence)
+	}
+	return &txs[0], nil
+}
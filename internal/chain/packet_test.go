@@ -0,0 +1,37 @@
+package chain
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/taramakage/gon-verifier/internal/types"
+)
+
+func TestSearchPacketTx(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		if r.URL.Query().Get("query") == `"recv_packet.packet_sequence=7 AND recv_packet.packet_src_port='nft-transfer' AND recv_packet.packet_src_channel='channel-0'"` {
+			w.Write([]byte(`{"result":{"txs":[{"hash":"ABC","height":"42","tx_result":{"code":0}}],"total_count":"1"}}`))
+			return
+		}
+		w.Write([]byte(`{"result":{"txs":[],"total_count":"0"}}`))
+	}))
+	defer srv.Close()
+
+	p := Packet{Sequence: 7, SrcPort: "nft-transfer", SrcChan: "channel-0"}
+	tx, err := searchPacketTx(srv.Client(), srv.URL+"/", types.EventTypeIbcRecvPacket, p)
+	if err != nil {
+		t.Fatalf("query %s: %v", query, err)
+	}
+	if tx.Result.Hash != "ABC" || tx.BlockHeight() != 42 {
+		t.Errorf("unexpected tx %+v", tx.Result)
+	}
+
+	_, err = searchPacketTx(srv.Client(), srv.URL+"/", types.EventTypeIbcTimeoutPacket, p)
+	if !errors.Is(err, ErrPacketTxNotFound) {
+		t.Errorf("want packet tx not found, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/taramakage/gon-verifier/internal/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// ErrTxProofInvalid is returned when the proof of a tx doesn't lead to the data hash of its block.
+var ErrTxProofInvalid = errors.New("tx proof invalid")
+
+// ProofVerifier checks the proof of a tx against the header of its block queried from another node,
+// so a tx can't be made up by the node it is queried from. A nil ProofVerifier accepts every tx.
+type ProofVerifier struct {
+	rpc        string
+	chainId    string
+	httpClient *http.Client
+}
+
+// newProofVerifier returns the proof verifier of an endpoint, nil if it has no proof rpc.
+func newProofVerifier(cfg EndpointConfig, fs *FixtureStore) *ProofVerifier {
+	if len(cfg.ProofRPC) == 0 {
+		return nil
+	}
+	return &ProofVerifier{
+		rpc:        cfg.proofRPCBase(),
+		chainId:    cfg.ChainId,
+		httpClient: fs.httpClient(cfg),
+	}
+}
+
+// Verify checks the tx is the one hashed, and that its proof leads to the data hash of the header at its height.
+func (pv *ProofVerifier) Verify(tx *types.TxResponse) error {
+	if pv == nil {
+		return nil
+	}
+	dataHash, err := pv.dataHash(tx.BlockHeight())
+	if err != nil {
+		return err
+	}
+	return VerifyTxProof(tx, dataHash)
+}
+
+// dataHash returns the data hash of the header at a height.
+func (pv *ProofVerifier) dataHash(height int64) ([]byte, error) {
+	header, err := getHeader(pv.httpClient, pv.rpc, height)
+	if err != nil {
+		return nil, err
+	}
+	if header.ChainId != pv.chainId {
+		return nil, fmt.Errorf("proof rpc: chain id %s, want %s", header.ChainId, pv.chainId)
+	}
+	if header.Height != strconv.FormatInt(height, 10) {
+		return nil, fmt.Errorf("proof rpc: header at height %s, want %d", header.Height, height)
+	}
+	return hex.DecodeString(header.DataHash)
+}
+
+// VerifyTxProof checks the proof of a tx queried with prove=true against the data hash of its block.
+func VerifyTxProof(tx *types.TxResponse, dataHash []byte) error {
+	txProof := tx.Result.Proof
+	txBytes, err := base64.StdEncoding.DecodeString(txProof.Data)
+	if err != nil || len(txBytes) == 0 {
+		return fmt.Errorf("%w: tx %s has no proof", ErrTxProofInvalid, tx.Result.Hash)
+	}
+
+	data := tmtypes.Tx(txBytes)
+	if !strings.EqualFold(hex.EncodeToString(data.Hash()), tx.Result.Hash) {
+		return fmt.Errorf("%w: tx %s proves another tx", ErrTxProofInvalid, tx.Result.Hash)
+	}
+	if len(tx.Result.Tx) != 0 && tx.Result.Tx != txProof.Data {
+		return fmt.Errorf("%w: tx %s differs from the tx proved", ErrTxProofInvalid, tx.Result.Hash)
+	}
+	rootHash, err := hex.DecodeString(txProof.RootHash)
+	if err != nil || !bytes.Equal(rootHash, dataHash) {
+		return fmt.Errorf("%w: tx %s root hash %s, data hash %X", ErrTxProofInvalid, tx.Result.Hash, txProof.RootHash, dataHash)
+	}
+
+	proof, err := merkleProof(tx)
+	if err != nil {
+		return fmt.Errorf("%w: tx %s: %v", ErrTxProofInvalid, tx.Result.Hash, err)
+	}
+	// the leaves of the data hash are the hashes of the txs
+	txp := tmtypes.TxProof{RootHash: rootHash, Data: data, Proof: *proof}
+	if err := txp.Validate(dataHash); err != nil {
+		return fmt.Errorf("%w: tx %s: %v", ErrTxProofInvalid, tx.Result.Hash, err)
+	}
+	return nil
+}
+
+// merkleProof decodes the proof of a tx response.
+func merkleProof(tx *types.TxResponse) (*merkle.Proof, error) {
+	p := tx.Result.Proof.Proof
+	total, err := strconv.ParseInt(p.Total, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid total %q", p.Total)
+	}
+	index, err := strconv.ParseInt(p.Index, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid index %q", p.Index)
+	}
+	leafHash, err := base64.StdEncoding.DecodeString(p.LeafHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid leaf hash %q", p.LeafHash)
+	}
+
+	proof := &merkle.Proof{Total: total, Index: index, LeafHash: leafHash}
+	for _, a := range p.Aunts {
+		s, ok := a.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid aunt %v", a)
+		}
+		aunt, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid aunt %q", s)
+		}
+		proof.Aunts = append(proof.Aunts, aunt)
+	}
+	return proof, nil
+}
@@ -0,0 +1,151 @@
+package chain
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/taramakage/gon-verifier/internal/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// provedTx returns the response of the tx at index of a block with txs, and the data hash of the block,
+// proved the way tendermint does.
+func provedTx(txs tmtypes.Txs, index int) (types.TxResponse, []byte) {
+	proof := txs.Proof(index)
+
+	var tx types.TxResponse
+	tx.Result.Hash = fmt.Sprintf("%X", txs[index].Hash())
+	tx.Result.Height = "42"
+	tx.Result.Tx = base64.StdEncoding.EncodeToString(txs[index])
+	tx.Result.Proof.RootHash = fmt.Sprintf("%X", proof.RootHash)
+	tx.Result.Proof.Data = base64.StdEncoding.EncodeToString(proof.Data)
+	tx.Result.Proof.Proof.Total = strconv.FormatInt(proof.Proof.Total, 10)
+	tx.Result.Proof.Proof.Index = strconv.FormatInt(proof.Proof.Index, 10)
+	tx.Result.Proof.Proof.LeafHash = base64.StdEncoding.EncodeToString(proof.Proof.LeafHash)
+	for _, aunt := range proof.Proof.Aunts {
+		tx.Result.Proof.Proof.Aunts = append(tx.Result.Proof.Proof.Aunts, base64.StdEncoding.EncodeToString(aunt))
+	}
+	return tx, txs.Hash()
+}
+
+func TestVerifyTxProof(t *testing.T) {
+	txs := tmtypes.Txs{tmtypes.Tx("tx0"), tmtypes.Tx("tx1"), tmtypes.Tx("tx2")}
+
+	cases := []struct {
+		name   string
+		tamper func(tx *types.TxResponse, dataHash []byte) []byte
+		valid  bool
+	}{
+		{
+			name:   "valid",
+			tamper: func(tx *types.TxResponse, dataHash []byte) []byte { return dataHash },
+			valid:  true,
+		},
+		{
+			name:   "another data hash",
+			tamper: func(tx *types.TxResponse, dataHash []byte) []byte { return make([]byte, 32) },
+		},
+		{
+			name: "root hash of another block",
+			tamper: func(tx *types.TxResponse, dataHash []byte) []byte {
+				tx.Result.Proof.RootHash = fmt.Sprintf("%X", make([]byte, 32))
+				return dataHash
+			},
+		},
+		{
+			name: "another tx proved",
+			tamper: func(tx *types.TxResponse, dataHash []byte) []byte {
+				tx.Result.Proof.Data = base64.StdEncoding.EncodeToString(txs[2])
+				return dataHash
+			},
+		},
+		{
+			name: "aunt tampered",
+			tamper: func(tx *types.TxResponse, dataHash []byte) []byte {
+				tx.Result.Proof.Proof.Aunts[0] = base64.StdEncoding.EncodeToString(make([]byte, 32))
+				return dataHash
+			},
+		},
+		{
+			name: "raw txs as leaves",
+			tamper: func(tx *types.TxResponse, dataHash []byte) []byte {
+				root, proofs := merkle.ProofsFromByteSlices([][]byte{txs[0], txs[1], txs[2]})
+				tx.Result.Proof.RootHash = fmt.Sprintf("%X", root)
+				tx.Result.Proof.Proof.LeafHash = base64.StdEncoding.EncodeToString(proofs[1].LeafHash)
+				for i, aunt := range proofs[1].Aunts {
+					tx.Result.Proof.Proof.Aunts[i] = base64.StdEncoding.EncodeToString(aunt)
+				}
+				return root
+			},
+		},
+		{
+			name: "no proof",
+			tamper: func(tx *types.TxResponse, dataHash []byte) []byte {
+				tx.Result.Proof.Data = ""
+				return dataHash
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx, dataHash := provedTx(txs, 1)
+			err := VerifyTxProof(&tx, tc.tamper(&tx, dataHash))
+			if tc.valid && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !tc.valid && !errors.Is(err, ErrTxProofInvalid) {
+				t.Fatalf("want tx proof invalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProofVerifier(t *testing.T) {
+	tx, dataHash := provedTx(tmtypes.Txs{tmtypes.Tx("tx0"), tmtypes.Tx("tx1")}, 0)
+	var height string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		height = r.URL.Query().Get("height")
+		fmt.Fprintf(w, `{"result":{"block":{"header":{"chain_id":"gon-irishub-1","height":"42","data_hash":"%X"}}}}`, dataHash)
+	}))
+	defer srv.Close()
+
+	cfg := EndpointConfig{ChainId: "gon-irishub-1", ProofRPC: srv.URL}
+	if err := newProofVerifier(cfg, nil).Verify(&tx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if height != "42" {
+		t.Errorf("want header at height 42, got %s", height)
+	}
+
+	cfg.ChainId = "gon-flixnet-1"
+	if err := newProofVerifier(cfg, nil).Verify(&tx); err == nil {
+		t.Error("want an error for the header of another chain")
+	}
+
+	cfg.ProofRPC = ""
+	if pv := newProofVerifier(cfg, nil); pv != nil || pv.Verify(&types.TxResponse{}) != nil {
+		t.Error("want no proof verified without proof rpc")
+	}
+}
+
+func TestEndpointConfigProofRPC(t *testing.T) {
+	ec := defaultEndpoint(ChainIdValueIirs, ChainIdAbbreviationIris, ChainGRPCIris, ChainRPCIris)
+	ec.ProofRPC = ChainRPCIris
+	if err := ec.Validate(); err == nil {
+		t.Error("want an error for a proof rpc being the rpc")
+	}
+	ec.ProofRPC = "rpc.example.com"
+	if err := ec.Validate(); err == nil {
+		t.Error("want an error for an invalid proof rpc")
+	}
+	ec.ProofRPC = "https://rpc.example.com"
+	if err := ec.Validate(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
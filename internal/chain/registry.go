@@ -1,5 +1,11 @@
 package chain
 
+import (
+	"fmt"
+
+	nfttypes "github.com/irisnet/irismod/modules/nft/types"
+)
+
 const (
 	ChainIdAbbreviationIris     = "i"
 	ChainIdAbbreviationStars    = "s"
@@ -29,13 +35,15 @@ const (
 
 type (
 	Class struct {
-		ID      string
-		Name    string
-		Schema  string
-		Creator string
-		Uri     string
-		UriHash string
-		Data    string
+		ID        string
+		Name      string
+		Schema    string
+		Creator   string
+		Uri       string
+		UriHash   string
+		Data      string
+		Symbol    string // cw721 only
+		NumTokens uint64 // cw721 only
 	}
 
 	NFT struct {
@@ -53,30 +61,150 @@ type (
 		HasNFT(classID, nftID string) bool
 		GetClass(classID string) (*Class, error)
 		HasClass(classID string) bool
+		// AtHeight returns the chain whose state is queried at a height, the latest height if 0.
+		// Txs are found by hash whatever the height.
+		AtHeight(height int64) Chain
 		Close()
 	}
 
+	// ClassTracer resolves an ibc class to the class id it originates from.
+	ClassTracer interface {
+		GetOriginalClassId(ibcClassId string) (string, error)
+	}
+
+	// Collector lists all nfts of a class.
+	Collector interface {
+		GetCollection(classID string) (*nfttypes.QueryCollectionResponse, error)
+	}
+
 	Registry struct {
-		chains map[string]Chain
+		chains  map[string]Chain
+		heights map[string]int64 // height a chain is queried at, keyed by abbreviation
 	}
 )
 
-func NewRegistry() *Registry {
-	return &Registry{
-		chains: map[string]Chain{
-			ChainIdAbbreviationIris:     NewIris(),
-			ChainIdAbbreviationStars:    NewStargaze(),
-			ChainIdAbbreviationJuno:     NewJuno(),
-			ChainIdAbbreviationUptick:   NewUptick(),
-			ChainIdAbbreviationOmniflix: NewOmniflix(),
-		},
+// NewRegistry builds a chain for every configured endpoint.
+func NewRegistry(cfg *Config) (*Registry, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var fs *FixtureStore
+	if len(cfg.FixtureDir) != 0 {
+		var err error
+		fs, err = NewFixtureStore(cfg.FixtureDir, cfg.Offline)
+		if err != nil {
+			return nil, err
+		}
 	}
+
+	cr := &Registry{
+		chains: make(map[string]Chain, len(cfg.Chains)),
+	}
+	for _, ec := range cfg.Chains {
+		c, err := newChain(ec, fs)
+		if err != nil {
+			cr.Close()
+			return nil, fmt.Errorf("chain %s: %w", ec.ChainId, err)
+		}
+		cr.chains[ec.Abbreviation] = c
+	}
+	return cr, nil
 }
 
+// NewRegistryFromChains builds a registry of already created chains keyed by abbreviation.
+func NewRegistryFromChains(chains map[string]Chain) *Registry {
+	return &Registry{chains: chains}
+}
+
+func newChain(cfg EndpointConfig, fs *FixtureStore) (Chain, error) {
+	switch cfg.Abbreviation {
+	case ChainIdAbbreviationIris:
+		return NewIris(cfg, fs)
+	case ChainIdAbbreviationStars:
+		return NewStargaze(cfg, fs)
+	case ChainIdAbbreviationJuno:
+		return NewJuno(cfg, fs)
+	case ChainIdAbbreviationUptick:
+		return NewUptick(cfg, fs)
+	case ChainIdAbbreviationOmniflix:
+		return NewOmniflix(cfg, fs)
+	}
+	return nil, fmt.Errorf("unknown chain abbreviation %s", cfg.Abbreviation)
+}
+
+// GetChain returns a chain by abbreviation, a chain left out of the config fails every query.
 func (cr *Registry) GetChain(chainID string) Chain {
-	return cr.chains[chainID]
+	if c, ok := cr.chains[chainID]; ok {
+		return c
+	}
+	return missingChain(chainID)
 }
 
 func (cr *Registry) GetChains() map[string]Chain {
 	return cr.chains
 }
+
+// AtHeights returns a registry whose chains are queried at a height each, keyed by abbreviation.
+// A chain without height is queried at the latest height. The registry shares the connections of cr
+// and must not be closed.
+func (cr *Registry) AtHeights(heights map[string]int64) *Registry {
+	if len(heights) == 0 {
+		return cr
+	}
+	chains := make(map[string]Chain, len(cr.chains))
+	pinned := make(map[string]int64, len(heights))
+	for abbr, c := range cr.chains {
+		if height, ok := heights[abbr]; ok && height > 0 {
+			c = c.AtHeight(height)
+			pinned[abbr] = height
+		}
+		chains[abbr] = c
+	}
+	return &Registry{chains: chains, heights: pinned}
+}
+
+// Height returns the height a chain is queried at, 0 for the latest height.
+func (cr *Registry) Height(abbr string) int64 {
+	return cr.heights[abbr]
+}
+
+// Close closes the connections of all chains.
+func (cr *Registry) Close() {
+	for _, c := range cr.chains {
+		c.Close()
+	}
+}
+
+// missingChain stands for a chain that isn't configured.
+type missingChain string
+
+func (c missingChain) err() error {
+	return fmt.Errorf("chain %s not configured", string(c))
+}
+
+func (c missingChain) GetTx(txHash, txType string) (any, error) {
+	return nil, c.err()
+}
+
+func (c missingChain) GetNFT(classID, nftID string) (*NFT, error) {
+	return nil, c.err()
+}
+
+func (c missingChain) HasNFT(classID, nftID string) bool {
+	return false
+}
+
+func (c missingChain) GetClass(classID string) (*Class, error) {
+	return nil, c.err()
+}
+
+func (c missingChain) HasClass(classID string) bool {
+	return false
+}
+
+func (c missingChain) AtHeight(height int64) Chain {
+	return c
+}
+
+func (c missingChain) Close() {}
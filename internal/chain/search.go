@@ -0,0 +1,69 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/taramakage/gon-verifier/internal/types"
+)
+
+const (
+	// searchPerPage is the page size of tx_search, the maximum of the nodes.
+	searchPerPage = 100
+	// searchMaxTxs bounds the txs of a search, a participant doesn't send that many.
+	searchMaxTxs = 1000
+)
+
+// TxSearcher finds txs with a tendermint query, e.g. "message.sender='iaa1...' AND send_packet.packet_sequence EXISTS".
+// The txs are in ascending order of height.
+type TxSearcher interface {
+	SearchTxs(query string) ([]types.TxResponse, error)
+}
+
+// searchTxs pages through the tx_search endpoint of a node until limit txs or all of them are read.
+func searchTxs(client *http.Client, rpc, query string, limit int) ([]types.TxResponse, error) {
+	perPage := searchPerPage
+	if limit < perPage {
+		perPage = limit
+	}
+
+	var txs []types.TxResponse
+	for page := 1; len(txs) < limit; page++ {
+		u := fmt.Sprintf("%stx_search?query=%s&page=%d&per_page=%d&order_by=%s",
+			rpc, url.QueryEscape(`"`+query+`"`), page, perPage, url.QueryEscape(`"asc"`))
+		body, err := getRespWithRetry(client, u)
+		if err != nil {
+			return nil, err
+		}
+
+		var data struct {
+			Result struct {
+				Txs        []json.RawMessage `json:"txs"`
+				TotalCount string            `json:"total_count"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(body, &data); err != nil {
+			return nil, err
+		}
+		for _, raw := range data.Result.Txs {
+			// a tx of tx_search is the result of the tx endpoint
+			var tx types.TxResponse
+			if err := json.Unmarshal(raw, &tx.Result); err != nil {
+				return nil, err
+			}
+			txs = append(txs, tx)
+		}
+
+		total, _ := strconv.Atoi(data.Result.TotalCount)
+		if len(data.Result.Txs) == 0 || len(txs) >= total {
+			break
+		}
+	}
+	if len(txs) > limit {
+		txs = txs[:limit]
+	}
+	return txs, nil
+}
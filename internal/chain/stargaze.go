@@ -1,39 +1,45 @@
 package chain
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/taramakage/gon-verifier/internal/types"
 	wasmtype "github.com/taramakage/gon-verifier/internal/types/wasm"
 	"google.golang.org/grpc"
+	"net/http"
+	"time"
 )
 
 type Stargaze struct {
+	rpc        string
+	httpClient *http.Client
+	proof      *ProofVerifier
 	conn       *grpc.ClientConn
-	wasmClient wasmtype.QueryClient
+	ics721Client
 }
 
-func NewStargaze() *Stargaze {
-	conn, err := grpc.Dial(
-		ChainGRPCStars,
-		grpc.WithInsecure(),
-		grpc.WithDefaultCallOptions(),
-	)
+func NewStargaze(cfg EndpointConfig, fs *FixtureStore) (*Stargaze, error) {
+	conn, err := dial(cfg, fs)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return &Stargaze{
+		rpc:        cfg.rpcBase(),
+		httpClient: fs.httpClient(cfg),
+		proof:      newProofVerifier(cfg, fs),
 		conn:       conn, // NOTE: Close this connection when the program exits
-		wasmClient: wasmtype.NewQueryClient(conn),
-	}
+		ics721Client: ics721Client{
+			cw721Client: cw721Client{wasmClient: wasmtype.NewQueryClient(conn)},
+			bridge:      cfg.ics721Bridge(),
+		},
+	}, nil
 }
 
 func (s *Stargaze) GetTx(txHash, txType string) (any, error) {
 	txHash = "0x" + txHash
-	url := fmt.Sprintf(ChainRPCStars+"tx?hash=%s&prove=true", txHash)
-	body, err := getRespWithRetry(url)
+	url := fmt.Sprintf(s.rpc+"tx?hash=%s&prove=true", txHash)
+	body, err := getRespWithRetry(s.httpClient, url)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +48,9 @@ func (s *Stargaze) GetTx(txHash, txType string) (any, error) {
 	if err := json.Unmarshal(body, &data); err != nil {
 		return nil, err
 	}
+	if err := s.proof.Verify(&data); err != nil {
+		return nil, err
+	}
 
 	switch txType {
 	case types.TxResultTypeIbcNft:
@@ -55,34 +64,9 @@ func (s *Stargaze) getTxResultIbcNft(data *types.TxResponse) (any, error) {
 	return data.IbcNftPkg()
 }
 
+// GetNFT returns the token of a cw721 contract with its owner, uri and extension.
 func (s *Stargaze) GetNFT(classID, nftID string) (*NFT, error) {
-	wq := WasmQueryNFT{
-		NftInfo: NftInfo{nftID},
-	}
-	// convert wq to json string
-	bz, err := json.Marshal(wq)
-	if err != nil {
-		return nil, err
-	}
-
-	req := &wasmtype.QuerySmartContractStateRequest{
-		Address:   classID,
-		QueryData: bz,
-	}
-	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return s.wasmClient.SmartContractState(context.Background(), req)
-	})
-	if err != nil {
-		return nil, err
-	}
-	_, ok := resi.(*wasmtype.QuerySmartContractStateResponse)
-	if !ok {
-		return nil, err
-	}
-
-	return &NFT{
-		ID: nftID,
-	}, nil
+	return s.getNFT(classID, nftID)
 }
 
 func (s *Stargaze) HasNFT(classID, nftID string) bool {
@@ -93,36 +77,9 @@ func (s *Stargaze) HasNFT(classID, nftID string) bool {
 	return true
 }
 
+// GetClass returns the name, symbol and number of tokens of a cw721 contract.
 func (s *Stargaze) GetClass(classID string) (*Class, error) {
-	wq := WasmQueryClass{}
-	// convert wq to json string
-	bz, err := json.Marshal(wq)
-	if err != nil {
-		return nil, err
-	}
-
-	req := &wasmtype.QuerySmartContractStateRequest{
-		Address:   classID,
-		QueryData: bz,
-	}
-	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return s.wasmClient.SmartContractState(context.Background(), req)
-	})
-	if err != nil {
-		return nil, err
-	}
-	res, ok := resi.(*wasmtype.QuerySmartContractStateResponse)
-	if !ok {
-		return nil, err
-	}
-
-	wr := WasmRespClass{}
-	err = json.Unmarshal(res.Data, &wr)
-	if err != nil {
-		return nil, nil
-	}
-
-	return &Class{}, nil
+	return s.getClass(classID)
 }
 
 func (s *Stargaze) HasClass(classID string) bool {
@@ -133,8 +90,30 @@ func (s *Stargaze) HasClass(classID string) bool {
 	return true
 }
 
+// AtHeight returns the chain whose contracts are queried at a height.
+func (s *Stargaze) AtHeight(height int64) Chain {
+	pinned := *s
+	pinned.height = height
+	return &pinned
+}
+
 func (s *Stargaze) Close() {
 	if s.conn != nil {
 		s.conn.Close()
 	}
 }
+
+// GetPacketTx returns the tx emitting the event of a packet.
+func (s *Stargaze) GetPacketTx(event string, p Packet) (*types.TxResponse, error) {
+	return searchPacketTx(s.httpClient, s.rpc, event, p)
+}
+
+// SearchTxs returns the txs matching a tendermint query.
+func (s *Stargaze) SearchTxs(query string) ([]types.TxResponse, error) {
+	return searchTxs(s.httpClient, s.rpc, query, searchMaxTxs)
+}
+
+// BlockTime returns the time of the block at a height.
+func (s *Stargaze) BlockTime(height int64) (time.Time, error) {
+	return blockTime(s.httpClient, s.rpc, height)
+}
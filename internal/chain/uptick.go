@@ -1,39 +1,43 @@
 package chain
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	nfttypes "github.com/UptickNetwork/uptick/x/collection/types"
 	"github.com/taramakage/gon-verifier/internal/types"
 	"google.golang.org/grpc"
+	"net/http"
+	"time"
 )
 
 type Uptick struct {
-	conn      *grpc.ClientConn
-	nftClient nfttypes.QueryClient
+	rpc        string
+	httpClient *http.Client
+	proof      *ProofVerifier
+	conn       *grpc.ClientConn
+	height     int64 // height of the state queries, the latest if 0
+	nftClient  nfttypes.QueryClient
 }
 
-func NewUptick() *Uptick {
-	conn, err := grpc.Dial(
-		ChainGRPCUptick,
-		grpc.WithInsecure(),
-		grpc.WithDefaultCallOptions(),
-	)
+func NewUptick(cfg EndpointConfig, fs *FixtureStore) (*Uptick, error) {
+	conn, err := dial(cfg, fs)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return &Uptick{
-		conn:      conn,
-		nftClient: nfttypes.NewQueryClient(conn),
-	}
+		rpc:        cfg.rpcBase(),
+		httpClient: fs.httpClient(cfg),
+		proof:      newProofVerifier(cfg, fs),
+		conn:       conn,
+		nftClient:  nfttypes.NewQueryClient(conn),
+	}, nil
 }
 
 func (u *Uptick) GetTx(txHash, txType string) (any, error) {
 	txHash = "0x" + txHash
-	url := fmt.Sprintf(ChainRPCUptick+"tx?hash=%s&prove=true", txHash)
-	body, err := getRespWithRetry(url)
+	url := fmt.Sprintf(u.rpc+"tx?hash=%s&prove=true", txHash)
+	body, err := getRespWithRetry(u.httpClient, url)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +48,9 @@ func (u *Uptick) GetTx(txHash, txType string) (any, error) {
 		fmt.Printf("Error unmarshalling JSON: %s\n", err.Error())
 		return nil, err
 	}
+	if err := u.proof.Verify(&data); err != nil {
+		return nil, err
+	}
 
 	switch txType {
 	case types.TxResultTypeIbcNft:
@@ -64,7 +71,7 @@ func (u *Uptick) GetNFT(classID, nftID string) (*NFT, error) {
 	}
 
 	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return u.nftClient.NFT(context.Background(), req)
+		return u.nftClient.NFT(heightContext(u.height), req)
 	})
 	if err != nil {
 		return nil, err
@@ -96,7 +103,7 @@ func (u *Uptick) GetClass(classID string) (*Class, error) {
 	}
 
 	resi, err := withGrpcRetry(func() (interface{}, error) {
-		return u.nftClient.Denom(context.Background(), req)
+		return u.nftClient.Denom(heightContext(u.height), req)
 	})
 	if err != nil {
 		return nil, err
@@ -125,8 +132,30 @@ func (u *Uptick) HasClass(classID string) bool {
 	return true
 }
 
+// AtHeight returns the chain whose state is queried at a height.
+func (u *Uptick) AtHeight(height int64) Chain {
+	pinned := *u
+	pinned.height = height
+	return &pinned
+}
+
 func (u *Uptick) Close() {
 	if u.conn != nil {
 		u.conn.Close()
 	}
 }
+
+// GetPacketTx returns the tx emitting the event of a packet.
+func (u *Uptick) GetPacketTx(event string, p Packet) (*types.TxResponse, error) {
+	return searchPacketTx(u.httpClient, u.rpc, event, p)
+}
+
+// SearchTxs returns the txs matching a tendermint query.
+func (u *Uptick) SearchTxs(query string) ([]types.TxResponse, error) {
+	return searchTxs(u.httpClient, u.rpc, query, searchMaxTxs)
+}
+
+// BlockTime returns the time of the block at a height.
+func (u *Uptick) BlockTime(height int64) (time.Time, error) {
+	return blockTime(u.httpClient, u.rpc, height)
+}
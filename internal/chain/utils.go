@@ -1,42 +1,77 @@
 package chain
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 )
 
-type WasmQueryNFT struct {
-	NftInfo NftInfo `json:"nft_info"`
-}
-type NftInfo struct {
-	TokenId string `json:"token_id"`
-}
+// GRPCBlockHeightHeader is the grpc metadata a cosmos node answers a query at a past height with.
+const GRPCBlockHeightHeader = "x-cosmos-block-height"
 
-type WasmRespNFT struct {
+// heightContext returns the context of a grpc query at a height, the latest height if 0.
+func heightContext(height int64) context.Context {
+	ctx := context.Background()
+	if height <= 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
 }
 
-type WasmQueryClass struct {
-	NumTokens NumTokens `json:"num_tokens"`
-}
-type NumTokens struct {
+// dial opens a grpc connection to the endpoint, every call on it is bounded by the endpoint timeout
+// and goes through the fixture store if any.
+func dial(cfg EndpointConfig, fs *FixtureStore) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if cfg.TLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+
+	interceptors := []grpc.UnaryClientInterceptor{timeoutInterceptor(cfg.Timeout)}
+	if fs != nil {
+		interceptors = append(interceptors, fs.interceptor(cfg.ChainId))
+	}
+
+	return grpc.Dial(
+		cfg.GRPC,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(),
+		grpc.WithChainUnaryInterceptor(interceptors...),
+	)
 }
 
-type WasmRespClass struct {
-	Count int `json:"count"`
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
 }
 
-func getRespWithRetry(url string) ([]byte, error) {
+func getRespWithRetry(client *http.Client, url string) ([]byte, error) {
 	var body []byte
 	var resp *http.Response
 	var err error
 	maxRetries := 3
 
 	for i := 1; i <= maxRetries; i++ {
-		resp, err = http.Get(url)
+		resp, err = client.Get(url)
+		if errors.Is(err, ErrFixtureNotFound) {
+			return body, err
+		}
 		if err != nil || resp.StatusCode != 200 {
 			if i == maxRetries {
 				return body, err
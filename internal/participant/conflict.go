@@ -0,0 +1,71 @@
+package participant
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+)
+
+// Kinds of conflict between participants.
+const (
+	ConflictAddress = "address"
+	ConflictTeam    = "team"
+)
+
+// Conflict is a value registered by several participants.
+type Conflict struct {
+	Kind  string
+	Chain string // abbreviation of the chain of an address
+	Value string
+	Ids   []string // participants registering the value in order
+}
+
+func (c Conflict) String() string {
+	if c.Kind == ConflictAddress {
+		return fmt.Sprintf("%s address %s registered by %s", chain.ChainNames[c.Chain], c.Value, strings.Join(c.Ids, ", "))
+	}
+	return fmt.Sprintf("team %q registered by %s", c.Value, strings.Join(c.Ids, ", "))
+}
+
+// Conflicts returns the addresses of a chain and the teams registered by several participants, the addresses
+// first by chain, each kind sorted by value. Case doesn't matter, nor spaces, _ and - in team names.
+func (r *Registry) Conflicts() []Conflict {
+	var conflicts []Conflict
+	for _, abbr := range chain.Abbreviations {
+		conflicts = append(conflicts, duplicates(r.All(), ConflictAddress, abbr, func(p *Participant) (string, string) {
+			return strings.ToLower(p.Addresses[abbr]), p.Addresses[abbr]
+		})...)
+	}
+	return append(conflicts, duplicates(r.All(), ConflictTeam, "", func(p *Participant) (string, string) {
+		return normalize(p.Team), p.Team
+	})...)
+}
+
+// duplicates groups the participants by the key of a value, empty keys are left out.
+func duplicates(participants []*Participant, kind, chainAbbr string, value func(p *Participant) (key, v string)) []Conflict {
+	groups := make(map[string]*Conflict)
+	for _, p := range participants {
+		key, v := value(p)
+		if len(key) == 0 {
+			continue
+		}
+		if c, ok := groups[key]; ok {
+			c.Ids = append(c.Ids, p.Id)
+			continue
+		}
+		groups[key] = &Conflict{Kind: kind, Chain: chainAbbr, Value: v, Ids: []string{p.Id}}
+	}
+
+	var conflicts []Conflict
+	for _, c := range groups {
+		if len(c.Ids) > 1 {
+			conflicts = append(conflicts, *c)
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Value < conflicts[j].Value
+	})
+	return conflicts
+}
@@ -0,0 +1,228 @@
+package participant
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+)
+
+type (
+	// Participant is a registered participant, its evidence, results and ranks are joined on its id.
+	Participant struct {
+		Id        string
+		Github    string // without the leading @
+		Discord   string
+		Team      string
+		Addresses map[string]string // keyed by chain abbreviation
+	}
+
+	// Registry is the participants of a campaign. A nil registry has no participant.
+	Registry struct {
+		participants []*Participant
+		ids          map[string]*Participant
+	}
+
+	// entry is a participant in a JSON file, its addresses are keyed by chain name, e.g. iris.
+	entry struct {
+		Id        string            `json:"id"`
+		Github    string            `json:"github"`
+		Discord   string            `json:"discord"`
+		Team      string            `json:"team"`
+		Addresses map[string]string `json:"addresses"`
+	}
+)
+
+// Load reads the participants of a .json or .csv file.
+func Load(file string) (*Registry, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var entries []entry
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(bz))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&entries); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+	case ".csv":
+		entries, err = readCSV(bz)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown participants format %q, want .json or .csv", filepath.Ext(file))
+	}
+
+	participants := make([]Participant, 0, len(entries))
+	for i, e := range entries {
+		p, err := e.participant()
+		if err != nil {
+			return nil, fmt.Errorf("participant %d: %w", i+1, err)
+		}
+		participants = append(participants, p)
+	}
+	return NewRegistry(participants)
+}
+
+// readCSV reads a header of id, github, discord, team and the chain names, e.g. iris or iris address, and a
+// participant per row.
+func readCSV(bz []byte) ([]entry, error) {
+	rows, err := csv.NewReader(bytes.NewReader(bz)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("header not found")
+	}
+
+	chains := make(map[string]string, len(chain.ChainNames))
+	for _, name := range chain.ChainNames {
+		chains[name] = name
+		chains[name+"address"] = name
+	}
+	header := rows[0]
+	for j, cell := range header {
+		name := normalize(cell)
+		switch name {
+		case "id", "github", "discord", "team":
+		default:
+			if _, ok := chains[name]; !ok {
+				return nil, fmt.Errorf("unknown column %q", cell)
+			}
+		}
+		header[j] = name
+	}
+
+	entries := make([]entry, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		e := entry{Addresses: make(map[string]string)}
+		for j, cell := range row {
+			cell = strings.TrimSpace(cell)
+			switch header[j] {
+			case "id":
+				e.Id = cell
+			case "github":
+				e.Github = cell
+			case "discord":
+				e.Discord = cell
+			case "team":
+				e.Team = cell
+			default:
+				if len(cell) != 0 {
+					e.Addresses[chains[header[j]]] = cell
+				}
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// participant checks an entry and keys its addresses by chain abbreviation.
+func (e entry) participant() (Participant, error) {
+	p := Participant{
+		Id:        strings.TrimSpace(e.Id),
+		Github:    strings.TrimPrefix(strings.TrimSpace(e.Github), "@"),
+		Discord:   strings.TrimSpace(e.Discord),
+		Team:      strings.TrimSpace(e.Team),
+		Addresses: make(map[string]string, len(e.Addresses)),
+	}
+	if len(p.Id) == 0 {
+		return Participant{}, errors.New("missing id")
+	}
+	abbrs := make(map[string]string, len(chain.ChainNames))
+	for abbr, name := range chain.ChainNames {
+		abbrs[name] = abbr
+	}
+	for name, addr := range e.Addresses {
+		abbr, ok := abbrs[name]
+		if !ok {
+			return Participant{}, fmt.Errorf("%s: address of unknown chain %q", p.Id, name)
+		}
+		p.Addresses[abbr] = strings.TrimSpace(addr)
+	}
+	return p, nil
+}
+
+// NewRegistry indexes the participants by id, which must be unique.
+func NewRegistry(participants []Participant) (*Registry, error) {
+	r := &Registry{ids: make(map[string]*Participant, len(participants))}
+	for i := range participants {
+		p := &participants[i]
+		if _, ok := r.ids[p.Id]; ok {
+			return nil, fmt.Errorf("duplicate participant id %q", p.Id)
+		}
+		r.ids[p.Id] = p
+		r.participants = append(r.participants, p)
+	}
+	return r, nil
+}
+
+// All returns the participants in the order of the file.
+func (r *Registry) All() []*Participant {
+	if r == nil {
+		return nil
+	}
+	return r.participants
+}
+
+// Get returns the participant of an id.
+func (r *Registry) Get(id string) (*Participant, bool) {
+	if r == nil {
+		return nil, false
+	}
+	p, ok := r.ids[id]
+	return p, ok
+}
+
+// Resolve returns the participant of an evidence: the one of the id, the directory of the evidence, or else the
+// only one registering any of the addresses of the evidence, keyed by chain abbreviation.
+func (r *Registry) Resolve(id string, addresses map[string]string) (*Participant, error) {
+	if p, ok := r.Get(id); ok {
+		return p, nil
+	}
+	var ids []string
+	matched := make(map[string]bool)
+	for _, p := range r.All() {
+		for abbr, addr := range addresses {
+			if len(addr) != 0 && sameAddress(p.Addresses[abbr], addr) && !matched[p.Id] {
+				matched[p.Id] = true
+				ids = append(ids, p.Id)
+			}
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return nil, fmt.Errorf("no participant registered with id %q or its addresses", id)
+	case 1:
+		return r.ids[ids[0]], nil
+	}
+	sort.Strings(ids)
+	return nil, fmt.Errorf("addresses registered by several participants: %s", strings.Join(ids, ", "))
+}
+
+// sameAddress compares bech32 addresses, which are case insensitive.
+func sameAddress(a, b string) bool {
+	return len(a) != 0 && strings.EqualFold(a, b)
+}
+
+// normalize folds the case and drops spaces, _ and - of a header or a team name.
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(s)))
+}
@@ -0,0 +1,126 @@
+package participant
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoad(t *testing.T) {
+	want := []*Participant{
+		{Id: "p1", Github: "alice", Discord: "alice#1", Team: "Wolves", Addresses: map[string]string{
+			chain.ChainIdAbbreviationIris:  "iaa1alice",
+			chain.ChainIdAbbreviationStars: "stars1alice",
+		}},
+		{Id: "p2", Github: "bob", Team: "Bears", Addresses: map[string]string{
+			chain.ChainIdAbbreviationIris: "iaa1bob",
+		}},
+	}
+	files := []string{
+		writeFile(t, "participants.csv", "ID,GitHub,Discord,Team,iris address,stars\n"+
+			"p1,@alice,alice#1,Wolves,iaa1alice,stars1alice\n"+
+			"p2,bob,,Bears,iaa1bob,\n"),
+		writeFile(t, "participants.json", `[
+	{"id": "p1", "github": "@alice", "discord": "alice#1", "team": "Wolves", "addresses": {"iris": "iaa1alice", "stars": "stars1alice"}},
+	{"id": "p2", "github": "bob", "team": "Bears", "addresses": {"iris": "iaa1bob"}}
+]`),
+	}
+	for _, file := range files {
+		r, err := Load(file)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if !reflect.DeepEqual(r.All(), want) {
+			t.Errorf("%s: want %+v, got %+v", file, want, r.All())
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for name, content := range map[string]string{
+		"duplicate.json": `[{"id": "p1"}, {"id": "p1"}]`,
+		"noid.json":      `[{"github": "alice"}]`,
+		"chain.json":     `[{"id": "p1", "addresses": {"cosmos": "cosmos1alice"}}]`,
+		"field.json":     `[{"id": "p1", "twitter": "alice"}]`,
+		"column.csv":     "id,twitter\np1,alice\n",
+		"format.yaml":    "- id: p1\n",
+	} {
+		if _, err := Load(writeFile(t, name, content)); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r, err := NewRegistry([]Participant{
+		{Id: "p1", Addresses: map[string]string{chain.ChainIdAbbreviationIris: "iaa1alice"}},
+		{Id: "p2", Addresses: map[string]string{chain.ChainIdAbbreviationStars: "stars1bob"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		id        string
+		addresses map[string]string
+		want      string
+	}{
+		{"p2", map[string]string{chain.ChainIdAbbreviationIris: "iaa1alice"}, "p2"},
+		{"alice", map[string]string{chain.ChainIdAbbreviationIris: "IAA1ALICE"}, "p1"},
+		{"carol", map[string]string{chain.ChainIdAbbreviationIris: "iaa1carol"}, ""},
+		{"both", map[string]string{chain.ChainIdAbbreviationIris: "iaa1alice", chain.ChainIdAbbreviationStars: "stars1bob"}, ""},
+	} {
+		p, err := r.Resolve(tc.id, tc.addresses)
+		if len(tc.want) == 0 {
+			if err == nil {
+				t.Errorf("%s: want an error, got %s", tc.id, p.Id)
+			}
+			continue
+		}
+		if err != nil || p.Id != tc.want {
+			t.Errorf("%s: want %s, got %v %v", tc.id, tc.want, p, err)
+		}
+	}
+
+	var none *Registry
+	if _, err := none.Resolve("p1", nil); err == nil {
+		t.Error("want an error without registry")
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	r, err := NewRegistry([]Participant{
+		{Id: "p1", Team: "Wolves", Addresses: map[string]string{chain.ChainIdAbbreviationIris: "iaa1alice", chain.ChainIdAbbreviationJuno: "juno1x"}},
+		{Id: "p2", Team: "the-wolves", Addresses: map[string]string{chain.ChainIdAbbreviationIris: "iaa1bob", chain.ChainIdAbbreviationJuno: "juno1x"}},
+		{Id: "p3", Team: "The Wolves", Addresses: map[string]string{chain.ChainIdAbbreviationIris: "IAA1ALICE"}},
+		{Id: "p4", Addresses: map[string]string{chain.ChainIdAbbreviationStars: "juno1x"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range r.Conflicts() {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"iris address iaa1alice registered by p1, p3",
+		"juno address juno1x registered by p1, p2",
+		`team "the-wolves" registered by p2, p3`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
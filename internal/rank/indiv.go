@@ -3,12 +3,13 @@ package rank
 import (
 	"errors"
 	"fmt"
-	"github.com/taramakage/gon-verifier/internal/scorecard"
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/participant"
+	"github.com/taramakage/gon-verifier/internal/taskpoint"
 	"github.com/xuri/excelize/v2"
 	"os"
 	"path/filepath"
 	"sort"
-	"strings"
 )
 
 type IndivRanker struct {
@@ -17,9 +18,11 @@ type IndivRanker struct {
 	TaskPoint      int32
 	IndivRaceInfos []IndivRaceInfo
 	Entrance       string
+	Participants   *participant.Registry
+	TaskPointFile  string // task point file of the stage of the target
 }
 
-func NewIndivRanker(entrance, targetTaskNo, taskNo string, taskPoint int32) *IndivRanker {
+func NewIndivRanker(c *campaign.Campaign, participants *participant.Registry, entrance, targetTaskNo, taskNo string, taskPoint int32) *IndivRanker {
 	indivRaceInfos := make([]IndivRaceInfo, 0)
 	return &IndivRanker{
 		TargetTaskNo:   targetTaskNo,
@@ -27,6 +30,8 @@ func NewIndivRanker(entrance, targetTaskNo, taskNo string, taskPoint int32) *Ind
 		TaskPoint:      taskPoint,
 		IndivRaceInfos: indivRaceInfos,
 		Entrance:       entrance,
+		Participants:   participants,
+		TaskPointFile:  taskPointFileOf(c.StageOf(targetTaskNo)),
 	}
 }
 
@@ -57,7 +62,7 @@ func (ir *IndivRanker) Collect() error {
 			fmt.Printf("Error accessing path %q: %v\n", path, err)
 			return err
 		}
-		if info.IsDir() || info.Name() != scorecard.DefaultStageThreeTaskPoint {
+		if info.IsDir() || info.Name() != ir.TaskPointFile {
 			return nil
 		}
 		files = append(files, path)
@@ -91,23 +96,23 @@ func (ir *IndivRanker) loadRaceInfo(file string) error {
 
 	var indivRace *IndivRaceInfo = nil
 	for _, row := range rows {
-		if len(row) < 4 {
+		if len(row) == 0 || row[0] != ir.TargetTaskNo {
 			continue
 		}
-		if row[0] == ir.TargetTaskNo && strings.HasPrefix(row[3], "race") {
-			raceInfo, err := BuildRaceInfo(row[3])
-			if err != nil {
-				return err
-			}
-			if raceInfo != nil {
-				indivRace = &IndivRaceInfo{
-					RaceInfo: *raceInfo,
-					teamName: row[1],
-					path:     file,
-				}
+		raceInfo, err := taskpoint.ReadRaceResult(row)
+		if err != nil {
+			return err
+		}
+		if raceInfo != nil {
+			id := taskpoint.ReadParticipant(row)
+			indivRace = &IndivRaceInfo{
+				RaceResult:  *raceInfo,
+				participant: id,
+				teamName:    teamOf(ir.Participants, id, row[1]),
+				path:        file,
 			}
-			break
 		}
+		break
 	}
 
 	if indivRace != nil {
@@ -119,12 +124,12 @@ func (ir *IndivRanker) loadRaceInfo(file string) error {
 
 func (ir *IndivRanker) Sort() {
 	sort.SliceStable(ir.IndivRaceInfos, func(i, j int) bool {
-		ri1 := ir.IndivRaceInfos[i].RaceInfo
-		ri2 := ir.IndivRaceInfos[j].RaceInfo
-		if ri1.diff == ri2.diff {
-			return ri1.start < ri2.start
+		ri1 := ir.IndivRaceInfos[i].RaceResult
+		ri2 := ir.IndivRaceInfos[j].RaceResult
+		if ri1.Diff == ri2.Diff {
+			return ri1.StartHeight < ri2.StartHeight
 		}
-		return ri1.diff < ri2.diff
+		return ri1.Diff < ri2.Diff
 	})
 }
 
@@ -143,13 +148,15 @@ func (ir *IndivRanker) GenerateRank() error {
 	f.SetCellValue(sheetName, "C1", "DiffHeight")
 	f.SetCellValue(sheetName, "D1", "StartHeight")
 	f.SetCellValue(sheetName, "E1", "EndHeight")
+	f.SetCellValue(sheetName, "F1", "Participant")
 
 	for i, indivRaceInfo := range ir.IndivRaceInfos {
 		f.SetCellValue(sheetName, fmt.Sprintf("A%d", i+2), i+1)
 		f.SetCellValue(sheetName, fmt.Sprintf("B%d", i+2), indivRaceInfo.teamName)
-		f.SetCellValue(sheetName, fmt.Sprintf("C%d", i+2), indivRaceInfo.diff)
-		f.SetCellValue(sheetName, fmt.Sprintf("D%d", i+2), indivRaceInfo.start)
-		f.SetCellValue(sheetName, fmt.Sprintf("E%d", i+2), indivRaceInfo.end)
+		f.SetCellValue(sheetName, fmt.Sprintf("C%d", i+2), indivRaceInfo.Diff)
+		f.SetCellValue(sheetName, fmt.Sprintf("D%d", i+2), indivRaceInfo.StartHeight)
+		f.SetCellValue(sheetName, fmt.Sprintf("E%d", i+2), indivRaceInfo.EndHeight)
+		f.SetCellValue(sheetName, fmt.Sprintf("F%d", i+2), indivRaceInfo.participant)
 	}
 
 	f.SetActiveSheet(index)
@@ -221,6 +228,9 @@ func (ir *IndivRanker) writeTaskPoint(indivRaceInfo IndivRaceInfo) error {
 	file.SetCellValue(sheetName, fmt.Sprintf("A%d", length+1), ir.TaskNo)
 	file.SetCellValue(sheetName, fmt.Sprintf("B%d", length+1), indivRaceInfo.teamName)
 	file.SetCellValue(sheetName, fmt.Sprintf("C%d", length+1), ir.TaskPoint)
+	if len(indivRaceInfo.participant) != 0 {
+		file.SetCellValue(sheetName, taskpoint.Cell(taskpoint.ParticipantColumn, length+1), indivRaceInfo.participant)
+	}
 
 	err = file.Save()
 	if err != nil {
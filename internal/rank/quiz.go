@@ -3,10 +3,10 @@ package rank
 import (
 	"errors"
 	"fmt"
+	"github.com/taramakage/gon-verifier/internal/campaign"
 	"github.com/taramakage/gon-verifier/internal/chain"
-	"github.com/taramakage/gon-verifier/internal/scorecard"
+	"github.com/taramakage/gon-verifier/internal/taskpoint"
 	"github.com/xuri/excelize/v2"
-	"os"
 	"path"
 	"path/filepath"
 	"sort"
@@ -15,34 +15,47 @@ import (
 )
 
 type QuizRanker struct {
-	TaskNo    string
-	TaskPoint int32
-	Entrance  string
-	Quizers   []Quizer
-	r         *chain.Registry
-	f         *chain.Flow
+	TaskNo        string
+	TaskPoint     int32
+	Entrance      string
+	Quizers       []Quizer
+	TaskPointFile string // task point file of the stage holding the races, the quiz points are written to it
+	r             *chain.Registry
+	f             *chain.Flow
+	entrants      []Entrant
 }
 
 type Quizer struct {
-	TeamName string
-	Address  string
-	Count    int
-	Path     string
+	Participant string
+	TeamName    string
+	Address     string
+	Count       int
+	Path        string
 }
 
-func NewQuizRanker(entrance, taskNo string, taskPoint int32) *QuizRanker {
-	f, err := chain.NewFlow(chain.FlowStrMap["f04"])
+// Entrant is a participant whose evidence is found under the entrance, as read by the verifier.
+type Entrant struct {
+	Participant string
+	TeamName    string
+	Address     map[string]string // keyed by chain abbreviation
+	Path        string            // evidence of the participant
+}
+
+func NewQuizRanker(c *campaign.Campaign, r *chain.Registry, entrants []Entrant, entrance, taskNo string, taskPoint int32) *QuizRanker {
+	f, err := chain.LookupFlow("f04")
 	if err != nil {
 		return nil
 	}
 
 	return &QuizRanker{
-		Entrance:  entrance,
-		TaskNo:    taskNo,
-		TaskPoint: taskPoint,
-		Quizers:   make([]Quizer, 0),
-		f:         f,
-		r:         chain.NewRegistry(),
+		Entrance:      entrance,
+		TaskNo:        taskNo,
+		TaskPoint:     taskPoint,
+		Quizers:       make([]Quizer, 0),
+		TaskPointFile: taskPointFileOf(c.RankStage(campaign.Rank{Task: taskNo, Kind: campaign.RankQuiz})),
+		f:             f,
+		r:             r,
+		entrants:      entrants,
 	}
 }
 
@@ -82,7 +95,7 @@ func (qr *QuizRanker) collectNft() error {
 	}
 	ibcClassId := "ibc/" + hash.String()
 	c := qr.r.GetChain(chain.ChainIdAbbreviationIris)
-	iris, ok := c.(*chain.Iris)
+	iris, ok := c.(chain.Collector)
 	if !ok {
 		return errors.New("failed to get chain")
 	}
@@ -109,50 +122,24 @@ func (qr *QuizRanker) collectNft() error {
 	return nil
 }
 
-// collectQuizer walk participants' evidence file and read info of quizer
+// collectQuizers matches the owners of the quiz nfts with the iris address of the entrants
 func (qr *QuizRanker) collectQuizers() {
-	strs := make([]string, 0)
-
-	err := filepath.Walk(qr.Entrance, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			fmt.Printf("Error accessing path %q: %v\n", path, err)
-			return err
-		}
-
-		if info.IsDir() || info.Name() != scorecard.DefaultEvidenceFile {
-			return nil
-		}
-		strs = append(strs, path)
-		return nil
-	})
-	if err != nil {
-		err.Error()
-	}
-
-	for _, str := range strs {
-		qr.collectQuizer(str)
+	for _, entrant := range qr.entrants {
+		qr.collectQuizer(entrant)
 	}
 }
 
-func (qr *QuizRanker) collectQuizer(evidence string) {
-	f, err := excelize.OpenFile(evidence)
-	defer f.Close()
-
-	rows, err := f.GetRows("Info")
-	if err != nil || len(rows) < 2 {
-		return
-	}
-
-	teamName := rows[1][0]
-	addr := rows[1][1]
+func (qr *QuizRanker) collectQuizer(entrant Entrant) {
+	addr := entrant.Address[chain.ChainIdAbbreviationIris]
 
 	for i, quizer := range qr.Quizers {
-		if len(quizer.TeamName) != 0 {
+		if len(quizer.Path) != 0 {
 			continue
 		}
 		if quizer.Address == addr {
-			qr.Quizers[i].TeamName = teamName
-			qr.Quizers[i].Path = evidence
+			qr.Quizers[i].Participant = entrant.Participant
+			qr.Quizers[i].TeamName = entrant.TeamName
+			qr.Quizers[i].Path = entrant.Path
 			break
 		}
 	}
@@ -177,11 +164,13 @@ func (qr *QuizRanker) GenerateRank() error {
 	f.SetCellValue(sheetName, "A1", "Rank")
 	f.SetCellValue(sheetName, "B1", "TeamName")
 	f.SetCellValue(sheetName, "C1", "QuizContent")
+	f.SetCellValue(sheetName, "D1", "Participant")
 
 	for i, quizer := range qr.Quizers {
 		f.SetCellValue(sheetName, fmt.Sprintf("A%d", i+2), i+1)
 		f.SetCellValue(sheetName, fmt.Sprintf("B%d", i+2), quizer.TeamName)
 		f.SetCellValue(sheetName, fmt.Sprintf("C%d", i+2), quizer.Count)
+		f.SetCellValue(sheetName, fmt.Sprintf("D%d", i+2), quizer.Participant)
 	}
 
 	f.SetActiveSheet(index)
@@ -209,8 +198,7 @@ func (qr *QuizRanker) WriteTaskPoint() error {
 
 func (qr *QuizRanker) clearLegacyTaskPoint(quizer Quizer) error {
 	dir := path.Dir(quizer.Path)
-	taskpoint3 := path.Join(dir, "taskpoint3.xlsx")
-	file, err := excelize.OpenFile(taskpoint3)
+	file, err := excelize.OpenFile(path.Join(dir, qr.TaskPointFile))
 	if err != nil {
 		return err
 	}
@@ -239,8 +227,7 @@ func (qr *QuizRanker) clearLegacyTaskPoint(quizer Quizer) error {
 
 func (qr *QuizRanker) writeTaskPoint(quizer Quizer) error {
 	dir := path.Dir(quizer.Path)
-	taskpoint3 := path.Join(dir, "taskpoint3.xlsx")
-	file, err := excelize.OpenFile(taskpoint3)
+	file, err := excelize.OpenFile(path.Join(dir, qr.TaskPointFile))
 	if err != nil {
 		return err
 	}
@@ -253,6 +240,9 @@ func (qr *QuizRanker) writeTaskPoint(quizer Quizer) error {
 	file.SetCellValue(sheetName, fmt.Sprintf("A%d", length+1), qr.TaskNo+"*"+strconv.Itoa(quizer.Count))
 	file.SetCellValue(sheetName, fmt.Sprintf("B%d", length+1), quizer.TeamName)
 	file.SetCellValue(sheetName, fmt.Sprintf("C%d", length+1), qr.TaskPoint*int32(quizer.Count))
+	if len(quizer.Participant) != 0 {
+		file.SetCellValue(sheetName, taskpoint.Cell(taskpoint.ParticipantColumn, length+1), quizer.Participant)
+	}
 
 	err = file.Save()
 	if err != nil {
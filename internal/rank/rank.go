@@ -1,7 +1,16 @@
 package rank
 
+import (
+	"fmt"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/participant"
+)
+
 type Ranker interface {
 	Collect() error
+	Sort()
 	GenerateRank() error
 	WriteTaskPoint() error
 }
@@ -11,15 +20,51 @@ func Rank(ranker Ranker) error {
 	if err != nil {
 		return err
 	}
+	ranker.Sort()
 
-	ranker.GenerateRank()
+	err = ranker.GenerateRank()
 	if err != nil {
 		return err
 	}
 
-	ranker.WriteTaskPoint()
+	err = ranker.WriteTaskPoint()
 	if err != nil {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// NewRanker builds the ranker of a rank task of the campaign, the chain registry and the entrants are only read by quiz.
+// The participants are ranked under their registered team if the registry is not nil.
+func NewRanker(r *chain.Registry, participants *participant.Registry, entrants []Entrant, entrance string, c *campaign.Campaign, rank campaign.Rank) (Ranker, error) {
+	point := c.Points[rank.Task]
+	switch rank.Kind {
+	case campaign.RankIndiv:
+		return NewIndivRanker(c, participants, entrance, rank.Targets[0], rank.Task, point), nil
+	case campaign.RankTeam:
+		return NewTeamRanker(c, participants, entrance, rank.Targets, rank.Task, point), nil
+	case campaign.RankQuiz:
+		qr := NewQuizRanker(c, r, entrants, entrance, rank.Task, point)
+		if qr == nil {
+			return nil, fmt.Errorf("rank %s: quiz flow not found", rank.Task)
+		}
+		return qr, nil
+	}
+	return nil, fmt.Errorf("rank %s: unknown kind %q", rank.Task, rank.Kind)
+}
+
+// taskPointFileOf returns the task point file of a stage, empty if there is none.
+func taskPointFileOf(stage *campaign.Stage, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return stage.TaskPointFile
+}
+
+// teamOf returns the registered team of a participant, the team of its results otherwise.
+func teamOf(participants *participant.Registry, id, team string) string {
+	if p, ok := participants.Get(id); ok && len(p.Team) != 0 {
+		return p.Team
+	}
+	return team
+}
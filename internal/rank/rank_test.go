@@ -0,0 +1,185 @@
+package rank
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/taskpoint"
+)
+
+// raceRow is a row of a task point file, without race result if race is nil.
+type raceRow struct {
+	taskNo string
+	race   *taskpoint.RaceResult
+}
+
+func race(start, diff int64) *taskpoint.RaceResult {
+	return &taskpoint.RaceResult{Flow: "race", TokenId: "nft1", StartHeight: start, EndHeight: start + diff, Diff: diff, Owner: "iaa1owner"}
+}
+
+// writeTaskPoint writes the task point file of a participant under the entrance as the verifier does.
+func writeTaskPoint(t *testing.T, entrance, participant, file, team string, rows ...raceRow) {
+	t.Helper()
+	dir := filepath.Join(entrance, participant)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	if _, err := f.NewSheet("result"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetSheetRow("result", "A1", &taskpoint.Header); err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		cells := []any{row.taskNo, team, 0, ""}
+		if row.race != nil {
+			cells = append(cells, row.race.Row()...)
+			cells = append(cells, participant)
+		}
+		if err := f.SetSheetRow("result", taskpoint.Cell(0, i+2), &cells); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.SaveAs(filepath.Join(dir, file)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readRows reads the rows of the result sheet of a file.
+func readRows(t *testing.T, file string) [][]string {
+	t.Helper()
+	f, err := excelize.OpenFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("result")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+// rankedTeams returns the team column of a rank file.
+func rankedTeams(t *testing.T, file string) []string {
+	t.Helper()
+	var teams []string
+	for _, row := range readRows(t, file)[1:] {
+		teams = append(teams, row[1])
+	}
+	return teams
+}
+
+// awarded returns the point rows of a task in a task point file.
+func awarded(t *testing.T, file, taskNo string) [][]string {
+	t.Helper()
+	var rows [][]string
+	for _, row := range readRows(t, file) {
+		if row[0] == taskNo {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func TestIndivRanker(t *testing.T) {
+	c, err := campaign.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entrance := t.TempDir()
+	writeTaskPoint(t, entrance, "alice", "taskpoint3.xlsx", "Wolves", raceRow{"B1", race(100, 10)}, raceRow{"B2", race(100, 1)})
+	writeTaskPoint(t, entrance, "bob", "taskpoint3.xlsx", "Bears", raceRow{"B1", race(200, 5)})
+	// tied with alice, ahead by starting earlier
+	writeTaskPoint(t, entrance, "carol", "taskpoint3.xlsx", "Foxes", raceRow{"B1", race(90, 10)})
+	// no race result
+	writeTaskPoint(t, entrance, "dave", "taskpoint3.xlsx", "Lynx", raceRow{"B1", nil})
+	// the task point file of another stage isn't read
+	writeTaskPoint(t, entrance, "erin", "taskpoint2.xlsx", "Owls", raceRow{"B1", race(1, 1)})
+
+	ir := NewIndivRanker(c, nil, entrance, "B1", "B3", 50)
+	if ir.TaskPointFile != "taskpoint3.xlsx" {
+		t.Fatalf("task point file: want taskpoint3.xlsx, got %s", ir.TaskPointFile)
+	}
+	// ranking twice doesn't award twice
+	for i := 0; i < 2; i++ {
+		ir.IndivRaceInfos = nil
+		if err := Rank(ir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var order []string
+	for _, info := range ir.IndivRaceInfos {
+		order = append(order, info.participant)
+	}
+	if want := []string{"bob", "carol", "alice"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("order: want %v, got %v", want, order)
+	}
+	if want, got := []string{"Bears", "Foxes", "Wolves"}, rankedTeams(t, filepath.Join(entrance, "rankB3.xlsx")); !reflect.DeepEqual(got, want) {
+		t.Fatalf("rank file: want %v, got %v", want, got)
+	}
+	for _, participant := range []string{"alice", "bob", "carol"} {
+		rows := awarded(t, filepath.Join(entrance, participant, "taskpoint3.xlsx"), "B3")
+		if len(rows) != 1 || rows[0][2] != "50" {
+			t.Errorf("%s: want one B3 row of 50 points, got %v", participant, rows)
+		}
+	}
+	if rows := awarded(t, filepath.Join(entrance, "dave", "taskpoint3.xlsx"), "B3"); len(rows) != 0 {
+		t.Errorf("dave: want no point, got %v", rows)
+	}
+}
+
+func TestTeamRanker(t *testing.T) {
+	c, err := campaign.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := []string{"B5", "B6", "B7"}
+	entrance := t.TempDir()
+	writeTaskPoint(t, entrance, "alice", "taskpoint3.xlsx", "Wolves",
+		raceRow{"B5", race(100, 10)}, raceRow{"B6", race(100, 10)}, raceRow{"B7", race(100, 10)})
+	// the same sum of diffs as Wolves, ahead by the sum of the start heights
+	writeTaskPoint(t, entrance, "bob", "taskpoint3.xlsx", "Bears",
+		raceRow{"B5", race(90, 5)}, raceRow{"B6", race(90, 15)}, raceRow{"B7", race(90, 10)})
+	writeTaskPoint(t, entrance, "carol", "taskpoint3.xlsx", "Foxes",
+		raceRow{"B5", race(300, 4)}, raceRow{"B6", race(300, 4)}, raceRow{"B7", race(300, 4)})
+	// two races out of three, not rankable
+	writeTaskPoint(t, entrance, "dave", "taskpoint3.xlsx", "Lynx",
+		raceRow{"B5", race(100, 1)}, raceRow{"B6", race(100, 1)}, raceRow{"B7", nil})
+
+	tr := NewTeamRanker(c, nil, entrance, targets, "B8", 150)
+	if tr.TaskPointFile != "taskpoint3.xlsx" {
+		t.Fatalf("task point file: want taskpoint3.xlsx, got %s", tr.TaskPointFile)
+	}
+	if err := Rank(tr); err != nil {
+		t.Fatal(err)
+	}
+
+	var order []string
+	for _, info := range tr.TeamRaceInfos {
+		order = append(order, info.teamName)
+	}
+	if want := []string{"Lynx", "Foxes", "Bears", "Wolves"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("order: want %v, got %v", want, order)
+	}
+	if want, got := []string{"Foxes", "Bears", "Wolves"}, rankedTeams(t, filepath.Join(entrance, "rankB8.xlsx")); !reflect.DeepEqual(got, want) {
+		t.Fatalf("rank file: want %v, got %v", want, got)
+	}
+	for _, participant := range []string{"alice", "bob", "carol"} {
+		rows := awarded(t, filepath.Join(entrance, participant, "taskpoint3.xlsx"), "B8")
+		if len(rows) != 1 || rows[0][2] != "150" {
+			t.Errorf("%s: want one B8 row of 150 points, got %v", participant, rows)
+		}
+	}
+	if rows := awarded(t, filepath.Join(entrance, "dave", "taskpoint3.xlsx"), "B8"); len(rows) != 0 {
+		t.Errorf("dave: want no point, got %v", rows)
+	}
+}
@@ -3,12 +3,13 @@ package rank
 import (
 	"errors"
 	"fmt"
-	"github.com/taramakage/gon-verifier/internal/scorecard"
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/participant"
+	"github.com/taramakage/gon-verifier/internal/taskpoint"
 	"github.com/xuri/excelize/v2"
 	"os"
 	"path/filepath"
 	"sort"
-	"strings"
 )
 
 type TeamRanker struct {
@@ -17,9 +18,11 @@ type TeamRanker struct {
 	TaskPoint     int32
 	TeamRaceInfos []TeamRaceInfo
 	Entrance      string
+	Participants  *participant.Registry
+	TaskPointFile string // task point file of the stage of the targets
 }
 
-func NewTeamRanker(entrance string, targetTaskNos []string, taskNo string, taskPoint int32) *TeamRanker {
+func NewTeamRanker(c *campaign.Campaign, participants *participant.Registry, entrance string, targetTaskNos []string, taskNo string, taskPoint int32) *TeamRanker {
 	teamRaceInfos := make([]TeamRaceInfo, 0)
 	return &TeamRanker{
 		TargetTaskNos: targetTaskNos,
@@ -27,6 +30,8 @@ func NewTeamRanker(entrance string, targetTaskNos []string, taskNo string, taskP
 		TaskPoint:     taskPoint,
 		TeamRaceInfos: teamRaceInfos,
 		Entrance:      entrance,
+		Participants:  participants,
+		TaskPointFile: taskPointFileOf(c.RankStage(campaign.Rank{Targets: targetTaskNos})),
 	}
 }
 
@@ -56,7 +61,7 @@ func (tr *TeamRanker) Collect() error {
 			fmt.Printf("Error accessing path %q: %v\n", path, err)
 			return err
 		}
-		if info.IsDir() || info.Name() != scorecard.DefaultStageThreeTaskPoint {
+		if info.IsDir() || info.Name() != tr.TaskPointFile {
 			return nil
 		}
 		files = append(files, path)
@@ -89,32 +94,31 @@ func (tr *TeamRanker) loadRaceInfo(file string) error {
 	}
 
 	teamRace := TeamRaceInfo{
-		raceInfos: make([]RaceInfo, 0),
+		raceInfos: make([]taskpoint.RaceResult, 0),
 	}
 
 	for _, row := range rows {
 		for _, targetTaskNo := range tr.TargetTaskNos {
-			if len(row) < 4 {
+			if len(row) == 0 || row[0] != targetTaskNo {
 				continue
 			}
-			if row[0] == targetTaskNo && strings.HasPrefix(row[3], "race") {
-				raceInfo, err := BuildRaceInfo(row[3])
-				if err != nil {
-					return err
-				}
-				if raceInfo != nil {
-					teamRace.raceInfos = append(teamRace.raceInfos, *raceInfo)
-				}
+			raceInfo, err := taskpoint.ReadRaceResult(row)
+			if err != nil {
+				return err
+			}
+			if raceInfo != nil {
+				teamRace.raceInfos = append(teamRace.raceInfos, *raceInfo)
+				teamRace.participant = taskpoint.ReadParticipant(row)
 			}
 		}
 	}
 
 	if len(teamRace.raceInfos) != 0 {
-		teamRace.teamName = rows[1][1]
+		teamRace.teamName = teamOf(tr.Participants, teamRace.participant, rows[1][1])
 		teamRace.path = file
 		for _, raceInfo := range teamRace.raceInfos {
-			teamRace.diffSum += raceInfo.diff
-			teamRace.startSum += raceInfo.start
+			teamRace.diffSum += raceInfo.Diff
+			teamRace.startSum += raceInfo.StartHeight
 		}
 		if len(teamRace.raceInfos) == 3 {
 			teamRace.rankable = true
@@ -150,6 +154,7 @@ func (tr *TeamRanker) GenerateRank() error {
 	f.SetCellValue(sheetName, "B1", "TeamName")
 	f.SetCellValue(sheetName, "C1", "SumOfDiffHeight")
 	f.SetCellValue(sheetName, "D1", "SumOfStartHeight")
+	f.SetCellValue(sheetName, "E1", "Participant")
 
 	index := 1
 	for _, teamRaceInfo := range tr.TeamRaceInfos {
@@ -160,6 +165,7 @@ func (tr *TeamRanker) GenerateRank() error {
 		f.SetCellValue(sheetName, fmt.Sprintf("B%d", index+1), teamRaceInfo.teamName)
 		f.SetCellValue(sheetName, fmt.Sprintf("C%d", index+1), teamRaceInfo.diffSum)
 		f.SetCellValue(sheetName, fmt.Sprintf("D%d", index+1), teamRaceInfo.startSum)
+		f.SetCellValue(sheetName, fmt.Sprintf("E%d", index+1), teamRaceInfo.participant)
 		index++
 	}
 
@@ -233,6 +239,9 @@ func (tr *TeamRanker) writeTaskPoint(teamRaceInfo TeamRaceInfo) error {
 	file.SetCellValue(sheetName, fmt.Sprintf("A%d", length+1), tr.TaskNo)
 	file.SetCellValue(sheetName, fmt.Sprintf("B%d", length+1), teamRaceInfo.teamName)
 	file.SetCellValue(sheetName, fmt.Sprintf("C%d", length+1), tr.TaskPoint)
+	if len(teamRaceInfo.participant) != 0 {
+		file.SetCellValue(sheetName, taskpoint.Cell(taskpoint.ParticipantColumn, length+1), teamRaceInfo.participant)
+	}
 
 	err = file.Save()
 	if err != nil {
@@ -1,7 +1,12 @@
 package scorecard
 
 import (
+	"fmt"
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/participant"
+	"github.com/taramakage/gon-verifier/internal/taskpoint"
 	"github.com/xuri/excelize/v2"
+	"golang.org/x/exp/slices"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -18,11 +23,6 @@ const (
 	DefaultRankIndivTwo  = "rankB4.xlsx"
 	DefaultRankTeamOne   = "rankB8.xlsx"
 	DefaultQuizGame      = "rankB9.xlsx"
-	// user directory
-	DefaultStageOneTaskPoint   = "taskpoint1.xlsx"
-	DefaultStageTwoTaskPoint   = "taskpoint2.xlsx"
-	DefaultStageTwoBTaskPoint  = "taskpoint2b.xlsx"
-	DefaultStageThreeTaskPoint = "taskpoint3.xlsx"
 	// sheet name
 	DefaultTaskPointSheet = "result"
 	DefaultScoreCardSheet = "result"
@@ -30,20 +30,25 @@ const (
 
 // ScoreCard reads task results and output to the scorecard
 type ScoreCard struct {
-	entranceDir string // path: entrance
+	entranceDir    string                // path: entrance
+	participants   *participant.Registry // nil if the participants are named after their directory
+	taskPointFiles []string              // task point files of the stages of the campaign
 }
 
 type ScoreCardEntry struct {
+	participantId string
 	taskCompleted string
 	totalPoint    int
 	teamName      string
 	failedReason  string
 	githubAccount string
+	discord       string
 }
 
-// NewScoreCard creates a new ScoreCard
-func NewScoreCard(entranceDir string) *ScoreCard {
-	return &ScoreCard{entranceDir: entranceDir}
+// NewScoreCard creates a new ScoreCard summing the task point files of the stages of the campaign,
+// the team, github and discord of a participant are those of the registry if not nil
+func NewScoreCard(c *campaign.Campaign, entranceDir string, participants *participant.Registry) *ScoreCard {
+	return &ScoreCard{entranceDir: entranceDir, participants: participants, taskPointFiles: c.TaskPointFiles()}
 }
 
 func (sc *ScoreCard) Generate() error {
@@ -59,47 +64,42 @@ func (sc *ScoreCard) Generate() error {
 	f.SetCellValue(DefaultScoreCardSheet, "E1", "update_time")
 	f.SetCellValue(DefaultScoreCardSheet, "F1", "failed_reason")
 	f.SetCellValue(DefaultScoreCardSheet, "G1", "github_account")
+	f.SetCellValue(DefaultScoreCardSheet, "H1", "participant_id")
+	f.SetCellValue(DefaultScoreCardSheet, "I1", "discord")
 
-	var allTaskPointFiles [][]string
-	var taskPointFiles []string
+	// the task point files of a participant are written next to its evidence
+	var dirs []string
+	allTaskPointFiles := make(map[string][]string)
 	err = filepath.Walk(sc.entranceDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if !info.IsDir() && info.Name() == DefaultStageOneTaskPoint {
-			taskPointFiles = append(taskPointFiles, path)
+		if info.IsDir() || !slices.Contains(sc.taskPointFiles, info.Name()) {
+			return nil
 		}
-
-		if !info.IsDir() && info.Name() == DefaultStageTwoTaskPoint {
-			taskPointFiles = append(taskPointFiles, path)
-		}
-
-		if !info.IsDir() && info.Name() == DefaultStageTwoBTaskPoint {
-			taskPointFiles = append(taskPointFiles, path)
-		}
-
-		if !info.IsDir() && info.Name() == DefaultStageThreeTaskPoint {
-			taskPointFiles = append(taskPointFiles, path)
-		}
-
-		if info.IsDir() && len(taskPointFiles) != 0 {
-			allTaskPointFiles = append(allTaskPointFiles, taskPointFiles)
-			taskPointFiles = []string{}
+		dir := filepath.Dir(path)
+		if _, ok := allTaskPointFiles[dir]; !ok {
+			dirs = append(dirs, dir)
 		}
+		allTaskPointFiles[dir] = append(allTaskPointFiles[dir], path)
 		return nil
 	})
-	allTaskPointFiles = append(allTaskPointFiles, taskPointFiles)
 	if err != nil {
 		return err
 	}
 
 	var entries []*ScoreCardEntry
-	for _, taskPointFiles := range allTaskPointFiles {
-		entry, err := sc.HandleTaskPoint(taskPointFiles)
-		entry.filterRedundantReason()
+	dirOf := make(map[string]string)
+	for _, dir := range dirs {
+		entry, err := sc.HandleTaskPoint(allTaskPointFiles[dir])
 		if err != nil {
 			continue
 		}
+		if other, ok := dirOf[entry.participantId]; ok {
+			return fmt.Errorf("participant %s has results in %s and %s", entry.participantId, other, dir)
+		}
+		dirOf[entry.participantId] = dir
+		entry.filterRedundantReason()
 		entries = append(entries, entry)
 	}
 
@@ -107,7 +107,10 @@ func (sc *ScoreCard) Generate() error {
 		if entries[i].totalPoint != entries[j].totalPoint {
 			return entries[i].totalPoint > entries[j].totalPoint
 		}
-		return entries[i].teamName < entries[j].teamName
+		if entries[i].teamName != entries[j].teamName {
+			return entries[i].teamName < entries[j].teamName
+		}
+		return entries[i].participantId < entries[j].participantId
 	})
 
 	rank := 1
@@ -124,6 +127,8 @@ func (sc *ScoreCard) Generate() error {
 		f.SetCellValue(DefaultScoreCardSheet, "D"+strconv.Itoa(i+2), entries[i].totalPoint)
 		f.SetCellValue(DefaultScoreCardSheet, "F"+strconv.Itoa(i+2), entries[i].failedReason)
 		f.SetCellValue(DefaultScoreCardSheet, "G"+strconv.Itoa(i+2), entries[i].githubAccount)
+		f.SetCellValue(DefaultScoreCardSheet, "H"+strconv.Itoa(i+2), entries[i].participantId)
+		f.SetCellValue(DefaultScoreCardSheet, "I"+strconv.Itoa(i+2), entries[i].discord)
 	}
 
 	f.SetActiveSheet(index)
@@ -135,11 +140,12 @@ func (sc *ScoreCard) Generate() error {
 	return nil
 }
 
-// HandleTaskPoint calculate the score of a stage from one's task point files
+// HandleTaskPoint calculate the score of a stage from one's task point files, all in the directory of its evidence.
+// The participant is the one of the participant column, or else the directory.
 func (sc *ScoreCard) HandleTaskPoint(taskPointFiles []string) (*ScoreCardEntry, error) {
 	var (
 		taskResults TaskResults
-		github      string
+		id          string
 		teamName    string
 	)
 
@@ -155,17 +161,14 @@ func (sc *ScoreCard) HandleTaskPoint(taskPointFiles []string) (*ScoreCardEntry,
 			return nil, err
 		}
 
-		strs := strings.Split(taskPointFile, "/")
-		github = strs[len(strs)-2]
+		if len(id) == 0 {
+			id = participantOf(rows)
+		}
 
 		if i == 0 && len(rows) == 1 {
-			return &ScoreCardEntry{
-				taskCompleted: "",
-				totalPoint:    0,
-				teamName:      "UnknownTeam:@" + github,
-				failedReason:  "all evidence formats are incorrect",
-				githubAccount: "@" + github,
-			}, nil
+			entry := sc.newEntry(id, taskPointFile, "")
+			entry.failedReason = "all evidence formats are incorrect"
+			return entry, nil
 		}
 
 		if len(rows) == 1 {
@@ -175,7 +178,7 @@ func (sc *ScoreCard) HandleTaskPoint(taskPointFiles []string) (*ScoreCardEntry,
 		for _, row := range rows[1:] {
 			point, _ := strconv.Atoi(row[2])
 			reason := ""
-			if len(row) == 4 {
+			if len(row) >= 4 {
 				reason = row[3]
 			}
 			taskResults = append(taskResults, TaskResult{
@@ -186,29 +189,63 @@ func (sc *ScoreCard) HandleTaskPoint(taskPointFiles []string) (*ScoreCardEntry,
 		}
 
 		teamName = rows[1][1]
-		if strings.HasPrefix(teamName, "team") {
-			teamName = "UnknownTeam:@" + github
-		}
 	}
 
-	sc.filterRaceReason(taskResults)
+	entry := sc.newEntry(id, taskPointFiles[0], teamName)
+	entry.taskCompleted = sc.concatenateTaskNo(taskResults)
+	entry.totalPoint = sc.calculateTotalPoint(taskResults)
+	entry.failedReason = sc.concatenateFailedReason(taskResults)
+	return entry, nil
+}
 
-	return &ScoreCardEntry{
-		taskCompleted: sc.concatenateTaskNo(taskResults),
-		totalPoint:    sc.calculateTotalPoint(taskResults),
+// newEntry creates the entry of a participant, named after the directory of its task point file without id.
+// A registered participant has the team, github and discord of the registry. Otherwise the github is the id and a
+// team name left as in the template, e.g. team-fake, is replaced.
+func (sc *ScoreCard) newEntry(id, taskPointFile, teamName string) *ScoreCardEntry {
+	if len(id) == 0 {
+		id = filepath.Base(filepath.Dir(taskPointFile))
+	}
+	entry := &ScoreCardEntry{
+		participantId: id,
 		teamName:      teamName,
-		failedReason:  sc.concatenateFailedReason(taskResults),
-		githubAccount: "@" + github,
-	}, nil
+		githubAccount: "@" + id,
+	}
+	if p, ok := sc.participants.Get(id); ok {
+		entry.discord = p.Discord
+		if len(p.Github) != 0 {
+			entry.githubAccount = "@" + p.Github
+		}
+		if len(p.Team) != 0 {
+			entry.teamName = p.Team
+			return entry
+		}
+	}
+	if len(entry.teamName) == 0 || strings.HasPrefix(entry.teamName, "team") {
+		entry.teamName = "UnknownTeam:" + entry.githubAccount
+	}
+	return entry
 }
 
-// filterReason will filter race from reason
-func (sc *ScoreCard) filterRaceReason(results TaskResults) {
-	for i, taskResult := range results {
-		if strings.HasPrefix(taskResult.TaskNo, "B") && taskResult.Point != 0 {
-			results[i].Reason = ""
+// participantOf reads the first participant of the rows of a task point file, empty if it has no participant column.
+func participantOf(rows [][]string) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	col := -1
+	for j, cell := range rows[0] {
+		if cell == taskpoint.ParticipantHeader {
+			col = j
+		}
+	}
+	if col < 0 {
+		return ""
+	}
+	for _, row := range rows[1:] {
+		if col < len(row) && len(row[col]) != 0 {
+			return row[col]
 		}
 	}
+	return ""
 }
 
 func (sc *ScoreCard) concatenateTaskNo(taskResults TaskResults) string {
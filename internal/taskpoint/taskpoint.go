@@ -0,0 +1,78 @@
+package taskpoint
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ParticipantHeader is the header of the participant column.
+const ParticipantHeader = "Participant"
+
+// Header are the columns of the task point xlsx written by the verifier, the race columns are read by the rankers
+// and the participant by the rankers and the scorecard.
+var Header = []string{
+	"TaskNo", "TeamName", "Point", "Reason",
+	"RaceFlow", "RaceTokenId", "RaceStartHeight", "RaceEndHeight", "RaceDiff", "RaceOwner",
+	ParticipantHeader,
+}
+
+// Indexes of the first race column and of the participant column.
+const (
+	RaceColumn        = 4
+	ParticipantColumn = 10
+)
+
+// RaceResult is the outcome of a race task, read by the individual and team rankers.
+type RaceResult struct {
+	Flow        string `json:"flow"`
+	TokenId     string `json:"token_id"`
+	StartHeight int64  `json:"start_height"` // height of the first transfer
+	EndHeight   int64  `json:"end_height"`   // height of the last transfer
+	Diff        int64  `json:"diff"`
+	Owner       string `json:"owner"` // final owner of the nft
+}
+
+// Row returns the race columns of the result.
+func (r *RaceResult) Row() []any {
+	return []any{r.Flow, r.TokenId, r.StartHeight, r.EndHeight, r.Diff, r.Owner}
+}
+
+// ReadRaceResult reads the race columns of a row, nil if the row has no race result.
+func ReadRaceResult(row []string) (*RaceResult, error) {
+	if len(row) < ParticipantColumn || len(row[RaceColumn]) == 0 {
+		return nil, nil
+	}
+	race := row[RaceColumn:]
+	heights := make([]int64, 3)
+	for i := range heights {
+		h, err := strconv.ParseInt(race[2+i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("race column %s: %w", Header[RaceColumn+2+i], err)
+		}
+		heights[i] = h
+	}
+	return &RaceResult{
+		Flow:        race[0],
+		TokenId:     race[1],
+		StartHeight: heights[0],
+		EndHeight:   heights[1],
+		Diff:        heights[2],
+		Owner:       race[5],
+	}, nil
+}
+
+// ReadParticipant reads the participant column of a row, empty if the row has none.
+func ReadParticipant(row []string) string {
+	if len(row) <= ParticipantColumn {
+		return ""
+	}
+	return row[ParticipantColumn]
+}
+
+// Cell returns the cell of a zero based column of a 1-based row, e.g. K2 for the participant column of row 2.
+func Cell(col, row int) string {
+	cell, _ := excelize.CoordinatesToCellName(col+1, row)
+	return cell
+}
@@ -0,0 +1,35 @@
+package taskpoint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadRaceResult(t *testing.T) {
+	race := &RaceResult{Flow: "a01", TokenId: "nft1", StartHeight: 150, EndHeight: 180, Diff: 30, Owner: "iaa1owner"}
+	for _, tc := range []struct {
+		row     []string
+		race    *RaceResult
+		invalid bool
+	}{
+		{[]string{"B1", "team", "2", "", "a01", "nft1", "150", "180", "30", "iaa1owner", "p1"}, race, false},
+		{[]string{"B1", "team", "2", "", "a01", "nft1", "150", "180", "30", "iaa1owner"}, race, false},
+		{[]string{"B1", "team", "0", "NFT: not found"}, nil, false},
+		{[]string{"B1", "team", "2", "", "a01", "nft1", "start", "180", "30", "iaa1owner"}, nil, true},
+	} {
+		got, err := ReadRaceResult(tc.row)
+		if (err != nil) != tc.invalid {
+			t.Errorf("%v: unexpected error %v", tc.row, err)
+		}
+		if !reflect.DeepEqual(got, tc.race) {
+			t.Errorf("%v: want %+v, got %+v", tc.row, tc.race, got)
+		}
+	}
+
+	if got := ReadParticipant([]string{"B1", "team", "2"}); got != "" {
+		t.Errorf("want no participant, got %q", got)
+	}
+	if got := Cell(ParticipantColumn, 2); got != "K2" {
+		t.Errorf("want K2, got %s", got)
+	}
+}
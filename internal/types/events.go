@@ -4,8 +4,10 @@ const (
 	// Issue Denom on Iris
 	EventTypeIssueDenom   = "issue_denom"
 	AttributeDenomId      = "denom_id"
+	AttributeDenomName    = "denom_name"
 	AttributeDenomCreator = "creator"
 	AttributeMsgSender    = "sender"
+	EventTypeMessage      = "message"
 
 	// Mint NFT on Iris
 	EventTypeNftMint      = "mint_nft"
@@ -24,6 +26,16 @@ const (
 	AttributeKeyReceiver       = "receiver"
 	AttributeKeyDestPort       = "packet_dst_port"
 	AttributeKeyDestChan       = "packet_dst_channel"
+	AttributeKeySrcPort        = "packet_src_port"
+	AttributeKeySrcChan        = "packet_src_channel"
+	AttributeKeySequence       = "packet_sequence"
+
+	// IBC Packet Lifecycle, the packet attributes are the ones of send_packet
+	EventTypeIbcRecvPacket    = "recv_packet"
+	EventTypeIbcWriteAck      = "write_acknowledgement"
+	EventTypeIbcAckPacket     = "acknowledge_packet"
+	EventTypeIbcTimeoutPacket = "timeout_packet"
+	AttributeKeyPacketAck     = "packet_ack"
 
 	EventTypeWasm = "wasm"
 	// AttributeKeySender = "sender"
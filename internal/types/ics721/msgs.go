@@ -0,0 +1,25 @@
+package ics721
+
+import (
+	proto "github.com/gogo/protobuf/proto"
+)
+
+// MsgTransfer sends nfts of a class over ics-721, only the fields read by the verifier are mapped.
+type MsgTransfer struct {
+	SourcePort       string   `protobuf:"bytes,1,opt,name=source_port,json=sourcePort,proto3" json:"source_port,omitempty"`
+	SourceChannel    string   `protobuf:"bytes,2,opt,name=source_channel,json=sourceChannel,proto3" json:"source_channel,omitempty"`
+	ClassId          string   `protobuf:"bytes,3,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	TokenIds         []string `protobuf:"bytes,4,rep,name=token_ids,json=tokenIds,proto3" json:"token_ids,omitempty"`
+	Sender           string   `protobuf:"bytes,5,opt,name=sender,proto3" json:"sender,omitempty"`
+	Receiver         string   `protobuf:"bytes,6,opt,name=receiver,proto3" json:"receiver,omitempty"`
+	TimeoutTimestamp uint64   `protobuf:"varint,8,opt,name=timeout_timestamp,json=timeoutTimestamp,proto3" json:"timeout_timestamp,omitempty"`
+	Memo             string   `protobuf:"bytes,9,opt,name=memo,proto3" json:"memo,omitempty"`
+}
+
+func (m *MsgTransfer) Reset()         { *m = MsgTransfer{} }
+func (m *MsgTransfer) String() string { return proto.CompactTextString(m) }
+func (*MsgTransfer) ProtoMessage()    {}
+
+func init() {
+	proto.RegisterType((*MsgTransfer)(nil), "ibc.applications.nft_transfer.v1.MsgTransfer")
+}
@@ -3,9 +3,19 @@ package types
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 )
 
+var (
+	// ErrTokenNotFound is returned when a packet doesn't carry a token.
+	ErrTokenNotFound = errors.New("token not in packet")
+	// ErrTokenAmbiguous is returned when a packet carries a token several times.
+	ErrTokenAmbiguous = errors.New("token carried several times by packet")
+)
+
 const (
 	TxResultTypeRaw = "raw"
 	TxResultTypeBasic      = "basic"
@@ -18,6 +28,8 @@ type (
 	TxResultBasic struct {
 		Sender string
 		TxCode int
+		Height int64
+		Body   *TxBody // decoded tx, nil if the response carries no tx bytes
 	}
 
 	TxResultIssueDenom struct {
@@ -25,6 +37,8 @@ type (
 		Creator string
 		DenomId string
 		TxCode  int
+		Height  int64
+		Body    *TxBody // decoded tx, nil if the response carries no tx bytes
 	}
 
 	TxResultMintNft struct {
@@ -33,16 +47,31 @@ type (
 		TokenId   string
 		Recipient string
 		TxCode    int
+		Height    int64
+		Body      *TxBody // decoded tx, nil if the response carries no tx bytes
 	}
 
 	TxResultIbcNft struct {
 		Sender   string
 		Receiver string
+		SrcPort  string
+		SrcChan  string
 		DestPort string
 		DestChan string
+		Sequence uint64
 		ClassId  string
-		TokenId  string
+		TokenId  string        // first token of the packet
+		Tokens   []IbcNftToken // every token of the packet
 		TxCode   int
+		Height   int64
+		Body     *TxBody // decoded tx, nil if the response carries no tx bytes
+	}
+
+	// IbcNftToken is a token carried by an ics-721 packet, Data is decoded from base64.
+	IbcNftToken struct {
+		Id   string
+		Uri  string
+		Data string
 	}
 
 	RaceResult struct {
@@ -50,6 +79,7 @@ type (
 		Receiver string
 		ClassId  string
 		TokenId  string
+		TokenIds []string // every token of the packet of the first transfer
 		Height string
 		TxCode   int
 	}
@@ -79,6 +109,12 @@ type (
 		Memo      string   `json:"memo"`
 	}
 
+	// IbcAck is the acknowledgement written by the receiving chain, either Result or Error is set.
+	IbcAck struct {
+		Result []byte `json:"result,omitempty"`
+		Error  string `json:"error,omitempty"`
+	}
+
 	// TxResponse is the response of the tx query
 	TxResponse struct {
 		Jsonrpc string `json:"jsonrpc"`
@@ -165,29 +201,101 @@ func (tx *TxResponse) DecodeAllEventAttributes() error {
 	return nil
 }
 
-func (tx *TxResponse) IbcNftPkg() (any, error) {
+// Tokens returns every token of the packet, a token without uri or data has them empty.
+func (p *IbcNftPacket) Tokens() []IbcNftToken {
+	tokens := make([]IbcNftToken, 0, len(p.TokenIds))
+	for i, id := range p.TokenIds {
+		token := IbcNftToken{Id: id}
+		if i < len(p.TokenUris) {
+			token.Uri = p.TokenUris[i]
+		}
+		if i < len(p.TokenData) {
+			data, _ := base64.StdEncoding.DecodeString(p.TokenData[i])
+			token.Data = string(data)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// Token returns the token of the packet with an id.
+func (p *IbcNftPacket) Token(id string) (IbcNftToken, error) {
+	var found []IbcNftToken
+	for _, token := range p.Tokens() {
+		if token.Id == id {
+			found = append(found, token)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return IbcNftToken{}, fmt.Errorf("%w: %s", ErrTokenNotFound, id)
+	case 1:
+		return found[0], nil
+	}
+	return IbcNftToken{}, fmt.Errorf("%w: %s", ErrTokenAmbiguous, id)
+}
+
+func (tx *TxResponse) ibcNftPacket() (IbcNftPacket, error) {
 	ibcPkgRaw := tx.EventAttributeValueByKey(EventTypeIbcSendPacket, AttributeKeyIbcPackageData)
 	var ibcPkg IbcNftPacket
-	err := json.Unmarshal([]byte(ibcPkgRaw), &ibcPkg)
+	if err := json.Unmarshal([]byte(ibcPkgRaw), &ibcPkg); err != nil {
+		return ibcPkg, err
+	}
+	if len(ibcPkg.TokenIds) == 0 {
+		return ibcPkg, errors.New("packet carries no token")
+	}
+	return ibcPkg, nil
+}
+
+func (tx *TxResponse) IbcNftPkg() (any, error) {
+	ibcPkg, err := tx.ibcNftPacket()
+	if err != nil {
+		return nil, err
+	}
+	body, err := tx.Body()
 	if err != nil {
 		return nil, err
 	}
+	sequence, _ := strconv.ParseUint(tx.EventAttributeValueByKey(EventTypeIbcSendPacket, AttributeKeySequence), 10, 64)
 
 	return TxResultIbcNft{
 		Sender:   ibcPkg.Sender,
 		Receiver: ibcPkg.Receiver,
+		SrcPort:  tx.EventAttributeValueByKey(EventTypeIbcSendPacket, AttributeKeySrcPort),
+		SrcChan:  tx.EventAttributeValueByKey(EventTypeIbcSendPacket, AttributeKeySrcChan),
 		DestPort: tx.EventAttributeValueByKey(EventTypeIbcSendPacket, AttributeKeyDestPort),
 		DestChan: tx.EventAttributeValueByKey(EventTypeIbcSendPacket, AttributeKeyDestChan),
+		Sequence: sequence,
 		ClassId:  ibcPkg.ClassId, // class-trace
 		TokenId:  ibcPkg.TokenIds[0],
+		Tokens:   ibcPkg.Tokens(),
 		TxCode:   tx.Result.TxResult.Code,
+		Height:   tx.BlockHeight(),
+		Body:     body,
 	}, nil
 }
 
+// IbcAck returns the acknowledgement written by a recv_packet tx.
+func (tx *TxResponse) IbcAck() (IbcAck, error) {
+	var ack IbcAck
+	raw := tx.EventAttributeValueByKey(EventTypeIbcWriteAck, AttributeKeyPacketAck)
+	if len(raw) == 0 {
+		return ack, errors.New("no acknowledgement written")
+	}
+	if err := json.Unmarshal([]byte(raw), &ack); err != nil {
+		return ack, err
+	}
+	return ack, nil
+}
+
+// BlockHeight returns the height of the block including the tx, 0 if it is not a number.
+func (tx *TxResponse) BlockHeight() int64 {
+	height, _ := strconv.ParseInt(tx.Result.Height, 10, 64)
+	return height
+}
+
 func (tx *TxResponse) GetFirstRace() (RaceResult, error) {
-	ibcPkgRaw := tx.EventAttributeValueByKey(EventTypeIbcSendPacket, AttributeKeyIbcPackageData)
-	var ibcPkg IbcNftPacket
-	err := json.Unmarshal([]byte(ibcPkgRaw), &ibcPkg)
+	ibcPkg, err := tx.ibcNftPacket()
 	if err != nil {
 		return RaceResult{}, err
 	}
@@ -197,6 +305,7 @@ func (tx *TxResponse) GetFirstRace() (RaceResult, error) {
 		Receiver: ibcPkg.Receiver,
 		ClassId: ibcPkg.ClassId,
 		TokenId: ibcPkg.TokenIds[0],
+		TokenIds: ibcPkg.TokenIds,
 		Height: tx.Result.Height,
 		TxCode: tx.Result.TxResult.Code,
 	}, err
@@ -214,29 +323,62 @@ func (tx *TxResponse) GetLastRace() (RaceResult, error) {
 }
 
 func (tx *TxResponse) GetIbcPkgRaceData() (RaceData, error) {
-	ibcPkgRaw := tx.EventAttributeValueByKey(EventTypeIbcSendPacket, AttributeKeyIbcPackageData)
-	var ibcPkg IbcNftPacket
-	err := json.Unmarshal([]byte(ibcPkgRaw), &ibcPkg)
+	ibcPkg, err := tx.ibcNftPacket()
+	if err != nil {
+		return RaceData{}, err
+	}
+	return tx.GetIbcPkgRaceDataOf(ibcPkg.TokenIds[0])
+}
+
+// GetIbcPkgRaceDataOf returns the race data of a token of the packet, which may carry several tokens.
+func (tx *TxResponse) GetIbcPkgRaceDataOf(tokenId string) (RaceData, error) {
+	ibcPkg, err := tx.ibcNftPacket()
 	if err != nil {
 		return RaceData{}, err
 	}
-	if len(ibcPkg.TokenData) ==  0 {
+	token, err := ibcPkg.Token(tokenId)
+	if err != nil {
 		return RaceData{}, err
 	}
 
 	var raceData RaceData
-	tokenData := ibcPkg.TokenData[0]
-	decode, _ := base64.StdEncoding.DecodeString(tokenData)
-
-	err = json.Unmarshal(decode, &raceData)
+	err = json.Unmarshal([]byte(token.Data), &raceData)
 	if err != nil {
 		return RaceData{}, err
 	}
 	return raceData, nil
 }
 
+// TokenIds returns the ids of every token of the packet.
+func (txIbc *TxResultIbcNft) TokenIds() []string {
+	ids := make([]string, 0, len(txIbc.Tokens))
+	for _, token := range txIbc.Tokens {
+		ids = append(ids, token.Id)
+	}
+	return ids
+}
+
+// CountToken returns how many times the packet carries a token, more than once makes the token ambiguous.
+func (txIbc *TxResultIbcNft) CountToken(id string) int {
+	n := 0
+	for _, token := range txIbc.Tokens {
+		if token.Id == id {
+			n++
+		}
+	}
+	return n
+}
+
 func (txIbc *TxResultIbcNft) OriginalClass() string {
 	ibcClassId := txIbc.ClassId
 	elements := strings.Split(ibcClassId, "/")
 	return elements[len(elements)-1]
 }
+
+// DestClassTrace returns the class trace on the receiving chain, the class drops the hop of the channel it goes back through.
+func (txIbc *TxResultIbcNft) DestClassTrace() string {
+	if prefix := txIbc.SrcPort + "/" + txIbc.SrcChan + "/"; strings.HasPrefix(txIbc.ClassId, prefix) {
+		return strings.TrimPrefix(txIbc.ClassId, prefix)
+	}
+	return txIbc.DestPort + "/" + txIbc.DestChan + "/" + txIbc.ClassId
+}
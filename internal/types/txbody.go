@@ -0,0 +1,168 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	onfttypes "github.com/OmniFlix/onft/types"
+	uptickcoll "github.com/UptickNetwork/uptick/x/collection/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	proto "github.com/gogo/protobuf/proto"
+	irisnft "github.com/irisnet/irismod/modules/nft/types"
+
+	"github.com/taramakage/gon-verifier/internal/types/ics721"
+	"github.com/taramakage/gon-verifier/internal/types/wasm"
+)
+
+// Type urls of the msgs decoded from the tx bytes.
+const (
+	MsgTypeIssueDenom        = "/irismod.nft.MsgIssueDenom"
+	MsgTypeMintNft           = "/irismod.nft.MsgMintNFT"
+	MsgTypeTransferNft       = "/irismod.nft.MsgTransferNFT"
+	MsgTypeIbcNft            = "/ibc.applications.nft_transfer.v1.MsgTransfer"
+	MsgTypeWasmExecute       = "/cosmwasm.wasm.v1.MsgExecuteContract"
+	MsgTypeUptickIssueDenom  = "/uptick.collection.v1.MsgIssueDenom"
+	MsgTypeUptickMintNft     = "/uptick.collection.v1.MsgMintNFT"
+	MsgTypeUptickTransferNft = "/uptick.collection.v1.MsgTransferNFT"
+	MsgTypeOnftCreateDenom   = "/OmniFlix.onft.v1beta1.MsgCreateDenom"
+	MsgTypeOnftMint          = "/OmniFlix.onft.v1beta1.MsgMintONFT"
+	MsgTypeOnftTransfer      = "/OmniFlix.onft.v1beta1.MsgTransferONFT"
+)
+
+// Type urls of the msgs issuing a denom and minting an nft on the chains of the nft module or of its forks.
+var (
+	MsgTypesIssueDenom = []string{MsgTypeIssueDenom, MsgTypeUptickIssueDenom, MsgTypeOnftCreateDenom}
+	MsgTypesMintNft    = []string{MsgTypeMintNft, MsgTypeUptickMintNft, MsgTypeOnftMint}
+)
+
+type (
+	// TxBody is a tx decoded from its bytes.
+	TxBody struct {
+		Msgs     []TxMsg
+		Memo     string
+		FeePayer string // the payer of the fee, the signer of the first msg if the tx doesn't set one
+	}
+
+	// TxMsg is a msg of a tx, Value and Signer are only set for the types the decoder knows.
+	TxMsg struct {
+		TypeUrl string
+		Signer  string
+		Value   proto.Message
+	}
+)
+
+// msgDecoder decodes the value of a msg and returns it with its signer.
+type msgDecoder func(bz []byte) (proto.Message, string, error)
+
+// decodeMsg returns the decoder of the msgs of type T signed by signer.
+func decodeMsg[T any, P interface {
+	*T
+	proto.Message
+}](signer func(P) string) msgDecoder {
+	return func(bz []byte) (proto.Message, string, error) {
+		msg := P(new(T))
+		if err := proto.Unmarshal(bz, msg); err != nil {
+			return nil, "", err
+		}
+		return msg, signer(msg), nil
+	}
+}
+
+var msgDecoders = map[string]msgDecoder{
+	MsgTypeIssueDenom:        decodeMsg(func(m *irisnft.MsgIssueDenom) string { return m.Sender }),
+	MsgTypeMintNft:           decodeMsg(func(m *irisnft.MsgMintNFT) string { return m.Sender }),
+	MsgTypeTransferNft:       decodeMsg(func(m *irisnft.MsgTransferNFT) string { return m.Sender }),
+	MsgTypeIbcNft:            decodeMsg(func(m *ics721.MsgTransfer) string { return m.Sender }),
+	MsgTypeWasmExecute:       decodeMsg(func(m *wasm.MsgExecuteContract) string { return m.Sender }),
+	MsgTypeUptickIssueDenom:  decodeMsg(func(m *uptickcoll.MsgIssueDenom) string { return m.Sender }),
+	MsgTypeUptickMintNft:     decodeMsg(func(m *uptickcoll.MsgMintNFT) string { return m.Sender }),
+	MsgTypeUptickTransferNft: decodeMsg(func(m *uptickcoll.MsgTransferNFT) string { return m.Sender }),
+	MsgTypeOnftCreateDenom:   decodeMsg(func(m *onfttypes.MsgCreateDenom) string { return m.Sender }),
+	MsgTypeOnftMint:          decodeMsg(func(m *onfttypes.MsgMintONFT) string { return m.Sender }),
+	MsgTypeOnftTransfer:      decodeMsg(func(m *onfttypes.MsgTransferONFT) string { return m.Sender }),
+}
+
+// DecodeTxBody decodes the bytes of a tx, the msgs of the types unknown to the decoder keep their type url only.
+func DecodeTxBody(txBytes []byte) (*TxBody, error) {
+	var raw txtypes.TxRaw
+	if err := raw.Unmarshal(txBytes); err != nil {
+		return nil, fmt.Errorf("decode tx: %w", err)
+	}
+	var body txtypes.TxBody
+	if err := body.Unmarshal(raw.BodyBytes); err != nil {
+		return nil, fmt.Errorf("decode tx body: %w", err)
+	}
+	var authInfo txtypes.AuthInfo
+	if err := authInfo.Unmarshal(raw.AuthInfoBytes); err != nil {
+		return nil, fmt.Errorf("decode tx auth info: %w", err)
+	}
+
+	tx := &TxBody{Memo: body.Memo}
+	for _, any := range body.Messages {
+		msg := TxMsg{TypeUrl: any.TypeUrl}
+		if decode, ok := msgDecoders[any.TypeUrl]; ok {
+			value, signer, err := decode(any.Value)
+			if err != nil {
+				return nil, fmt.Errorf("decode %s: %w", any.TypeUrl, err)
+			}
+			msg.Value, msg.Signer = value, signer
+		}
+		tx.Msgs = append(tx.Msgs, msg)
+	}
+	if authInfo.Fee != nil {
+		tx.FeePayer = authInfo.Fee.Payer
+	}
+	if len(tx.FeePayer) == 0 && len(tx.Msgs) != 0 {
+		tx.FeePayer = tx.Msgs[0].Signer
+	}
+	return tx, nil
+}
+
+// Body decodes the bytes of the tx, nil if the response doesn't carry them.
+func (tx *TxResponse) Body() (*TxBody, error) {
+	if len(tx.Result.Tx) == 0 {
+		return nil, nil
+	}
+	txBytes, err := base64.StdEncoding.DecodeString(tx.Result.Tx)
+	if err != nil {
+		return nil, fmt.Errorf("decode tx: %w", err)
+	}
+	return DecodeTxBody(txBytes)
+}
+
+// Signer returns the signer of the first msg of a type, of the first msg if no type is given, empty if the tx has none.
+func (b *TxBody) Signer(typeUrls ...string) string {
+	if len(typeUrls) == 0 && len(b.Msgs) != 0 {
+		return b.Msgs[0].Signer
+	}
+	for _, msg := range b.Msgs {
+		for _, typeUrl := range typeUrls {
+			if msg.TypeUrl == typeUrl {
+				return msg.Signer
+			}
+		}
+	}
+	return ""
+}
+
+// Signers returns the signers of the msgs of the types, in the order of the msgs.
+func (b *TxBody) Signers(typeUrls ...string) []string {
+	var signers []string
+	for _, msg := range b.Msgs {
+		for _, typeUrl := range typeUrls {
+			if msg.TypeUrl == typeUrl {
+				signers = append(signers, msg.Signer)
+			}
+		}
+	}
+	return signers
+}
+
+// MsgTypes returns the type urls of the msgs of the tx.
+func (b *TxBody) MsgTypes() []string {
+	typeUrls := make([]string, 0, len(b.Msgs))
+	for _, msg := range b.Msgs {
+		typeUrls = append(typeUrls, msg.TypeUrl)
+	}
+	return typeUrls
+}
@@ -0,0 +1,59 @@
+package types_test
+
+import (
+	"reflect"
+	"testing"
+
+	proto "github.com/gogo/protobuf/proto"
+	nfttypes "github.com/irisnet/irismod/modules/nft/types"
+
+	"github.com/taramakage/gon-verifier/internal/chain/fake"
+	"github.com/taramakage/gon-verifier/internal/types"
+	"github.com/taramakage/gon-verifier/internal/types/ics721"
+	"github.com/taramakage/gon-verifier/internal/types/wasm"
+)
+
+func TestDecodeTxBody(t *testing.T) {
+	transfer := &ics721.MsgTransfer{SourcePort: "nft-transfer", SourceChannel: "channel-0", ClassId: "denom1", TokenIds: []string{"nft1", "nft2"}, Sender: "iaa1user", Receiver: "stars1user"}
+	execute := &wasm.MsgExecuteContract{Sender: "stars1user", Contract: "stars1contract", Msg: []byte(`{"send_nft":{}}`)}
+
+	body, err := types.DecodeTxBody(fake.Tx{Msgs: []proto.Message{transfer, execute}, Memo: "gon", FeeDenom: "uiris", Sequence: 1}.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{types.MsgTypeIbcNft, types.MsgTypeWasmExecute}; !reflect.DeepEqual(body.MsgTypes(), want) {
+		t.Errorf("msg types: want %v, got %v", want, body.MsgTypes())
+	}
+	if !reflect.DeepEqual(body.Msgs[0].Value, transfer) {
+		t.Errorf("want %v, got %v", transfer, body.Msgs[0].Value)
+	}
+	if body.Signer() != "iaa1user" || body.Signer(types.MsgTypeWasmExecute) != "stars1user" || body.Signer(types.MsgTypeMintNft) != "" {
+		t.Errorf("unexpected signers %+v", body.Msgs)
+	}
+	if body.Memo != "gon" {
+		t.Errorf("memo: want gon, got %q", body.Memo)
+	}
+	if body.FeePayer != "iaa1user" {
+		t.Errorf("fee payer: want the first signer, got %q", body.FeePayer)
+	}
+
+	body, err = types.DecodeTxBody(fake.Tx{Msgs: []proto.Message{transfer}, FeePayer: "iaa1payer", FeeDenom: "uiris"}.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body.FeePayer != "iaa1payer" {
+		t.Errorf("fee payer: want iaa1payer, got %q", body.FeePayer)
+	}
+
+	body, err = types.DecodeTxBody(fake.Tx{Msgs: []proto.Message{&nfttypes.MsgBurnNFT{Id: "nft1", Sender: "iaa1user"}}, FeeDenom: "uiris"}.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := body.Msgs[0]; msg.TypeUrl != "/irismod.nft.MsgBurnNFT" || msg.Value != nil || msg.Signer != "" {
+		t.Errorf("want the type url only of an unknown msg, got %+v", msg)
+	}
+
+	if _, err := types.DecodeTxBody([]byte("gon-irishub-1/1")); err == nil {
+		t.Error("want an error decoding bytes which aren't a tx")
+	}
+}
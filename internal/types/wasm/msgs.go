@@ -0,0 +1,21 @@
+package wasm
+
+import (
+	proto "github.com/gogo/protobuf/proto"
+)
+
+// MsgExecuteContract executes a contract, e.g. send_nft of a cw721 contract to the ics721 bridge.
+// Only the fields read by the verifier are mapped.
+type MsgExecuteContract struct {
+	Sender   string             `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	Contract string             `protobuf:"bytes,2,opt,name=contract,proto3" json:"contract,omitempty"`
+	Msg      RawContractMessage `protobuf:"bytes,3,opt,name=msg,proto3,casttype=RawContractMessage" json:"msg,omitempty"`
+}
+
+func (m *MsgExecuteContract) Reset()         { *m = MsgExecuteContract{} }
+func (m *MsgExecuteContract) String() string { return proto.CompactTextString(m) }
+func (*MsgExecuteContract) ProtoMessage()    {}
+
+func init() {
+	proto.RegisterType((*MsgExecuteContract)(nil), "cosmwasm.wasm.v1.MsgExecuteContract")
+}
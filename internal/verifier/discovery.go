@@ -0,0 +1,159 @@
+package verifier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/types"
+)
+
+// Modes of discovery, the evidence is verified as is when discovery is off.
+const (
+	DiscoveryOff     = ""
+	DiscoverySuggest = "suggest" // missing or unknown evidence is replaced with the rows discovered on chain
+	DiscoveryStrict  = "strict"  // the evidence is verified as is, the rows discovered on chain are only compared
+)
+
+// Discoverer proposes the evidence rows of a task from the txs sent by the registered addresses of a participant,
+// in the layout read by BuildParams without the header. It returns no row when nothing is found.
+type Discoverer interface {
+	Discover(user UserInfo) ([][]string, error)
+}
+
+// chainIdValues are the chain ids written in the evidence of the transfer tasks.
+var chainIdValues = map[string]string{
+	chain.ChainIdAbbreviationIris:     chain.ChainIdValueIirs,
+	chain.ChainIdAbbreviationStars:    chain.ChainIdValueStars,
+	chain.ChainIdAbbreviationJuno:     chain.ChainIdValueJuno,
+	chain.ChainIdAbbreviationUptick:   chain.ChainIdValueUptick,
+	chain.ChainIdAbbreviationOmniflix: chain.ChainIdValueOmniflix,
+}
+
+// ValidateDiscovery checks the mode of discovery.
+func ValidateDiscovery(mode string) error {
+	switch mode {
+	case DiscoveryOff, DiscoverySuggest, DiscoveryStrict:
+		return nil
+	}
+	return fmt.Errorf("unknown discovery mode %q", mode)
+}
+
+// sentTxs returns the successful txs of a chain sent by an address and emitting the attribute key of an event, oldest first.
+func sentTxs(r *chain.Registry, abbr, sender, event, key string) ([]types.TxResponse, error) {
+	searcher, ok := r.GetChain(abbr).(chain.TxSearcher)
+	if !ok {
+		return nil, fmt.Errorf("chain %s can't search txs", abbr)
+	}
+	if len(sender) == 0 {
+		return nil, nil
+	}
+	txs, err := searcher.SearchTxs(fmt.Sprintf("%s.%s='%s' AND %s.%s EXISTS",
+		types.EventTypeMessage, types.AttributeMsgSender, sender, event, key))
+	if err != nil {
+		return nil, err
+	}
+
+	sent := txs[:0]
+	for _, tx := range txs {
+		if tx.Result.TxResult.Code == 0 {
+			sent = append(sent, tx)
+		}
+	}
+	return sent, nil
+}
+
+// sentNft is an ibc nft transfer sent by a participant.
+type sentNft struct {
+	hash string
+	dest string // abbreviation of the receiving chain
+	types.TxResultIbcNft
+}
+
+// sentNfts returns the ibc nft transfers sent by the participant from a chain to one of dests, latest first.
+func sentNfts(r *chain.Registry, user UserInfo, src string, dests ...string) ([]sentNft, error) {
+	txs, err := sentTxs(r, src, user.Address[src], types.EventTypeIbcSendPacket, types.AttributeKeySequence)
+	if err != nil {
+		return nil, err
+	}
+
+	var sent []sentNft
+	for i := len(txs) - 1; i >= 0; i-- {
+		txi, err := txs[i].IbcNftPkg()
+		if err != nil {
+			continue
+		}
+		tx := txi.(types.TxResultIbcNft)
+		pcp, err := chain.FindPortChanPair(src, tx.SrcPort, tx.SrcChan)
+		if err != nil {
+			continue
+		}
+		dest := pcp.GetDestPortChan().ChainAbbr
+		for _, d := range dests {
+			if d == dest {
+				sent = append(sent, sentNft{hash: txs[i].Result.Hash, dest: dest, TxResultIbcNft: tx})
+				break
+			}
+		}
+	}
+	return sent, nil
+}
+
+// diffRows describes how the evidence differs from the rows discovered, empty if it doesn't.
+// Rows are numbered as in the sheet, the header being row 1. Only the cells discovered are compared.
+func diffRows(evidence, discovered [][]string) string {
+	var diffs []string
+	for i, row := range discovered {
+		if i >= len(evidence) {
+			diffs = append(diffs, fmt.Sprintf("row %d missing", i+2))
+			continue
+		}
+		for j, cell := range row {
+			got := ""
+			if j < len(evidence[i]) {
+				got = strings.TrimSpace(evidence[i][j])
+			}
+			if !strings.EqualFold(got, cell) {
+				diffs = append(diffs, fmt.Sprintf("row %d col %d want %s got %s", i+2, j+1, cell, got))
+			}
+		}
+	}
+	return strings.Join(diffs, "; ")
+}
+
+// missingEvidence tells whether a result failed because of evidence discovery can fill, a malformed sheet or a tx not found.
+func missingEvidence(reason *Reason) bool {
+	return reason != nil && (reason.Severity == SeverityWarning || reason.Code == ReasonTxResultUnachievable)
+}
+
+// Discovery is the evidence discovered on chain for a task.
+type Discovery struct {
+	TaskNo string
+	Rows   [][]string // rows discovered, none if nothing is found
+	Diff   string     // how the evidence differs from the rows, empty if it doesn't
+	Err    error
+}
+
+// Discover proposes the evidence of every task of a participant a verifier can discover, compared with the evidence file.
+func (gv *GonVerifier) Discover(file string) ([]Discovery, error) {
+	var discoveries []Discovery
+	for _, s := range gv.stages {
+		tm, err := NewTaskManager(file, s.vr, s.opts)
+		if err != nil {
+			return nil, fmt.Errorf("stage %s: %w", s.opts.Stage.Name, err)
+		}
+		for _, task := range tm.tasks {
+			d, ok := task.vf.(Discoverer)
+			if !ok {
+				continue
+			}
+			discovery := Discovery{TaskNo: task.taskNo}
+			discovery.Rows, discovery.Err = d.Discover(tm.user)
+			if discovery.Err == nil && len(discovery.Rows) != 0 {
+				discovery.Diff = diffRows(task.rows, discovery.Rows)
+			}
+			discoveries = append(discoveries, discovery)
+		}
+	}
+	return discoveries, nil
+}
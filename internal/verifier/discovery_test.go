@@ -0,0 +1,145 @@
+package verifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/chain/fake"
+)
+
+func TestDiscover(t *testing.T) {
+	iris := chain.ChainIdAbbreviationIris
+	user := testUser.Address[iris]
+
+	for _, tc := range []struct {
+		name        string
+		newVerifier func(r *chain.Registry) Verifier
+		build       func(n *fake.Network) [][]string // evidence the participant should have written
+	}{
+		{
+			name:        "a1",
+			newVerifier: func(r *chain.Registry) Verifier { return A1Verifier{r: r} },
+			build: func(n *fake.Network) [][]string {
+				n.Chain(iris).IssueDenom(chain.Class{ID: "denom0", Creator: user}, user)
+				hash := n.Chain(iris).IssueDenom(chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+				return [][]string{{hash, "denom1"}}
+			},
+		},
+		{
+			name:        "a2",
+			newVerifier: func(r *chain.Registry) Verifier { return A2Verifier{r: r} },
+			build: func(n *fake.Network) [][]string {
+				c := n.Chain(iris)
+				c.IssueDenom(chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+				rows := make([][]string, 0, 2)
+				for _, id := range []string{"nft1", "nft2"} {
+					hash := c.MintNFT("denom1", chain.NFT{ID: id, URI: "ipfs://nft", Data: "{}", Owner: user}, user)
+					rows = append(rows, []string{hash, "denom1", id})
+				}
+				return rows
+			},
+		},
+		{
+			name:        "a3",
+			newVerifier: func(r *chain.Registry) Verifier { return A3Verifier{r: r} },
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferFromIris(n, chain.ChainIdAbbreviationJuno, testUser.Address["j"], chain.ChainIdValueJuno)}
+			},
+		},
+		{
+			name:        "a4",
+			newVerifier: func(r *chain.Registry) Verifier { return A4Verifier{r: r} },
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferFromIris(n, chain.ChainIdAbbreviationUptick, testUser.Address["u"], chain.ChainIdValueUptick)}
+			},
+		},
+		{
+			name:        "a5",
+			newVerifier: func(r *chain.Registry) Verifier { return A5Verifier{r: r} },
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferToIris(n, chain.ChainIdAbbreviationStars, testUser.Address["s"], chain.ChainIdValueStars)}
+			},
+		},
+		{
+			name:        "a6",
+			newVerifier: func(r *chain.Registry) Verifier { return A6Verifier{r: r} },
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferToIris(n, chain.ChainIdAbbreviationOmniflix, testUser.Address["o"], chain.ChainIdValueOmniflix)}
+			},
+		},
+		{
+			name:        "flow",
+			newVerifier: flowVerifier(t, "b01", false),
+			build: func(n *fake.Network) [][]string {
+				hashes, _ := playFlow(n, hopsB01)
+				return txRows(hashes)
+			},
+		},
+		{
+			name:        "flow ngb",
+			newVerifier: flowVerifier(t, "a01", true),
+			build: func(n *fake.Network) [][]string {
+				_, classID := playFlow(n, hopsA01)
+				return [][]string{{classID, "nft1"}}
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			n := fake.NewNetwork()
+			want := tc.build(n)
+			vf := tc.newVerifier(n.Registry())
+
+			rows, err := vf.(Discoverer).Discover(testUser)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := diffRows(want, rows); len(diff) != 0 || len(rows) != len(want) {
+				t.Fatalf("want rows %v, got %v: %s", want, rows, diff)
+			}
+			if res := verify(t, vf, rows); res.Reason != nil {
+				t.Errorf("discovered rows fail: %q", res.Reason)
+			}
+		})
+	}
+}
+
+func TestTaskManagerDiscovery(t *testing.T) {
+	iris := chain.ChainIdAbbreviationIris
+	user := testUser.Address[iris]
+
+	for _, tc := range []struct {
+		mode   string
+		reason ReasonCode
+		memo   string
+	}{
+		{DiscoveryOff, ReasonTxResultUnachievable, ""},
+		{DiscoveryStrict, ReasonTxResultUnachievable, "discovery: row 2 col 1 want "},
+		{DiscoverySuggest, "", "discovery: suggested row 2 col 1 want "},
+	} {
+		tc := tc
+		t.Run(tc.mode, func(t *testing.T) {
+			n := fake.NewNetwork()
+			hash := n.Chain(iris).IssueDenom(chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+
+			// the hash is truncated by the participant
+			vf := A1Verifier{r: n.Registry()}
+			rows := [][]string{{hash[:40], "denom1"}}
+			params, err := vf.BuildParams(vf.Schema().Records(rows))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tm := &TaskManager{user: testUser, resultCh: make(chan *Response, 1)}
+			tm.verify(Task{taskNo: "A1", point: testPoint, rows: rows, params: params, vf: vf}, tc.mode)
+			res := <-tm.resultCh
+
+			if res.Reason.GetCode() != tc.reason {
+				t.Fatalf("reason: want %q, got %q", tc.reason, res.Reason)
+			}
+			if !strings.HasPrefix(res.Memo, tc.memo) || (len(tc.memo) == 0 && len(res.Memo) != 0) {
+				t.Errorf("memo: want %q, got %q", tc.memo, res.Memo)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package verifier
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/participant"
+	"github.com/taramakage/gon-verifier/internal/scorecard"
+)
+
+// Names of the evidence of a participant: a workbook, a JSON or YAML bundle, or a directory of CSV files.
+const (
+	EvidenceJSON = "evidence.json"
+	EvidenceYAML = "evidence.yaml"
+	EvidenceYML  = "evidence.yml"
+	EvidenceDir  = "evidence"
+)
+
+// EvidenceSource reads the evidence of a participant whatever its format.
+type EvidenceSource interface {
+	// UserInfo returns the team and the registered addresses, the github handle is left to the caller.
+	UserInfo() (UserInfo, error)
+	// Records returns the records of a task, a *HeaderError if the header of its sheet lacks a column of the schema.
+	Records(taskNo string, s Schema) ([]EvidenceRecord, error)
+	Close() error
+
+	// lint checks a sheet against its schema, see Lint.
+	lint(sheet string, s Schema) []LintIssue
+}
+
+// HeaderError is returned when the header of a sheet lacks required columns of its schema.
+type HeaderError struct {
+	Sheet   string
+	Header  []string
+	Missing []string
+}
+
+func (e *HeaderError) Error() string {
+	return fmt.Sprintf("%s sheet: missing header %s", e.Sheet, strings.Join(e.Missing, ", "))
+}
+
+// FieldError is returned when a record of a bundle sets a field its schema has no column for.
+type FieldError struct {
+	Task   string
+	Record int // 1-based
+	Field  string
+	Fields []string // fields of the columns of the schema
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s record %d: field %s isn't one of %s", e.Task, e.Record, e.Field, strings.Join(e.Fields, ", "))
+}
+
+// OpenEvidence opens the evidence of a participant by its name: a directory of CSV files, a .json or .yaml bundle,
+// or else an xlsx workbook. An evidence with another evidence next to it is rejected, see EvidenceFiles.
+func OpenEvidence(path string) (EvidenceSource, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if IsEvidence(info) {
+		if err := soleEvidence(filepath.Dir(path)); err != nil {
+			return nil, err
+		}
+	}
+	if info.IsDir() {
+		return newCSVSource(path), nil
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return readBundle(path, func(bz []byte, b *Bundle) error {
+			dec := json.NewDecoder(bytes.NewReader(bz))
+			dec.DisallowUnknownFields()
+			return dec.Decode(b)
+		})
+	case ".yaml", ".yml":
+		return readBundle(path, func(bz []byte, b *Bundle) error {
+			dec := yaml.NewDecoder(bytes.NewReader(bz))
+			dec.KnownFields(true)
+			return dec.Decode(b)
+		})
+	}
+	return newXlsxSource(path)
+}
+
+// IsEvidence tells whether a file or a directory found under an entrance is the evidence of a participant.
+func IsEvidence(info os.FileInfo) bool {
+	if info.IsDir() {
+		return info.Name() == EvidenceDir
+	}
+	switch info.Name() {
+	case scorecard.DefaultEvidenceFile, EvidenceJSON, EvidenceYAML, EvidenceYML:
+		return true
+	}
+	return false
+}
+
+// EvidenceFiles returns the evidence of every participant under the entrance in lexical order, an error if
+// a directory holds more than one: they would write the same task point files.
+func EvidenceFiles(entrance string) ([]string, error) {
+	files := make([]string, 0)
+	evidenceOf := make(map[string]string)
+	err := filepath.Walk(entrance, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !IsEvidence(info) {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		if other, ok := evidenceOf[dir]; ok {
+			return evidenceConflict(dir, []string{other, path})
+		}
+		evidenceOf[dir] = path
+		files = append(files, path)
+		if info.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// soleEvidence returns an error if a directory holds more than one evidence.
+func soleEvidence(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var found []string
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if IsEvidence(info) {
+			found = append(found, filepath.Join(dir, entry.Name()))
+		}
+	}
+	if len(found) > 1 {
+		return evidenceConflict(dir, found)
+	}
+	return nil
+}
+
+func evidenceConflict(dir string, files []string) error {
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, filepath.Base(file))
+	}
+	return fmt.Errorf("%s holds more than one evidence: %s", dir, strings.Join(names, ", "))
+}
+
+// sheetSource reads the evidence from sheets of rows under a header: the sheets of a workbook or the CSV files of a directory.
+type sheetSource struct {
+	rows  func(sheet string) ([][]string, error)
+	close func() error
+}
+
+func newXlsxSource(file string) (*sheetSource, error) {
+	f, err := excelize.OpenFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return &sheetSource{
+		rows:  func(sheet string) ([][]string, error) { return f.GetRows(sheet) },
+		close: f.Close,
+	}, nil
+}
+
+// newCSVSource reads the sheets of a directory from <sheet>.csv, e.g. Info.csv and A1.csv.
+func newCSVSource(dir string) *sheetSource {
+	return &sheetSource{
+		rows: func(sheet string) ([][]string, error) {
+			f, err := os.Open(filepath.Join(dir, sheet+".csv"))
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			r := csv.NewReader(f)
+			r.FieldsPerRecord = -1
+			return r.ReadAll()
+		},
+		close: func() error { return nil },
+	}
+}
+
+// UserInfo reads the first row under the header of the Info sheet.
+func (ss *sheetSource) UserInfo() (UserInfo, error) {
+	sheet, err := ss.rows(InfoSheet)
+	if err != nil {
+		return UserInfo{}, errors.New("info sheet not found")
+	}
+	l := infoSchema.layout(sheet)
+	if len(l.missing) != 0 {
+		return UserInfo{}, &HeaderError{Sheet: InfoSheet, Header: sheet[0], Missing: l.missing}
+	}
+	if l.start >= len(sheet) {
+		return UserInfo{}, errors.New("info sheet format error")
+	}
+
+	columns := l.reorder(sheet[l.start])
+	user := UserInfo{
+		TeamName: columns[0],
+		Address:  make(map[string]string, len(infoChains)),
+	}
+	for i, abbr := range infoChains {
+		user.Address[abbr] = columns[i+1]
+	}
+	return user, nil
+}
+
+// Records reads the rows of the sheet of a task under its header.
+func (ss *sheetSource) Records(taskNo string, s Schema) ([]EvidenceRecord, error) {
+	sheet, err := ss.rows(taskNo)
+	if err != nil {
+		return nil, err
+	}
+	if len(sheet) == 0 {
+		return nil, errors.New("evidence sheet is empty")
+	}
+	rows, missing := s.Rows(sheet)
+	if len(missing) != 0 {
+		return nil, &HeaderError{Sheet: taskNo, Header: sheet[0], Missing: missing}
+	}
+	return s.Records(rows), nil
+}
+
+func (ss *sheetSource) Close() error {
+	return ss.close()
+}
+
+// Bundle is the evidence of a participant in a JSON or YAML file, a task left out has no record.
+type Bundle struct {
+	Team      string                      `json:"team" yaml:"team"`
+	Addresses map[string]string           `json:"addresses" yaml:"addresses"` // keyed by chain name, e.g. iris
+	Tasks     map[string][]EvidenceRecord `json:"tasks" yaml:"tasks"`
+}
+
+// readBundle reads a bundle with the decoder of its format.
+func readBundle(file string, decode func(bz []byte, b *Bundle) error) (*Bundle, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var b Bundle
+	if err := decode(bz, &b); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+	}
+	return &b, nil
+}
+
+func (b *Bundle) UserInfo() (UserInfo, error) {
+	if len(b.Team) == 0 && len(b.Addresses) == 0 {
+		return UserInfo{}, errors.New("info not found")
+	}
+	user := UserInfo{
+		TeamName: b.Team,
+		Address:  make(map[string]string, len(infoChains)),
+	}
+	known := make(map[string]bool, len(infoChains))
+	for _, abbr := range infoChains {
+		name := chain.ChainNames[abbr]
+		known[name] = true
+		user.Address[abbr] = b.Addresses[name]
+	}
+	for name := range b.Addresses {
+		if !known[name] {
+			return UserInfo{}, fmt.Errorf("address of unknown chain %q", name)
+		}
+	}
+	return user, nil
+}
+
+// Records returns the records of a task, a *FieldError if one sets a field the schema has no column for.
+func (b *Bundle) Records(taskNo string, s Schema) ([]EvidenceRecord, error) {
+	records := b.Tasks[taskNo]
+	for i, r := range records {
+		if field, ok := s.unknownField(r); ok {
+			return nil, &FieldError{Task: taskNo, Record: i + 1, Field: field, Fields: s.fields()}
+		}
+	}
+	return records, nil
+}
+
+func (b *Bundle) Close() error {
+	return nil
+}
+
+// EvidenceConflicts returns the evidence under the entrance that matches no participant of the registry, or the
+// participant of another evidence.
+func EvidenceConflicts(entrance string, participants *participant.Registry) ([]string, error) {
+	files, err := EvidenceFiles(entrance)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []string
+	evidenceOf := make(map[string]string, len(files))
+	for _, file := range files {
+		user, err := readUserInfo(file, participants)
+		if err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		if other, ok := evidenceOf[user.Id]; ok {
+			conflicts = append(conflicts, fmt.Sprintf("%s: participant %s already has evidence %s", file, user.Id, other))
+			continue
+		}
+		evidenceOf[user.Id] = file
+	}
+	return conflicts, nil
+}
+
+// readUserInfo reads the participant of an evidence file.
+func readUserInfo(file string, participants *participant.Registry) (UserInfo, error) {
+	evidence, err := OpenEvidence(file)
+	if err != nil {
+		return UserInfo{}, err
+	}
+	defer evidence.Close()
+	return ReadUserInfo(evidence, filepath.Dir(file), participants)
+}
@@ -0,0 +1,168 @@
+package verifier
+
+import (
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/scorecard"
+)
+
+func TestEvidenceSources(t *testing.T) {
+	hash := strings.Repeat("a", 64)
+	contract := "stars1contract"
+	sheets := map[string][][]string{
+		InfoSheet: {{"team", "iris", "stars", "juno", "uptick", "omniflix"}, {"team", "iaa1user", "stars1user", "juno1user", "uptick1user", "omniflix1user"}},
+		"A1":      {{"class id", "tx hash"}, {"denom1", hash}},
+		"A3":      {{"tx hash", "contract", "token id", "chain id"}, {hash, contract, "nft1", chain.ChainIdValueStars}},
+	}
+	bundle := map[string]string{
+		EvidenceJSON: `{
+	"team": "team",
+	"addresses": {"iris": "iaa1user", "stars": "stars1user", "juno": "juno1user", "uptick": "uptick1user", "omniflix": "omniflix1user"},
+	"tasks": {
+		"A1": [{"tx_hash": "` + hash + `", "class_id": "denom1"}],
+		"A3": [{"tx_hash": "` + hash + `", "contract": "` + contract + `", "token_id": "nft1", "chain_id": "elgafar-1"}]
+	}
+}`,
+		EvidenceYAML: `team: team
+addresses: {iris: iaa1user, stars: stars1user, juno: juno1user, uptick: uptick1user, omniflix: omniflix1user}
+tasks:
+  A1:
+    - {tx_hash: ` + hash + `, class_id: denom1}
+  A3:
+    - {tx_hash: ` + hash + `, contract: ` + contract + `, token_id: nft1, chain_id: elgafar-1}
+`,
+	}
+
+	dir := t.TempDir()
+	writeEvidence(t, dir, "xlsx", sheets)
+	for name, content := range bundle {
+		writeFile(t, filepath.Join(dir, name, name), content)
+	}
+	for name, rows := range sheets {
+		var sb strings.Builder
+		if err := csv.NewWriter(&sb).WriteAll(rows); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, "csv", EvidenceDir, name+".csv"), sb.String())
+	}
+
+	files, err := EvidenceFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dir, "csv", EvidenceDir),
+		filepath.Join(dir, EvidenceJSON, EvidenceJSON),
+		filepath.Join(dir, EvidenceYAML, EvidenceYAML),
+		filepath.Join(dir, "xlsx", scorecard.DefaultEvidenceFile),
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("evidence files: want %v, got %v", want, files)
+	}
+
+	wantUser := UserInfo{TeamName: "team", Address: testUser.Address}
+	wantRecords := map[string][]EvidenceRecord{
+		"A1": {{TxHash: hash, ClassId: "denom1"}},
+		"A3": {{TxHash: hash, Contract: contract, TokenId: "nft1", ChainId: chain.ChainIdValueStars}},
+	}
+	schemas := map[string]Schema{"A1": A1Verifier{}.Schema(), "A3": A3Verifier{}.Schema()}
+	for _, file := range files {
+		source, err := OpenEvidence(file)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		defer source.Close()
+
+		user, err := source.UserInfo()
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if !reflect.DeepEqual(user, wantUser) {
+			t.Errorf("%s: want user %+v, got %+v", file, wantUser, user)
+		}
+		for taskNo, want := range wantRecords {
+			records, err := source.Records(taskNo, schemas[taskNo])
+			if err != nil {
+				t.Fatalf("%s: %v", file, err)
+			}
+			if !reflect.DeepEqual(records, want) {
+				t.Errorf("%s %s: want records %+v, got %+v", file, taskNo, want, records)
+			}
+		}
+	}
+}
+
+func TestOpenEvidenceUnknownField(t *testing.T) {
+	file := filepath.Join(t.TempDir(), EvidenceJSON)
+	writeFile(t, file, `{"team": "team", "tasks": {"A1": [{"txhash": "a"}]}}`)
+	if _, err := OpenEvidence(file); err == nil {
+		t.Error("want an error for an unknown field")
+	}
+
+	writeFile(t, file, `{"team": "team", "addresses": {"cosmos": "cosmos1user"}}`)
+	source, err := OpenEvidence(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.UserInfo(); err == nil {
+		t.Error("want an error for the address of an unknown chain")
+	}
+}
+
+func TestEvidenceOnePerDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeEvidence(t, dir, "alice", map[string][][]string{InfoSheet: {{"team", "iris"}, {"team", "iaa1alice"}}})
+	writeFile(t, filepath.Join(dir, "alice", EvidenceJSON), `{"team": "team", "addresses": {"iris": "iaa1alice"}}`)
+	writeFile(t, filepath.Join(dir, "bob", EvidenceJSON), `{"team": "team", "addresses": {"iris": "iaa1bob"}}`)
+
+	if _, err := EvidenceFiles(dir); err == nil || !strings.Contains(err.Error(), "more than one evidence") {
+		t.Errorf("want an error for two evidence of alice, got %v", err)
+	}
+	for _, file := range []string{EvidenceJSON, scorecard.DefaultEvidenceFile} {
+		if _, err := OpenEvidence(filepath.Join(dir, "alice", file)); err == nil {
+			t.Errorf("%s: want an error for two evidence of alice", file)
+		}
+	}
+	source, err := OpenEvidence(filepath.Join(dir, "bob", EvidenceJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source.Close()
+}
+
+func TestBundleRecordsSchema(t *testing.T) {
+	b := &Bundle{Tasks: map[string][]EvidenceRecord{
+		"A1": {{TxHash: "a", ClassId: "denom1"}, {TxHash: "b", TokenId: "nft1"}},
+	}}
+	_, err := b.Records("A1", A1Verifier{}.Schema())
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("want a field error, got %v", err)
+	}
+	if fieldErr.Record != 2 || fieldErr.Field != ColumnTokenId || !reflect.DeepEqual(fieldErr.Fields, []string{ColumnTxHash, ColumnClassId}) {
+		t.Errorf("unexpected field error %+v", fieldErr)
+	}
+
+	records, err := b.Records("A2", A2Verifier{}.Schema())
+	if err != nil || len(records) != 0 {
+		t.Errorf("want no record of a task left out, got %v %v", records, err)
+	}
+}
+
+// writeFile writes a file and its directories.
+func writeFile(t *testing.T, file, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,126 @@
+package verifier
+
+import "strings"
+
+// sheetLayout is where the columns of a schema are in a sheet.
+type sheetLayout struct {
+	cols    []int    // index of each column of the schema in a row, -1 if the sheet lacks it
+	start   int      // index of the first data row
+	missing []string // names of the required columns the header lacks
+}
+
+// normalizeHeader folds the case and drops the spaces, _ and - of a header, so "Tx Hash", "tx_hash" and "txhash" match.
+func normalizeHeader(header string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(header)))
+}
+
+// layout locates the columns of the schema in the header of a sheet by their names, then by their aliases.
+// A column the header doesn't name keeps its position when the header cell there is blank, else it is missing.
+// A first row naming no column is a header of unknown names unless it holds values, the columns keep their positions.
+func (s Schema) layout(sheet [][]string) sheetLayout {
+	l := sheetLayout{cols: make([]int, len(s.Columns)), start: 1}
+	if len(sheet) == 0 {
+		return l
+	}
+	header := make([]string, len(sheet[0]))
+	for i, h := range sheet[0] {
+		header[i] = normalizeHeader(h)
+	}
+
+	claimed := make(map[int]bool)
+	find := func(name string) int {
+		for i, h := range header {
+			if h == normalizeHeader(name) && !claimed[i] {
+				return i
+			}
+		}
+		return -1
+	}
+	for j := range l.cols {
+		l.cols[j] = find(s.Columns[j].Name)
+		if l.cols[j] >= 0 {
+			claimed[l.cols[j]] = true
+		}
+	}
+	for j, col := range s.Columns {
+		for _, alias := range col.Aliases {
+			if l.cols[j] >= 0 {
+				break
+			}
+			if l.cols[j] = find(alias); l.cols[j] >= 0 {
+				claimed[l.cols[j]] = true
+			}
+		}
+	}
+
+	if len(claimed) == 0 {
+		for j := range l.cols {
+			l.cols[j] = j
+		}
+		if s.isData(sheet[0]) {
+			l.start = 0
+		}
+		return l
+	}
+	for j, col := range s.Columns {
+		if l.cols[j] >= 0 {
+			continue
+		}
+		if !claimed[j] && len(cellAt(header, j)) == 0 {
+			l.cols[j] = j
+			claimed[j] = true
+			continue
+		}
+		if !col.Optional {
+			l.missing = append(l.missing, col.Name)
+		}
+	}
+	return l
+}
+
+// isData tells whether a row holds values rather than a header: a cell of a column whose values have a shape has it.
+func (s Schema) isData(row []string) bool {
+	for j, col := range s.Columns {
+		switch col.Kind {
+		case ColumnTxHash, ColumnIbcClassId, ColumnAddress:
+			if value := cellAt(row, j); len(value) != 0 && len(s.checkCell(col, value, row)) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Rows returns the data rows of a sheet laid out as the columns of the schema, a column the sheet lacks is empty.
+// It returns the names of the required columns missing from the header instead, if any.
+func (s Schema) Rows(sheet [][]string) ([][]string, []string) {
+	l := s.layout(sheet)
+	if len(l.missing) != 0 {
+		return nil, l.missing
+	}
+	if l.start >= len(sheet) {
+		return nil, nil
+	}
+	rows := make([][]string, 0, len(sheet)-l.start)
+	for _, row := range sheet[l.start:] {
+		rows = append(rows, l.reorder(row))
+	}
+	return rows, nil
+}
+
+// reorder lays out a row of the sheet as the columns of the schema.
+func (l sheetLayout) reorder(row []string) []string {
+	values := make([]string, len(l.cols))
+	for j, i := range l.cols {
+		if i >= 0 && i < len(row) {
+			values[j] = row[i]
+		}
+	}
+	return values
+}
@@ -0,0 +1,126 @@
+package verifier
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/chain/fake"
+	"github.com/taramakage/gon-verifier/internal/scorecard"
+)
+
+func TestSchemaRows(t *testing.T) {
+	hash := strings.Repeat("a", 64)
+	a3 := A3Verifier{}.Schema()
+	flow := FlowVerifier{f: mustFlow(t, "a01")}.Schema()
+
+	for _, tc := range []struct {
+		name    string
+		s       Schema
+		sheet   [][]string
+		rows    [][]string
+		missing []string
+	}{
+		{
+			name:  "columns in order",
+			s:     a3,
+			sheet: [][]string{{"tx hash", "contract", "token id", "chain id"}, {hash, "stars1contract", "nft1", chain.ChainIdValueStars}},
+			rows:  [][]string{{hash, "stars1contract", "nft1", chain.ChainIdValueStars}},
+		},
+		{
+			name:  "aliases reordered with extra columns",
+			s:     a3,
+			sheet: [][]string{{"Chain", "note", "Tx Hash", "NFT ID", "class_id"}, {chain.ChainIdValueStars, "mine", hash, "nft1", "stars1contract"}},
+			rows:  [][]string{{hash, "stars1contract", "nft1", chain.ChainIdValueStars}},
+		},
+		{
+			name:    "missing header",
+			s:       a3,
+			sheet:   [][]string{{"txhash", "contract", "note", "chain id"}, {hash, "stars1contract", "nft1", chain.ChainIdValueStars}},
+			missing: []string{"token id"},
+		},
+		{
+			name:  "blank header keeps the position",
+			s:     flow,
+			sheet: [][]string{{"TxHash"}, {hash, "nft1"}, {hash}},
+			rows:  [][]string{{hash, "nft1"}, {hash, ""}},
+		},
+		{
+			name:  "unknown header names",
+			s:     a3,
+			sheet: [][]string{{"哈希", "合约", "编号", "链"}, {hash, "stars1contract", "nft1", chain.ChainIdValueStars}},
+			rows:  [][]string{{hash, "stars1contract", "nft1", chain.ChainIdValueStars}},
+		},
+		{
+			name:  "no header",
+			s:     a3,
+			sheet: [][]string{{hash, "stars1contract", "nft1", chain.ChainIdValueStars}},
+			rows:  [][]string{{hash, "stars1contract", "nft1", chain.ChainIdValueStars}},
+		},
+		{
+			name:  "info reordered",
+			s:     infoSchema,
+			sheet: [][]string{{"omniflix", "Team Name", "iaa", "stars", "juno", "uptick"}, {"o", "team", "i", "s", "j", "u"}},
+			rows:  [][]string{{"team", "i", "s", "j", "u", "o"}},
+		},
+		{
+			name:  "info of the team header only",
+			s:     infoSchema,
+			sheet: [][]string{{"team_name"}, {"team", "i", "s", "j", "u", "o"}},
+			rows:  [][]string{{"team", "i", "s", "j", "u", "o"}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			rows, missing := tc.s.Rows(tc.sheet)
+			if !reflect.DeepEqual(missing, tc.missing) {
+				t.Fatalf("missing: want %v, got %v", tc.missing, missing)
+			}
+			if !reflect.DeepEqual(rows, tc.rows) {
+				t.Errorf("rows: want %v, got %v", tc.rows, rows)
+			}
+		})
+	}
+}
+
+func TestTaskManagerHeaders(t *testing.T) {
+	hash := strings.Repeat("a", 64)
+	stage := &campaign.Stage{Name: "one", Tasks: []campaign.Task{{No: "A1", Verifier: campaign.VerifierA1}, {No: "A2", Verifier: campaign.VerifierA2}}}
+	opts := &Options{Campaign: &campaign.Campaign{Stages: []campaign.Stage{*stage}}, Stage: stage}
+	vr, err := NewRegistry(fake.NewNetwork().Registry(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeEvidence(t, dir, "alice", map[string][][]string{
+		InfoSheet: {{"stars", "team", "iris"}, {"stars1user", "team", "iaa1user"}},
+		"A1":      {{"class id", "tx hash"}, {"denom1", hash}},
+		"A2":      {{"tx hash", "token id"}, {hash, "nft1"}, {hash, "nft2"}},
+	})
+	tm, err := NewTaskManager(filepath.Join(dir, "alice", scorecard.DefaultEvidenceFile), vr, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tm.user.TeamName != "team" || tm.user.Address[chain.ChainIdAbbreviationIris] != "iaa1user" || tm.user.Address[chain.ChainIdAbbreviationStars] != "stars1user" {
+		t.Errorf("unexpected user %+v", tm.user)
+	}
+	if params := tm.tasks[0].params.(A1Params); params.TxHash != hash || params.ClassId != "denom1" {
+		t.Errorf("A1: unexpected params %+v", params)
+	}
+	if reason := tm.tasks[1].reason; reason.GetCode() != ReasonParamsHeaderMissing || reason.Expected != "class id" {
+		t.Errorf("A2: want the class id header missing, got %v", reason)
+	}
+}
+
+func mustFlow(t *testing.T, flowId string) *chain.Flow {
+	t.Helper()
+	f, err := chain.LookupFlow(flowId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
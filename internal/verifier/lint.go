@@ -0,0 +1,279 @@
+package verifier
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	nfttypes "github.com/irisnet/irismod/modules/nft/types"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+)
+
+// InfoSheet is the sheet of the team and the registered addresses of a participant.
+const InfoSheet = "Info"
+
+// infoChains are the chains of the addresses of the Info sheet, from its second column on.
+var infoChains = chain.Abbreviations
+
+// LintIssue is a problem of the evidence at a cell of a sheet, of the whole sheet if Cell is empty.
+type LintIssue struct {
+	Sheet   string
+	Cell    string
+	Message string
+}
+
+func (i LintIssue) String() string {
+	if len(i.Cell) == 0 {
+		return fmt.Sprintf("%s: %s", i.Sheet, i.Message)
+	}
+	return fmt.Sprintf("%s!%s: %s", i.Sheet, i.Cell, i.Message)
+}
+
+// Lint checks the evidence against the schemas of the tasks of every stage, without querying any chain.
+// The evidence is read by OpenEvidence, the issues of a bundle are at a field of a record rather than a cell.
+func Lint(evidencePath string, c *campaign.Campaign) ([]LintIssue, error) {
+	evidence, err := OpenEvidence(evidencePath)
+	if err != nil {
+		return nil, err
+	}
+	defer evidence.Close()
+
+	schemas, err := taskSchemas(c, c.Stages)
+	if err != nil {
+		return nil, err
+	}
+	issues := evidence.lint(InfoSheet, infoSchema)
+	for _, ts := range schemas {
+		issues = append(issues, evidence.lint(ts.taskNo, ts.schema)...)
+	}
+	return issues, nil
+}
+
+// lint checks the rows of a sheet against the schema.
+func (ss *sheetSource) lint(sheet string, s Schema) []LintIssue {
+	rows, err := ss.rows(sheet)
+	if err != nil {
+		return []LintIssue{{Sheet: sheet, Message: "sheet not found"}}
+	}
+	if len(rows) == 0 {
+		return []LintIssue{{Sheet: sheet, Message: "sheet is empty"}}
+	}
+
+	l := s.layout(rows)
+	if len(l.missing) != 0 {
+		issues := make([]LintIssue, 0, len(l.missing))
+		for _, name := range l.missing {
+			issues = append(issues, LintIssue{Sheet: sheet, Message: fmt.Sprintf("missing header %q", name)})
+		}
+		return issues
+	}
+
+	rows = rows[l.start:]
+	for i := range rows {
+		rows[i] = l.reorder(rows[i])
+	}
+	return s.lintRows(sheet, rows, func(i, j int) string {
+		return cellName(l.cols[j], l.start+i+1)
+	}, func(i int) string {
+		return fmt.Sprintf("row %d", l.start+i+1)
+	})
+}
+
+// lint checks the records of a task, or the team and the addresses, against the schema.
+func (b *Bundle) lint(sheet string, s Schema) []LintIssue {
+	if sheet == InfoSheet {
+		user, err := b.UserInfo()
+		if err != nil {
+			return []LintIssue{{Sheet: sheet, Message: err.Error()}}
+		}
+		row := []string{user.TeamName}
+		for _, abbr := range infoChains {
+			row = append(row, user.Address[abbr])
+		}
+		return s.lintRows(sheet, [][]string{row}, func(_, j int) string {
+			if j == 0 {
+				return "team"
+			}
+			return "addresses." + chain.ChainNames[infoChains[j-1]]
+		}, func(int) string { return "info" })
+	}
+
+	records, err := b.Records(sheet, s)
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return []LintIssue{{Sheet: sheet, Cell: recordName(fieldErr.Record - 1), Message: fmt.Sprintf("unknown field %s", fieldErr.Field)}}
+	}
+	return s.lintRows(sheet, s.RowsOf(records), func(i, j int) string {
+		return recordName(i) + "." + s.Columns[j].Kind
+	}, recordName)
+}
+
+// lintRows checks rows laid out as the columns of the schema, cell and name locate a cell and a row by zero based indexes.
+func (s Schema) lintRows(sheet string, rows [][]string, cell func(i, j int) string, name func(i int) string) []LintIssue {
+	var issues []LintIssue
+	switch {
+	case len(rows) < s.MinRows && s.MinRows == s.MaxRows:
+		issues = append(issues, LintIssue{Sheet: sheet, Message: fmt.Sprintf("want %d rows, got %d", s.MinRows, len(rows))})
+	case len(rows) < s.MinRows:
+		issues = append(issues, LintIssue{Sheet: sheet, Message: fmt.Sprintf("want at least %d rows, got %d", s.MinRows, len(rows))})
+	case s.MaxRows > 0 && len(rows) > s.MaxRows:
+		issues = append(issues, LintIssue{Sheet: sheet, Message: fmt.Sprintf("want %d rows, got %d", s.MaxRows, len(rows))})
+	}
+
+	seen := make(map[string]int)
+	for i, row := range rows {
+		for j, col := range s.Columns {
+			value := cellAt(row, j)
+			if len(value) == 0 {
+				if !col.Optional {
+					issues = append(issues, LintIssue{Sheet: sheet, Cell: cell(i, j), Message: "missing " + col.Name})
+				}
+				continue
+			}
+			if msg := s.checkCell(col, value, row); len(msg) != 0 {
+				issues = append(issues, LintIssue{Sheet: sheet, Cell: cell(i, j), Message: msg})
+			}
+		}
+
+		key := rowKey(row, len(s.Columns))
+		if len(key) == 0 {
+			continue
+		}
+		if prev, ok := seen[key]; ok {
+			issues = append(issues, LintIssue{Sheet: sheet, Cell: cell(i, 0), Message: "duplicates " + name(prev)})
+			continue
+		}
+		seen[key] = i
+	}
+	return issues
+}
+
+// recordName names a record of a bundle by its zero based index, e.g. record 1.
+func recordName(i int) string {
+	return fmt.Sprintf("record %d", i+1)
+}
+
+// checkCell checks the value of a column in a row, it returns the problem found or empty.
+func (s Schema) checkCell(col Column, value string, row []string) string {
+	switch col.Kind {
+	case ColumnTxHash:
+		if !isHex(value, 64) {
+			return fmt.Sprintf("tx hash %q isn't 64 hex characters", value)
+		}
+	case ColumnClassId:
+		if nfttypes.ValidateDenomID(value) != nil {
+			return fmt.Sprintf("class id %q isn't a denom id", value)
+		}
+	case ColumnIbcClassId:
+		if !strings.HasPrefix(value, "ibc/") || !isHex(strings.TrimPrefix(value, "ibc/"), 64) {
+			return fmt.Sprintf("ibc class id %q isn't ibc/ and 64 hex characters", value)
+		}
+	case ColumnChainId:
+		if _, ok := chainOf(value, col.Chains); !ok {
+			return fmt.Sprintf("unknown chain id %q; want %s", value, strings.Join(chainIdsOf(col.Chains), " or "))
+		}
+	case ColumnAddress:
+		return checkAddress(value, col.Chains[0])
+	case ColumnContract:
+		chains := s.rowChains(row)
+		contract := strings.TrimPrefix(value, "wasm.")
+		for _, abbr := range chains {
+			if len(checkAddress(contract, abbr)) == 0 {
+				return ""
+			}
+		}
+		names := make([]string, 0, len(chains))
+		for _, abbr := range chains {
+			names = append(names, chain.Bech32Prefixes[abbr])
+		}
+		return fmt.Sprintf("contract %q isn't a %s address", value, strings.Join(names, " or "))
+	}
+	return ""
+}
+
+// rowChains returns the chain named by the chain id column of a row, every chain of the column if it names none.
+func (s Schema) rowChains(row []string) []string {
+	for j, col := range s.Columns {
+		if col.Kind != ColumnChainId {
+			continue
+		}
+		if abbr, ok := chainOf(cellAt(row, j), col.Chains); ok {
+			return []string{abbr}
+		}
+		return col.Chains
+	}
+	return nil
+}
+
+// checkAddress checks an address is bech32 with the prefix of a chain, it returns the problem found or empty.
+func checkAddress(address, abbr string) string {
+	prefix, _, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return fmt.Sprintf("address %q isn't bech32", address)
+	}
+	if prefix != chain.Bech32Prefixes[abbr] {
+		return fmt.Sprintf("address %q has prefix %s; want %s", address, prefix, chain.Bech32Prefixes[abbr])
+	}
+	return ""
+}
+
+// chainOf returns the abbreviation of the chain of a chain id among chains.
+func chainOf(chainId string, chains []string) (string, bool) {
+	for _, abbr := range chains {
+		if chainIdValues[abbr] == chainId {
+			return abbr, true
+		}
+	}
+	return "", false
+}
+
+// chainIdsOf returns the chain ids of chains.
+func chainIdsOf(chains []string) []string {
+	chainIds := make([]string, 0, len(chains))
+	for _, abbr := range chains {
+		chainIds = append(chainIds, chainIdValues[abbr])
+	}
+	return chainIds
+}
+
+// isHex tells whether s is n hex characters.
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// cellAt returns the trimmed cell of a row, empty if the row is shorter.
+func cellAt(row []string, col int) string {
+	if col >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[col])
+}
+
+// rowKey joins the trimmed cells of the first n columns of a row, empty if they are all empty.
+func rowKey(row []string, n int) string {
+	cells := make([]string, n)
+	empty := true
+	for j := range cells {
+		cells[j] = cellAt(row, j)
+		empty = empty && len(cells[j]) == 0
+	}
+	if empty {
+		return ""
+	}
+	return strings.Join(cells, "\t")
+}
+
+// cellName returns the name of the cell at a zero based column and a one based row, e.g. B2.
+func cellName(col, row int) string {
+	name, _ := excelize.CoordinatesToCellName(col+1, row)
+	return name
+}
@@ -0,0 +1,127 @@
+package verifier
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/scorecard"
+)
+
+func TestLint(t *testing.T) {
+	c := &campaign.Campaign{
+		Name: "test",
+		Stages: []campaign.Stage{{
+			Name: "one",
+			Tasks: []campaign.Task{
+				{No: "A1", Verifier: campaign.VerifierA1},
+				{No: "A2", Verifier: campaign.VerifierA2},
+				{No: "A3", Verifier: campaign.VerifierA3},
+				{No: "A4", Verifier: campaign.VerifierA4},
+				{No: "B1", Verifier: campaign.VerifierFlow, Flow: "a01"},
+			},
+		}},
+	}
+	address := func(abbr string, b byte) string {
+		addr, err := bech32.ConvertAndEncode(chain.Bech32Prefixes[abbr], []byte{19: b})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addr
+	}
+	hash := func(c string) string { return strings.Repeat(c, 64) }
+	ibcClass := "ibc/" + hash("A")
+	info := []string{"team"}
+	for _, abbr := range infoChains {
+		info = append(info, address(abbr, 1))
+	}
+
+	valid := map[string][][]string{
+		InfoSheet: {{"team", "iris", "stars", "juno", "uptick", "omniflix"}, info},
+		"A1":      {{"tx hash", "class id"}, {hash("a"), "denom1"}},
+		"A2":      {{"tx hash", "class id", "token id"}, {hash("b"), "denom1", "nft1"}, {hash("c"), "denom1", "nft2"}},
+		"A3":      {{"tx hash", "contract", "token id", "chain id"}, {hash("d"), "wasm." + address(chain.ChainIdAbbreviationStars, 2), "nft1", chain.ChainIdValueStars}},
+		"A4":      {{"tx hash", "class id", "token id", "chain id"}, {hash("e"), ibcClass, "nft1", chain.ChainIdValueUptick}},
+		"B1":      {{"tx hash"}, {hash("1"), "nft1"}, {hash("2")}, {hash("3")}},
+	}
+	dir := t.TempDir()
+	writeEvidence(t, dir, "valid", valid)
+	issues, err := Lint(filepath.Join(dir, "valid", scorecard.DefaultEvidenceFile), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("want no issue, got %v", issues)
+	}
+
+	invalid := map[string][][]string{
+		InfoSheet: {{"team", "iris", "stars", "juno", "uptick", "omniflix"}, {"team", info[1], info[1], "juno1user", info[4]}},
+		"A1":      {{"tx hash", "class id"}, {hash("a")[1:]}},
+		"A2":      {{"tx hash", "class id", "token id"}, {hash("b"), "denom1", "nft1"}, {hash("b") + " ", "denom1", "nft1"}},
+		"A3":      {{"tx hash", "contract", "token id", "chain id"}, {hash("d"), address(chain.ChainIdAbbreviationJuno, 2), "nft1", chain.ChainIdValueStars}},
+		"A4":      {{"tx hash", "class id", "token id", "chain id"}, {hash("g"), "ibc/denom1", "nft1", chain.ChainIdValueIirs}},
+	}
+	writeEvidence(t, dir, "invalid", invalid)
+	issues, err = Lint(filepath.Join(dir, "invalid", scorecard.DefaultEvidenceFile), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`Info!C2: address "` + info[1] + `" has prefix iaa; want stars`,
+		`Info!D2: address "juno1user" isn't bech32`,
+		`Info!F2: missing omniflix address`,
+		`A1!A2: tx hash "` + hash("a")[1:] + `" isn't 64 hex characters`,
+		`A1!B2: missing class id`,
+		`A2!A3: duplicates row 2`,
+		`A3!B2: contract "` + address(chain.ChainIdAbbreviationJuno, 2) + `" isn't a stars address`,
+		`A4!A2: tx hash "` + hash("g") + `" isn't 64 hex characters`,
+		`A4!B2: ibc class id "ibc/denom1" isn't ibc/ and 64 hex characters`,
+		`A4!D2: unknown chain id "gon-irishub-1"; want uptick_7000-2 or gon-flixnet-1`,
+		`B1: sheet not found`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want issues\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	bundle := filepath.Join(dir, "bundle", EvidenceJSON)
+	writeFile(t, bundle, `{
+	"team": "team",
+	"addresses": {"iris": "`+info[1]+`", "stars": "`+info[1]+`"},
+	"tasks": {
+		"A1": [{"tx_hash": "`+hash("a")+`", "class_id": "denom1", "token_id": "nft1"}],
+		"A2": [{"tx_hash": "`+hash("b")+`", "class_id": "denom1", "token_id": "nft1"}, {"tx_hash": "`+hash("b")+`", "class_id": "denom1", "token_id": "nft1"}],
+		"A4": [{"tx_hash": "`+hash("e")+`", "ibc_class_id": "ibc/denom1", "token_id": "nft1", "chain_id": "uptick_7000-2"}],
+		"B1": [{"tx_hash": "`+hash("1")+`"}, {"tx_hash": "`+hash("2")+`"}, {"tx_hash": "`+hash("3")+`"}]
+	}
+}`)
+	issues, err = Lint(bundle, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = got[:0]
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want = []string{
+		`Info!addresses.stars: address "` + info[1] + `" has prefix iaa; want stars`,
+		`Info!addresses.juno: missing juno address`,
+		`Info!addresses.uptick: missing uptick address`,
+		`Info!addresses.omniflix: missing omniflix address`,
+		`A1!record 1: unknown field token_id`,
+		`A2!record 2.tx_hash: duplicates record 1`,
+		`A3: want 1 rows, got 0`,
+		`A4!record 1.ibc_class_id: ibc class id "ibc/denom1" isn't ibc/ and 64 hex characters`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want issues\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
@@ -1,33 +0,0 @@
-package verifier
-
-var PointMap = map[string]int32{
-	"A1":  1,
-	"A2":  1,
-	"A3":  1,
-	"A4":  1,
-	"A5":  1,
-	"A6":  1,
-	"A7":  2,
-	"A8":  2,
-	"A9":  2,
-	"A10": 2,
-	"A11": 3,
-	"A12": 3,
-	"A13": 2,
-	"A14": 2,
-	"A15": 2,
-	"A16": 2,
-	"A17": 2,
-	"A18": 2,
-	"A19": 3,
-	"A20": 3,
-	"B1":  5,
-	"B2":  5,
-	"B3":  50,
-	"B4":  50,
-	"B5":  10,
-	"B6":  10,
-	"B7":  10,
-	"B8":  150,
-	"B9":  10,
-}
@@ -1,34 +1,202 @@
 package verifier
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+)
+
+// ReasonCode is the stable code of a reason, machine-readable results carry it so consumers don't match on text.
+type ReasonCode string
+
+// Severity tells whether a reason costs the point and who can fix it.
+type Severity string
+
 const (
-	ReasonParamsFormatIncorrect = "Params: format is incorrect"
-	ReasonParamsChainIdEmpty    = "Params: chainId not found"
-	ReasonParamsChainIdError    = "Params: chainId is error"
-
-	ReasonTxResultUnexpected   = "Tx: result is unexpected"
-	ReasonTxResultUnachievable = "Tx: result is unachievable"
-	ReasonTxResultUnsuccessful = "Tx: result is unsuccessful"
-	ReasonTxMsgSenderNotMatch  = "Tx: sender not match register address"
-
-	ReasonClassNotFound        = "Class: not found"
-	ReasonClassCreatorNotMatch = "Class: creator not match register address"
-	ReasonClassDataInvalid     = "Class: data is invalid"
-	ReasonClassUrIEmpty        = "Class: uri is empty"
-
-	ReasonNftNotFound          = "NFT: not found"
-	ReasonNftOwnerNotMatch     = "NFT: initial owner not match register address"
-	ReasonNftRecipientNotMatch = "NFT: recipient not match register address"
-	ReasonNftTokenIdNotMatch   = "NFT: token id not match"
-	ReasonNftUriEmpty          = "NFT: uri is empty"
-	ReasonNftDataEmpty         = "NFT: data is empty"
-
-	ReasonIbcDestPortNotMatch        = "IBC: dest port not match"
-	ReasonIbcDestChanNotMatch        = "IBC: dest channel not match"
-	ReasonIbcClassNotMatch           = "IBC: ibc class not match"
-	ReasonIbcOriginalClassIdNotMatch = "IBC: original class id not match"
-
-	ReasonRaceUnexpectedFlowPath  = "Race: race flow unexpected"
-	ReasonRaceFirstLastSenderNotMatch = "Race: first and last sender not match"
-	ReasonRaceDataUnachievable    = "Race: data is unachievable"
-	ReasonRaceStartTooEarly = "Race: you start too early"
-)
\ No newline at end of file
+	SeverityWarning Severity = "warning" // the evidence is malformed, the participant can fix the sheet
+	SeverityError   Severity = "error"   // the evidence doesn't match the chain
+)
+
+const (
+	ReasonParamsFormatIncorrect ReasonCode = "params_format_incorrect"
+	ReasonParamsChainIdEmpty    ReasonCode = "params_chain_id_empty"
+	ReasonParamsChainIdError    ReasonCode = "params_chain_id_error"
+	ReasonParamsRowsInvalid     ReasonCode = "params_rows_invalid"
+	ReasonParamsExampleRowLeft  ReasonCode = "params_example_row_left"
+	ReasonParamsHeaderMissing   ReasonCode = "params_header_missing"
+
+	ReasonTxResultUnexpected   ReasonCode = "tx_result_unexpected"
+	ReasonTxResultUnachievable ReasonCode = "tx_result_unachievable"
+	ReasonTxResultUnsuccessful ReasonCode = "tx_result_unsuccessful"
+	ReasonTxMsgSenderNotMatch  ReasonCode = "tx_sender_not_match"
+	ReasonTxProofInvalid       ReasonCode = "tx_proof_invalid"
+	ReasonTxTooEarly           ReasonCode = "tx_too_early"
+	ReasonTxTooLate            ReasonCode = "tx_too_late"
+	ReasonTxMsgTypeNotMatch    ReasonCode = "tx_msg_type_not_match"
+	ReasonTxFeePayerNotMatch   ReasonCode = "tx_fee_payer_not_match"
+
+	ReasonClassNotFound        ReasonCode = "class_not_found"
+	ReasonClassCreatorNotMatch ReasonCode = "class_creator_not_match"
+	ReasonClassDataInvalid     ReasonCode = "class_data_invalid"
+	ReasonClassUrIEmpty        ReasonCode = "class_uri_empty"
+
+	ReasonNftNotFound          ReasonCode = "nft_not_found"
+	ReasonNftOwnerNotMatch     ReasonCode = "nft_owner_not_match"
+	ReasonNftRecipientNotMatch ReasonCode = "nft_recipient_not_match"
+	ReasonNftTokenIdNotMatch   ReasonCode = "nft_token_id_not_match"
+	ReasonNftUriEmpty          ReasonCode = "nft_uri_empty"
+	ReasonNftDataEmpty         ReasonCode = "nft_data_empty"
+	ReasonNftTokenAmbiguous    ReasonCode = "nft_token_ambiguous"
+
+	ReasonIbcDestPortNotMatch        ReasonCode = "ibc_dest_port_not_match"
+	ReasonIbcDestChanNotMatch        ReasonCode = "ibc_dest_chan_not_match"
+	ReasonIbcClassNotMatch           ReasonCode = "ibc_class_not_match"
+	ReasonIbcOriginalClassIdNotMatch ReasonCode = "ibc_original_class_id_not_match"
+	ReasonIbcPacketTimeout           ReasonCode = "ibc_packet_timeout"
+	ReasonIbcPacketNotReceived       ReasonCode = "ibc_packet_not_received"
+	ReasonIbcPacketAckError          ReasonCode = "ibc_packet_ack_error"
+	ReasonIbcPacketNotAcknowledged   ReasonCode = "ibc_packet_not_acknowledged"
+	ReasonIbcRouteNotMatch           ReasonCode = "ibc_route_not_match"
+	ReasonIbcRouteUntraceable        ReasonCode = "ibc_route_untraceable"
+
+	ReasonRaceUnexpectedFlowPath      ReasonCode = "race_unexpected_flow_path"
+	ReasonRaceFirstLastSenderNotMatch ReasonCode = "race_first_last_sender_not_match"
+	ReasonRaceDataUnachievable        ReasonCode = "race_data_unachievable"
+	ReasonRaceStartTooEarly           ReasonCode = "race_start_too_early"
+)
+
+// reasonMessages are the texts of the reasons written to the task point files.
+var reasonMessages = map[ReasonCode]string{
+	ReasonParamsFormatIncorrect: "Params: format is incorrect",
+	ReasonParamsChainIdEmpty:    "Params: chainId not found",
+	ReasonParamsChainIdError:    "Params: chainId is error",
+	ReasonParamsRowsInvalid:     "Params: number of rows is incorrect",
+	ReasonParamsExampleRowLeft:  "Params: example row should be replaced with evidence rather than left there",
+	ReasonParamsHeaderMissing:   "Params: header of a column is missing",
+
+	ReasonTxResultUnexpected:   "Tx: result is unexpected",
+	ReasonTxResultUnachievable: "Tx: result is unachievable",
+	ReasonTxResultUnsuccessful: "Tx: result is unsuccessful",
+	ReasonTxMsgSenderNotMatch:  "Tx: sender not match register address",
+	ReasonTxProofInvalid:       "Tx: proof not match block header",
+	ReasonTxTooEarly:           "Tx: sent before the task opened",
+	ReasonTxTooLate:            "Tx: sent after the task closed",
+	ReasonTxMsgTypeNotMatch:    "Tx: msg type not match task",
+	ReasonTxFeePayerNotMatch:   "Tx: fee payer not match register address",
+
+	ReasonClassNotFound:        "Class: not found",
+	ReasonClassCreatorNotMatch: "Class: creator not match register address",
+	ReasonClassDataInvalid:     "Class: data is invalid",
+	ReasonClassUrIEmpty:        "Class: uri is empty",
+
+	ReasonNftNotFound:          "NFT: not found",
+	ReasonNftOwnerNotMatch:     "NFT: initial owner not match register address",
+	ReasonNftRecipientNotMatch: "NFT: recipient not match register address",
+	ReasonNftTokenIdNotMatch:   "NFT: token id not match",
+	ReasonNftUriEmpty:          "NFT: uri is empty",
+	ReasonNftDataEmpty:         "NFT: data is empty",
+	ReasonNftTokenAmbiguous:    "NFT: token ambiguous in batch packet",
+
+	ReasonIbcDestPortNotMatch:        "IBC: dest port not match",
+	ReasonIbcDestChanNotMatch:        "IBC: dest channel not match",
+	ReasonIbcClassNotMatch:           "IBC: ibc class not match",
+	ReasonIbcOriginalClassIdNotMatch: "IBC: original class id not match",
+	ReasonIbcPacketTimeout:           "IBC: packet timed out",
+	ReasonIbcPacketNotReceived:       "IBC: packet not received on dest",
+	ReasonIbcPacketAckError:          "IBC: packet acknowledged with error",
+	ReasonIbcPacketNotAcknowledged:   "IBC: packet not acknowledged on src",
+	ReasonIbcRouteNotMatch:           "IBC: route taken not match the flow",
+	ReasonIbcRouteUntraceable:        "IBC: route taken can't be traced",
+
+	ReasonRaceUnexpectedFlowPath:      "Race: race flow unexpected",
+	ReasonRaceFirstLastSenderNotMatch: "Race: first and last sender not match",
+	ReasonRaceDataUnachievable:        "Race: data is unachievable",
+	ReasonRaceStartTooEarly:           "Race: you start too early",
+}
+
+// Reason explains the result of a task, it is rendered to text only by the sinks.
+type Reason struct {
+	Code     ReasonCode
+	Severity Severity
+	Hop      int    // 1-based hop of the flow that failed, 0 if not about a hop
+	Row      int    // 1-based evidence row that failed, 0 if not about a row
+	Expected string // value the verifier wanted, e.g. the channel of the hop
+	Actual   string // value found in the evidence or on chain
+}
+
+// NewReason creates a reason with the severity of its code.
+func NewReason(code ReasonCode) *Reason {
+	severity := SeverityError
+	if strings.HasPrefix(string(code), "params_") {
+		severity = SeverityWarning
+	}
+	return &Reason{
+		Code:     code,
+		Severity: severity,
+	}
+}
+
+// NewTxReason creates the reason of a tx the chain didn't return, its proof failed or it wasn't found.
+func NewTxReason(err error) *Reason {
+	if errors.Is(err, chain.ErrTxProofInvalid) {
+		return NewReason(ReasonTxProofInvalid)
+	}
+	return NewReason(ReasonTxResultUnachievable)
+}
+
+// AtHop sets the 1-based hop of the flow that failed.
+func (r *Reason) AtHop(hop int) *Reason {
+	r.Hop = hop
+	return r
+}
+
+// AtRow sets the 1-based evidence row that failed.
+func (r *Reason) AtRow(row int) *Reason {
+	r.Row = row
+	return r
+}
+
+// Want sets the expected and the actual value.
+func (r *Reason) Want(expected, actual string) *Reason {
+	r.Expected = expected
+	r.Actual = actual
+	return r
+}
+
+// GetCode returns the code, empty for a nil reason.
+func (r *Reason) GetCode() ReasonCode {
+	if r == nil {
+		return ""
+	}
+	return r.Code
+}
+
+// String renders the reason, e.g. "IBC: dest channel not match (hop 2; want channel-5; got channel-3)".
+// Details are separated by ";" as the scorecard joins the reasons of a participant with ",".
+func (r *Reason) String() string {
+	if r == nil {
+		return ""
+	}
+
+	msg, ok := reasonMessages[r.Code]
+	if !ok {
+		msg = string(r.Code)
+	}
+
+	details := make([]string, 0, 3)
+	if r.Hop > 0 {
+		details = append(details, fmt.Sprintf("hop %d", r.Hop))
+	}
+	if r.Row > 0 {
+		details = append(details, fmt.Sprintf("row %d", r.Row))
+	}
+	if len(r.Expected) != 0 || len(r.Actual) != 0 {
+		details = append(details, "want "+r.Expected, "got "+r.Actual)
+	}
+	if len(details) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s (%s)", msg, strings.Join(details, "; "))
+}
@@ -0,0 +1,91 @@
+package verifier
+
+// EvidenceRecord is an evidence row read by BuildParams, a field per kind of column, empty if the task has no such column.
+type EvidenceRecord struct {
+	TxHash     string `json:"tx_hash,omitempty" yaml:"tx_hash,omitempty"`
+	ClassId    string `json:"class_id,omitempty" yaml:"class_id,omitempty"`
+	IbcClassId string `json:"ibc_class_id,omitempty" yaml:"ibc_class_id,omitempty"`
+	Contract   string `json:"contract,omitempty" yaml:"contract,omitempty"`
+	TokenId    string `json:"token_id,omitempty" yaml:"token_id,omitempty"`
+	ChainId    string `json:"chain_id,omitempty" yaml:"chain_id,omitempty"`
+}
+
+// field returns the field of a kind of column, nil for the kinds a record doesn't hold.
+func (r *EvidenceRecord) field(kind string) *string {
+	switch kind {
+	case ColumnTxHash:
+		return &r.TxHash
+	case ColumnClassId:
+		return &r.ClassId
+	case ColumnIbcClassId:
+		return &r.IbcClassId
+	case ColumnContract:
+		return &r.Contract
+	case ColumnTokenId:
+		return &r.TokenId
+	case ColumnChainId:
+		return &r.ChainId
+	}
+	return nil
+}
+
+// recordFields are the kinds of column a record holds, in the order of its fields.
+var recordFields = []string{ColumnTxHash, ColumnClassId, ColumnIbcClassId, ColumnContract, ColumnTokenId, ColumnChainId}
+
+// fields returns the fields of a record the schema has a column for.
+func (s Schema) fields() []string {
+	fields := make([]string, 0, len(s.Columns))
+	for _, col := range s.Columns {
+		if new(EvidenceRecord).field(col.Kind) != nil {
+			fields = append(fields, col.Kind)
+		}
+	}
+	return fields
+}
+
+// unknownField returns the first field set in a record that the schema has no column for.
+func (s Schema) unknownField(r EvidenceRecord) (string, bool) {
+	for _, kind := range recordFields {
+		if len(*r.field(kind)) == 0 {
+			continue
+		}
+		known := false
+		for _, col := range s.Columns {
+			known = known || col.Kind == kind
+		}
+		if !known {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+// Records reads rows laid out as the columns of the schema, a row shorter than the schema leaves the fields empty.
+func (s Schema) Records(rows [][]string) []EvidenceRecord {
+	records := make([]EvidenceRecord, 0, len(rows))
+	for _, row := range rows {
+		var r EvidenceRecord
+		for j, col := range s.Columns {
+			if f := r.field(col.Kind); f != nil && j < len(row) {
+				*f = row[j]
+			}
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+// RowsOf lays out records as the columns of the schema.
+func (s Schema) RowsOf(records []EvidenceRecord) [][]string {
+	rows := make([][]string, 0, len(records))
+	for _, r := range records {
+		row := make([]string, len(s.Columns))
+		for j, col := range s.Columns {
+			if f := r.field(col.Kind); f != nil {
+				row[j] = *f
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -1,6 +1,9 @@
 package verifier
 
 import (
+	"fmt"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
 	"github.com/taramakage/gon-verifier/internal/chain"
 )
 
@@ -8,80 +11,63 @@ type Registry struct {
 	vs map[string]Verifier
 }
 
-const (
-	VerifyRegistryStageOne = iota
-	VerifyRegistryStageTwo
-	VerifyRegistryStageTwoShadow
-	VerifyRegistryStageThree
-)
-
-func NewRegistry(r *chain.Registry, stage int) *Registry {
-	switch stage {
-	case VerifyRegistryStageOne:
-		return NewRegistryOne(r)
-	case VerifyRegistryStageTwo:
-		return NewRegistryTwo(r)
-	case VerifyRegistryStageTwoShadow:
-		return NewRegistryTwoShadow(r)
-	case VerifyRegistryStageThree:
-		return NewRegistryThree(r)
-	}
-	return nil
-}
-
-func NewRegistryOne(r *chain.Registry) *Registry {
-	vs := map[string]Verifier{
-		"A1": A1Verifier{r},
-		"A2": A2Verifier{r},
-		"A3": A3Verifier{r},
-		"A4": A4Verifier{r},
-		"A5": A5Verifier{r},
-		"A6": A6Verifier{r},
-	}
-	return &Registry{vs}
-}
-
-func NewRegistryTwo(r *chain.Registry) *Registry {
-	vs := map[string]Verifier{
-		"A7":  NewFlowVerifier(r, "a01", true),
-		"A8":  NewFlowVerifier(r, "a02", true),
-		"A9":  NewFlowVerifier(r, "a03", true),
-		"A10": NewFlowVerifier(r, "a04", true),
-		"A11": NewFlowVerifier(r, "a05", true),
-		"A12": NewFlowVerifier(r, "a06", true),
-		"A13": NewFlowVerifier(r, "b01", false),
-		"A14": NewFlowVerifier(r, "b02", false),
-		"A15": NewFlowVerifier(r, "b03", false),
-		"A16": NewFlowVerifier(r, "b04", false),
-		"A17": NewFlowVerifier(r, "c01", false),
-		"A18": NewFlowVerifier(r, "c02", false),
-		"A19": NewFlowVerifier(r, "c03", false),
-		"A20": NewFlowVerifier(r, "c04", false),
-	}
-	return &Registry{vs}
-}
-
-func NewRegistryTwoShadow(r *chain.Registry) *Registry {
-	vs := map[string]Verifier{
-		"A7":  NewFlowVerifier(r, "a01b", true),
-		"A9":  NewFlowVerifier(r, "a03b", true),
-		"A11": NewFlowVerifier(r, "a05b", true),
-		"A16": NewFlowVerifier(r, "b04b", false),
-		"A17": NewFlowVerifier(r, "c01b", false),
-		"A19": NewFlowVerifier(r, "c03b", false),
+// NewRegistry builds the verifiers of a stage according to the campaign,
+// they query the state of the chains at the heights of the stage.
+func NewRegistry(r *chain.Registry, opts *Options) (*Registry, error) {
+	r = r.AtHeights(opts.Stage.Heights)
+	vs := make(map[string]Verifier, len(opts.Stage.Tasks))
+	windows := make(map[*campaign.Window]*campaign.Window) // the window of the stage is resolved once
+	for _, task := range opts.Stage.Tasks {
+		w, ok := windows[opts.Stage.WindowOf(task)]
+		if !ok {
+			resolved, err := resolveWindow(r, opts.Stage.WindowOf(task))
+			if err != nil {
+				return nil, fmt.Errorf("task %s: %w", task.No, err)
+			}
+			windows[opts.Stage.WindowOf(task)], w = resolved, resolved
+		}
+		vf, err := newVerifier(r, opts.Campaign, task, w)
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.No, err)
+		}
+		vs[task.No] = vf
 	}
-	return &Registry{vs}
+	return &Registry{vs}, nil
 }
 
-func NewRegistryThree(r *chain.Registry) *Registry {
-	vs := map[string]Verifier{
-		"B1": NewRaceVerifier(r, DenomIdGonIndivRace1, LastOwner, StartBlockHeightIndivRace1, EndBlockHeightIndivRace),
-		"B2": NewRaceVerifier(r, DenomIdGonIndivRace2, LastOwner, StartBlockHeightIndivRace2, EndBlockHeightIndivRace),
-		"B5": NewRaceVerifier(r, DenomIdGonTeamRace1, LastOwner, StartBlockHeightTeamRace, EndBlockHeightTeamRace),
-		"B6": NewRaceVerifier(r, DenomIdGonTeamRace2, LastOwner, StartBlockHeightTeamRace, EndBlockHeightTeamRace),
-		"B7": NewRaceVerifier(r, DenomIdGonTeamRace3, LastOwner, StartBlockHeightTeamRace, EndBlockHeightTeamRace),
+func newVerifier(r *chain.Registry, c *campaign.Campaign, task campaign.Task, w *campaign.Window) (Verifier, error) {
+	switch task.Verifier {
+	case campaign.VerifierA1:
+		return A1Verifier{r: r, w: w, feePayer: task.FeePayer}, nil
+	case campaign.VerifierA2:
+		return A2Verifier{r: r, w: w, feePayer: task.FeePayer}, nil
+	case campaign.VerifierA3:
+		return A3Verifier{r: r, w: w, feePayer: task.FeePayer}, nil
+	case campaign.VerifierA4:
+		return A4Verifier{r: r, w: w, feePayer: task.FeePayer}, nil
+	case campaign.VerifierA5:
+		return A5Verifier{r: r, w: w, feePayer: task.FeePayer}, nil
+	case campaign.VerifierA6:
+		return A6Verifier{r: r, w: w, feePayer: task.FeePayer}, nil
+	case campaign.VerifierFlow:
+		vf, err := NewFlowVerifier(r, task.Flow, task.Ngb)
+		if err != nil {
+			return nil, err
+		}
+		vf.strict = task.StrictRoute
+		vf.feePayer = task.FeePayer
+		vf.w = w
+		return vf, nil
+	case campaign.VerifierRace:
+		race, ok := c.Races[task.Race]
+		if !ok {
+			return nil, fmt.Errorf("unknown race %q", task.Race)
+		}
+		rv := NewRaceVerifier(r, race.Denom, race.Owner, race.StartHeight, race.EndHeight)
+		rv.feePayer = task.FeePayer
+		return rv, nil
 	}
-	return &Registry{vs}
+	return nil, fmt.Errorf("unknown verifier %q", task.Verifier)
 }
 
 // Get returns a verifier by key.
@@ -0,0 +1,43 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain/fake"
+)
+
+func TestNewRegistry(t *testing.T) {
+	c, err := campaign.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := fake.NewNetwork().Registry()
+
+	for i := range c.Stages {
+		stage := &c.Stages[i]
+		vr, err := NewRegistry(r, &Options{Campaign: c, Stage: stage})
+		if err != nil {
+			t.Fatalf("stage %s: %v", stage.Name, err)
+		}
+		for _, no := range stage.TaskNos() {
+			if vr.Get(no) == nil {
+				t.Errorf("stage %s: no verifier for task %s", stage.Name, no)
+			}
+		}
+	}
+}
+
+func TestNewRegistryUnknownFlow(t *testing.T) {
+	c, err := campaign.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stage := campaign.Stage{
+		Name:  "broken",
+		Tasks: []campaign.Task{{No: "A7", Verifier: campaign.VerifierFlow, Flow: "z99"}},
+	}
+	if _, err := NewRegistry(fake.NewNetwork().Registry(), &Options{Campaign: c, Stage: &stage}); err == nil {
+		t.Fatal("want error for unknown flow")
+	}
+}
@@ -0,0 +1,104 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+)
+
+// Kinds of the evidence columns, they tell how a cell is linted.
+const (
+	ColumnTxHash     = "tx_hash"      // hex hash of a tx
+	ColumnClassId    = "class_id"     // id of a native class
+	ColumnIbcClassId = "ibc_class_id" // ibc/<hex hash of the class trace>
+	ColumnContract   = "contract"     // cw721 contract on the chain of the chain id column, optionally prefixed by wasm.
+	ColumnTokenId    = "token_id"     // id of an nft
+	ColumnChainId    = "chain_id"     // chain id of one of the chains of the column
+	ColumnTeam       = "team"         // name of the team
+	ColumnAddress    = "address"      // bech32 address on the chain of the column
+)
+
+type (
+	// Schema is the layout of the evidence rows of a task read by BuildParams, without the header.
+	Schema struct {
+		Columns  []Column
+		MinRows  int
+		MaxRows  int      // no limit if 0
+		RowNames []string // what each row holds, e.g. the hops of a flow, optional
+	}
+
+	// Column is a column of the evidence rows, found in a sheet by its name or one of its aliases in the header.
+	Column struct {
+		Name     string
+		Aliases  []string
+		Kind     string
+		Optional bool
+		Chains   []string // abbreviations of the chains a chain id column may name, or of the chain of an address
+	}
+)
+
+// taskSchema is the schema of the evidence sheet of a task.
+type taskSchema struct {
+	taskNo string
+	schema Schema
+}
+
+// taskSchemas returns the schemas of the tasks of the stages in order, once for a task shared by stages.
+// The verifiers are built without chains, only to declare their schema.
+func taskSchemas(c *campaign.Campaign, stages []campaign.Stage) ([]taskSchema, error) {
+	var schemas []taskSchema
+	seen := make(map[string]bool)
+	for _, stage := range stages {
+		for _, task := range stage.Tasks {
+			if seen[task.No] {
+				continue
+			}
+			seen[task.No] = true
+
+			vf, err := newVerifier(nil, c, task, nil)
+			if err != nil {
+				return nil, fmt.Errorf("task %s: %w", task.No, err)
+			}
+			schemas = append(schemas, taskSchema{taskNo: task.No, schema: vf.Schema()})
+		}
+	}
+	return schemas, nil
+}
+
+// Required columns shared by the schemas.
+var (
+	txHashColumn     = Column{Name: "tx hash", Aliases: []string{"hash", "tx", "transaction hash"}, Kind: ColumnTxHash}
+	classIdColumn    = Column{Name: "class id", Aliases: []string{"class", "denom", "denom id"}, Kind: ColumnClassId}
+	ibcClassIdColumn = Column{Name: "ibc class id", Aliases: []string{"class id", "class", "ibc class"}, Kind: ColumnIbcClassId}
+	contractColumn   = Column{Name: "contract", Aliases: []string{"contract address", "class id", "class"}, Kind: ColumnContract}
+	tokenIdColumn    = Column{Name: "token id", Aliases: []string{"token", "nft id", "nft"}, Kind: ColumnTokenId}
+)
+
+// chainIdColumn returns the chain id column naming one of the chains.
+func chainIdColumn(chains ...string) Column {
+	return Column{Name: "chain id", Aliases: []string{"chain"}, Kind: ColumnChainId, Chains: chains}
+}
+
+// infoSchema is the layout of the Info sheet: the team and an address per chain.
+var infoSchema = func() Schema {
+	s := Schema{
+		Columns: []Column{{Name: "team", Aliases: []string{"team name", "name"}, Kind: ColumnTeam}},
+		MinRows: 1,
+	}
+	for _, abbr := range infoChains {
+		name := chain.ChainNames[abbr]
+		s.Columns = append(s.Columns, Column{
+			Name:    name + " address",
+			Aliases: []string{name, chain.Bech32Prefixes[abbr]},
+			Kind:    ColumnAddress,
+			Chains:  []string{abbr},
+		})
+	}
+	return s
+}()
+
+// rowsSchema returns the schema of exactly n rows.
+func rowsSchema(n int, columns ...Column) Schema {
+	return Schema{Columns: columns, MinRows: n, MaxRows: n}
+}
@@ -0,0 +1,318 @@
+package verifier
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/taramakage/gon-verifier/internal/taskpoint"
+)
+
+// Formats of the task results.
+const (
+	OutputXlsx = "xlsx"
+	OutputJSON = "json"
+	OutputCSV  = "csv"
+)
+
+// DefaultOutputs keep the task point files read by rank and scorecard.
+var DefaultOutputs = []string{OutputXlsx}
+
+type (
+	// Sink receives the task results of one stage of a participant.
+	Sink interface {
+		Write(result *Response) error
+		// Close saves the results, the sink is not usable afterwards.
+		Close() error
+	}
+
+	// Record is a task result in JSON Lines and CSV.
+	Record struct {
+		Task        string                `json:"task"`
+		Participant string                `json:"participant"`
+		Team        string                `json:"team"`
+		Github      string                `json:"github"`
+		Point       int32                 `json:"point"`
+		ReasonCode  ReasonCode            `json:"reason_code,omitempty"`
+		Severity    Severity              `json:"severity,omitempty"`
+		Hop         int                   `json:"hop,omitempty"`
+		Row         int                   `json:"row,omitempty"`
+		Expected    string                `json:"expected,omitempty"`
+		Actual      string                `json:"actual,omitempty"`
+		Reason      string                `json:"reason,omitempty"` // rendered text as in the xlsx
+		Chain       string                `json:"chain,omitempty"`
+		Txs         []RecordTx            `json:"txs,omitempty"`
+		Race        *taskpoint.RaceResult `json:"race,omitempty"`
+		Memo        string                `json:"memo,omitempty"`
+	}
+
+	RecordTx struct {
+		Chain  string `json:"chain"`
+		Hash   string `json:"hash"`
+		Height int64  `json:"height"`
+	}
+
+	multiSink []Sink
+
+	xlsxSink struct {
+		f      *excelize.File
+		file   string
+		sheet  string
+		rowIdx int
+	}
+
+	jsonSink struct {
+		f   *os.File
+		enc *json.Encoder
+	}
+
+	csvSink struct {
+		f *os.File
+		w *csv.Writer
+	}
+)
+
+var csvHeader = []string{
+	"task", "participant", "team", "github", "point",
+	"reason_code", "severity", "hop", "row", "expected", "actual", "reason",
+	"chain", "tx_hashes", "heights",
+	"race_flow", "race_token_id", "race_start_height", "race_end_height", "race_diff", "race_owner",
+	"memo",
+}
+
+// ValidateOutputs checks the output formats.
+func ValidateOutputs(outputs []string) error {
+	if len(outputs) == 0 {
+		return errors.New("no output format")
+	}
+	for _, output := range outputs {
+		switch output {
+		case OutputXlsx, OutputJSON, OutputCSV:
+		default:
+			return fmt.Errorf("unknown output format %q", output)
+		}
+	}
+	return nil
+}
+
+// NewSink creates the sinks of every output format, the results of a stage are written next to the evidence as
+// the task point file with the extension of the format, e.g. taskpoint1.xlsx, taskpoint1.jsonl and taskpoint1.csv.
+func NewSink(outputs []string, dir, taskPointFile string) (Sink, error) {
+	base := strings.TrimSuffix(taskPointFile, filepath.Ext(taskPointFile))
+	sinks := make(multiSink, 0, len(outputs))
+	for _, output := range outputs {
+		var (
+			s   Sink
+			err error
+		)
+		switch output {
+		case OutputXlsx:
+			s, err = newXlsxSink(filepath.Join(dir, base+".xlsx"))
+		case OutputJSON:
+			s, err = newJSONSink(filepath.Join(dir, base+".jsonl"))
+		case OutputCSV:
+			s, err = newCSVSink(filepath.Join(dir, base+".csv"))
+		default:
+			err = fmt.Errorf("unknown output format %q", output)
+		}
+		if err != nil {
+			sinks.Close()
+			return nil, err
+		}
+		sinks = append(sinks, s)
+	}
+	return sinks, nil
+}
+
+// NewRecord converts a task result to a record.
+func NewRecord(result *Response) Record {
+	r := Record{
+		Task:        result.TaskNo,
+		Participant: result.Participant,
+		Team:        result.TeamName,
+		Github:      result.Github,
+		Point:       result.Point,
+		Reason:      result.Reason.String(),
+		Chain:       result.Chain,
+		Race:        result.Race,
+		Memo:        result.Memo,
+	}
+	if reason := result.Reason; reason != nil {
+		r.ReasonCode = reason.Code
+		r.Severity = reason.Severity
+		r.Hop = reason.Hop
+		r.Row = reason.Row
+		r.Expected = reason.Expected
+		r.Actual = reason.Actual
+	}
+	for _, tx := range result.Txs {
+		r.Txs = append(r.Txs, RecordTx(tx))
+	}
+	return r
+}
+
+func (ms multiSink) Write(result *Response) error {
+	for _, s := range ms {
+		if err := s.Write(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ms multiSink) Close() error {
+	var firstErr error
+	for _, s := range ms {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func newXlsxSink(file string) (*xlsxSink, error) {
+	f := excelize.NewFile()
+
+	sheetName := "result"
+	index, err := f.NewSheet(sheetName)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	f.SetActiveSheet(index)
+
+	if err := f.SetSheetRow(sheetName, "A1", &taskpoint.Header); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &xlsxSink{
+		f:      f,
+		file:   file,
+		sheet:  sheetName,
+		rowIdx: 1,
+	}, nil
+}
+
+func (s *xlsxSink) Write(result *Response) error {
+	s.f.SetCellValue(s.sheet, fmt.Sprintf("A%d", s.rowIdx+1), result.TaskNo)
+	s.f.SetCellValue(s.sheet, fmt.Sprintf("B%d", s.rowIdx+1), result.TeamName)
+	s.f.SetCellValue(s.sheet, fmt.Sprintf("C%d", s.rowIdx+1), result.Point)
+	s.f.SetCellValue(s.sheet, fmt.Sprintf("D%d", s.rowIdx+1), result.Reason.String())
+	if len(result.Participant) != 0 {
+		s.f.SetCellValue(s.sheet, taskpoint.Cell(taskpoint.ParticipantColumn, s.rowIdx+1), result.Participant)
+	}
+	if race := result.Race; race != nil {
+		row := race.Row()
+		if err := s.f.SetSheetRow(s.sheet, taskpoint.Cell(taskpoint.RaceColumn, s.rowIdx+1), &row); err != nil {
+			return err
+		}
+	}
+	s.rowIdx++
+	return nil
+}
+
+func (s *xlsxSink) Close() error {
+	if err := s.f.SaveAs(s.file); err != nil {
+		s.f.Close()
+		return err
+	}
+	return s.f.Close()
+}
+
+func newJSONSink(file string) (*jsonSink, error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonSink{
+		f:   f,
+		enc: json.NewEncoder(f),
+	}, nil
+}
+
+func (s *jsonSink) Write(result *Response) error {
+	return s.enc.Encode(NewRecord(result))
+}
+
+func (s *jsonSink) Close() error {
+	return s.f.Close()
+}
+
+func newCSVSink(file string) (*csvSink, error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, err
+	}
+	s := &csvSink{
+		f: f,
+		w: csv.NewWriter(f),
+	}
+	if err := s.w.Write(csvHeader); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *csvSink) Write(result *Response) error {
+	r := NewRecord(result)
+	race := make([]string, 6)
+	if r.Race != nil {
+		race = []string{
+			r.Race.Flow,
+			r.Race.TokenId,
+			strconv.FormatInt(r.Race.StartHeight, 10),
+			strconv.FormatInt(r.Race.EndHeight, 10),
+			strconv.FormatInt(r.Race.Diff, 10),
+			r.Race.Owner,
+		}
+	}
+	hashes := make([]string, 0, len(r.Txs))
+	heights := make([]string, 0, len(r.Txs))
+	for _, tx := range r.Txs {
+		hashes = append(hashes, tx.Hash)
+		heights = append(heights, strconv.FormatInt(tx.Height, 10))
+	}
+	return s.w.Write(append([]string{
+		r.Task,
+		r.Participant,
+		r.Team,
+		r.Github,
+		strconv.FormatInt(int64(r.Point), 10),
+		string(r.ReasonCode),
+		string(r.Severity),
+		optionalInt(r.Hop),
+		optionalInt(r.Row),
+		r.Expected,
+		r.Actual,
+		r.Reason,
+		r.Chain,
+		strings.Join(hashes, ";"),
+		strings.Join(heights, ";"),
+	}, append(race, r.Memo)...))
+}
+
+func (s *csvSink) Close() error {
+	s.w.Flush()
+	if err := s.w.Error(); err != nil {
+		s.f.Close()
+		return err
+	}
+	return s.f.Close()
+}
+
+// optionalInt formats n, leaving the cell empty for zero.
+func optionalInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,193 @@
+package verifier
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/taramakage/gon-verifier/internal/taskpoint"
+)
+
+func TestSink(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewSink([]string{OutputXlsx, OutputJSON, OutputCSV}, dir, "taskpoint2.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []*Response{
+		{
+			TaskNo:      "A13",
+			Participant: "p1",
+			TeamName:    "team-fake",
+			Github:      "fake",
+			Point:       2,
+			Chain:       "i",
+			Txs: []CheckedTx{
+				{Chain: "i", Hash: "AA", Height: 10},
+				{Chain: "s", Hash: "BB", Height: 20},
+			},
+			Race: &taskpoint.RaceResult{Flow: "a01", TokenId: "nft1", StartHeight: 150, EndHeight: 180, Diff: 30, Owner: "iaa1owner"},
+		},
+		{
+			TaskNo:      "A14",
+			Participant: "p1",
+			TeamName:    "team-fake",
+			Github:      "fake",
+			Reason:      NewReason(ReasonIbcDestChanNotMatch).AtHop(2).Want("channel-5", "channel-3"),
+			Chain:       "i",
+			Txs:         []CheckedTx{{Chain: "i", Hash: "CC", Height: 30}},
+			Memo:        "discovery: row 3 missing",
+		},
+	}
+	for _, result := range results {
+		if err := sink.Write(result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("xlsx", func(t *testing.T) {
+		f, err := excelize.OpenFile(filepath.Join(dir, "taskpoint2.xlsx"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		rows, err := f.GetRows("result")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := [][]string{
+			taskpoint.Header,
+			{"A13", "team-fake", "2", "", "a01", "nft1", "150", "180", "30", "iaa1owner", "p1"},
+			{"A14", "team-fake", "0", "IBC: dest channel not match (hop 2; want channel-5; got channel-3)", "", "", "", "", "", "", "p1"},
+		}
+		if !reflect.DeepEqual(rows, want) {
+			t.Fatalf("want %v, got %v", want, rows)
+		}
+
+		for i, result := range results {
+			if participant := taskpoint.ReadParticipant(rows[i+1]); participant != result.Participant {
+				t.Errorf("%s: want participant %q, got %q", result.TaskNo, result.Participant, participant)
+			}
+			race, err := taskpoint.ReadRaceResult(rows[i+1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(race, result.Race) {
+				t.Errorf("%s: want race %+v, got %+v", result.TaskNo, result.Race, race)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		f, err := os.Open(filepath.Join(dir, "taskpoint2.jsonl"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		var records []Record
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var r Record
+			if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+				t.Fatal(err)
+			}
+			records = append(records, r)
+		}
+		want := []Record{
+			{
+				Task:        "A13",
+				Participant: "p1",
+				Team:        "team-fake",
+				Github:      "fake",
+				Point:       2,
+				Chain:       "i",
+				Txs:         []RecordTx{{Chain: "i", Hash: "AA", Height: 10}, {Chain: "s", Hash: "BB", Height: 20}},
+				Race:        results[0].Race,
+			},
+			{
+				Task:        "A14",
+				Participant: "p1",
+				Team:        "team-fake",
+				Github:      "fake",
+				ReasonCode:  ReasonIbcDestChanNotMatch,
+				Severity:    SeverityError,
+				Hop:         2,
+				Expected:    "channel-5",
+				Actual:      "channel-3",
+				Reason:      "IBC: dest channel not match (hop 2; want channel-5; got channel-3)",
+				Chain:       "i",
+				Txs:         []RecordTx{{Chain: "i", Hash: "CC", Height: 30}},
+				Memo:        "discovery: row 3 missing",
+			},
+		}
+		if !reflect.DeepEqual(records, want) {
+			t.Fatalf("want %+v, got %+v", want, records)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		f, err := os.Open(filepath.Join(dir, "taskpoint2.csv"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		rows, err := csv.NewReader(f).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := [][]string{
+			csvHeader,
+			{
+				"A13", "p1", "team-fake", "fake", "2",
+				"", "", "", "", "", "", "",
+				"i", "AA;BB", "10;20",
+				"a01", "nft1", "150", "180", "30", "iaa1owner",
+				"",
+			},
+			{
+				"A14", "p1", "team-fake", "fake", "0",
+				"ibc_dest_chan_not_match", "error", "2", "", "channel-5", "channel-3",
+				"IBC: dest channel not match (hop 2; want channel-5; got channel-3)",
+				"i", "CC", "30",
+				"", "", "", "", "", "",
+				"discovery: row 3 missing",
+			},
+		}
+		if !reflect.DeepEqual(rows, want) {
+			t.Fatalf("want %v, got %v", want, rows)
+		}
+	})
+}
+
+func TestReasonString(t *testing.T) {
+	for _, tc := range []struct {
+		reason   *Reason
+		text     string
+		severity Severity
+	}{
+		{nil, "", ""},
+		{NewReason(ReasonNftNotFound), "NFT: not found", SeverityError},
+		{NewReason(ReasonTxResultUnachievable).AtHop(2), "Tx: result is unachievable (hop 2)", SeverityError},
+		{restrictParamLen(nil, 1), "Params: number of rows is incorrect (want 1; got 0)", SeverityWarning},
+		{NewReason(ReasonParamsExampleRowLeft).AtRow(2), reasonMessages[ReasonParamsExampleRowLeft] + " (row 2)", SeverityWarning},
+		{NewReason("something_new"), "something_new", SeverityError},
+	} {
+		if got := tc.reason.String(); got != tc.text {
+			t.Errorf("want %q, got %q", tc.text, got)
+		}
+		if tc.reason != nil && tc.reason.Severity != tc.severity {
+			t.Errorf("%s: want severity %q, got %q", tc.reason.Code, tc.severity, tc.reason.Severity)
+		}
+	}
+}
@@ -2,36 +2,43 @@ package verifier
 
 import (
 	"errors"
-	"fmt"
-	"github.com/xuri/excelize/v2"
-	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 
 	"golang.org/x/exp/slog"
 
-	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/participant"
 )
 
 type (
 	Options struct {
-		TaskNos       []string
-		TaskPointFile string
-		Stage         int
+		Campaign  *campaign.Campaign
+		Stage     *campaign.Stage
+		Outputs   []string // formats of the task results, DefaultOutputs if empty
+		Discovery string   // mode of discovery of the evidence on chain, off if empty
+
+		// Participants identify the participant of the evidence, taken from its directory and Info sheet if nil.
+		Participants *participant.Registry
 	}
 
 	Task struct {
 		taskNo string
+		point  int32
+		rows   [][]string // evidence records laid out as the schema of the verifier
 		params any
+		reason *Reason // set when the rows can't be read, the task fails without verifying
 		vf     Verifier
 	}
 
 	TaskManager struct {
 		tasks    []Task
 		user     UserInfo
-		cr       *chain.Registry
 		vr       *Registry
+		passed   int   // number of tasks awarded a point, set once saved
+		point    int32 // total point, set once saved
+		err      error // error saving the result
 		wg       *sync.WaitGroup
 		baseDir  string
 		resultCh chan *Response
@@ -40,12 +47,11 @@ type (
 	}
 )
 
-func NewTaskManager(evidenceFile string, opts *Options) (*TaskManager, error) {
-	cr := chain.NewRegistry()
+// NewTaskManager loads the tasks of a stage from the evidence file, they are verified by the verifiers of vr.
+func NewTaskManager(evidenceFile string, vr *Registry, opts *Options) (*TaskManager, error) {
 	tm := &TaskManager{
 		wg:       &sync.WaitGroup{},
-		cr:       cr,
-		vr:       NewRegistry(cr, opts.Stage),
+		vr:       vr,
 		resultCh: make(chan *Response, 10),
 		stopCh:   make(chan int),
 		saveCh:   make(chan int),
@@ -58,10 +64,10 @@ func NewTaskManager(evidenceFile string, opts *Options) (*TaskManager, error) {
 }
 
 // Process concurrently verify tasks of one participant and write the result to xlsx file.
-func (tm *TaskManager) Process(opt *Options) {
+func (tm *TaskManager) Process(opt *Options) error {
 	if len(tm.tasks) == 0 {
 		slog.Info("no task process")
-		return
+		return nil
 	}
 	slog.Info("start to verify", "TeamName", tm.user.TeamName, " Github", tm.user.Github)
 	go tm.receive(opt)
@@ -70,53 +76,112 @@ func (tm *TaskManager) Process(opt *Options) {
 		go func(task Task) {
 			defer tm.wg.Done()
 			// slog.Info("verify rule", "TeamName", tm.user.TeamName, "TaskNo", task.taskNo)
-			task.vf.Do(*&Request{
-				TaskNo: task.taskNo,
-				User:   tm.user,
-				Params: task.params,
-			}, tm.resultCh)
+			tm.verify(task, opt.Discovery)
 		}(task)
 	}
 	tm.wg.Wait()
 	tm.stop()
 	<-tm.saveCh
-	return
+	return tm.err
 }
 
-func (tm *TaskManager) receive(opt *Options) {
-	f := excelize.NewFile()
+// verify runs the verifier of a task. With discovery the evidence is compared with the rows discovered on chain:
+// in strict mode the differences are noted in the memo of the result, in suggest mode the discovered rows are
+// verified instead when the evidence is malformed or its tx isn't found.
+func (tm *TaskManager) verify(task Task, discovery string) {
+	req := Request{
+		TaskNo: task.taskNo,
+		Point:  task.point,
+		User:   tm.user,
+		Params: task.params,
+	}
+	if task.reason != nil {
+		tm.resultCh <- &Response{TaskNo: task.taskNo, TeamName: tm.user.TeamName, Reason: task.reason}
+		return
+	}
+	d, ok := task.vf.(Discoverer)
+	if !ok || discovery == DiscoveryOff {
+		task.vf.Do(req, tm.resultCh)
+		return
+	}
+
+	res := make(chan *Response, 1)
+	task.vf.Do(req, res)
+	result := <-res
+	defer func() {
+		tm.resultCh <- result
+	}()
 
-	sheetName := "result"
-	index, err := f.NewSheet(sheetName)
+	rows, err := d.Discover(tm.user)
 	if err != nil {
-		slog.Error("NewSheet error", err)
+		slog.Error("discover evidence error", err, "TaskNo", task.taskNo)
+		return
+	}
+	if len(rows) == 0 {
+		result.Memo = "discovery: nothing found on chain"
+		return
+	}
+	diff := diffRows(task.rows, rows)
+	if len(diff) == 0 {
+		return
+	}
+	if discovery == DiscoveryStrict || !missingEvidence(result.Reason) {
+		result.Memo = "discovery: " + diff
 		return
 	}
 
-	rowIdx := 1
-	f.SetCellValue(sheetName, "A1", "TaskNo")
-	f.SetCellValue(sheetName, "B1", "TeamName")
-	f.SetCellValue(sheetName, "C1", "Point")
-	f.SetCellValue(sheetName, "D1", "Reason")
+	params, err := task.vf.BuildParams(task.vf.Schema().Records(rows))
+	if err != nil {
+		result.Memo = "discovery: " + diff
+		return
+	}
+	req.Params = params
+	task.vf.Do(req, res)
+	result = <-res
+	result.Memo = "discovery: suggested " + diff
+}
+
+func (tm *TaskManager) receive(opt *Options) {
+	outputs := opt.Outputs
+	if len(outputs) == 0 {
+		outputs = DefaultOutputs
+	}
+	sink, err := NewSink(outputs, tm.baseDir, opt.Stage.TaskPointFile)
+	if err != nil {
+		slog.Error("NewSink error", err)
+		tm.err = err
+	}
+
+	write := func(result *Response) {
+		result.Participant = tm.user.Id
+		result.Github = tm.user.Github
+		if result.Point > 0 {
+			tm.passed++
+			tm.point += result.Point
+		}
+		if sink == nil {
+			return
+		}
+		if err := sink.Write(result); err != nil && tm.err == nil {
+			slog.Error("write result error", err)
+			tm.err = err
+		}
+	}
 
 	for {
 		select {
 		case result := <-tm.resultCh:
-			f.SetCellValue(sheetName, fmt.Sprintf("A%d", rowIdx+1), result.TaskNo)
-			f.SetCellValue(sheetName, fmt.Sprintf("B%d", rowIdx+1), result.TeamName)
-			f.SetCellValue(sheetName, fmt.Sprintf("C%d", rowIdx+1), result.Point)
-			f.SetCellValue(sheetName, fmt.Sprintf("D%d", rowIdx+1), result.Reason)
-			rowIdx++
+			write(result)
 		case <-tm.stopCh:
-			f.SetActiveSheet(index)
-
-			fileName := filepath.Join(tm.baseDir, opt.TaskPointFile)
-			if err := f.SaveAs(fileName); err != nil {
-				slog.Error("Save file error", err)
+			// every task is done, but select may pick stop before the buffered results
+			for len(tm.resultCh) > 0 {
+				write(<-tm.resultCh)
 			}
-
-			if err := f.Close(); err != nil {
-				slog.Error("close file error", err)
+			if sink != nil {
+				if err := sink.Close(); err != nil && tm.err == nil {
+					slog.Error("Save file error", err)
+					tm.err = err
+				}
 			}
 			tm.saveCh <- 1
 			return
@@ -130,84 +195,90 @@ func (tm *TaskManager) stop() {
 }
 
 func (tm *TaskManager) loadEvidence(evidenceFile string, opts *Options) error {
-	evidence, err := excelize.OpenFile(evidenceFile)
+	evidence, err := OpenEvidence(evidenceFile)
 	if err != nil {
 		return err
 	}
+	defer evidence.Close()
 
 	tm.baseDir = filepath.Dir(evidenceFile)
-	if err := tm.loadUserInfo(evidence); err != nil {
+	user, err := ReadUserInfo(evidence, tm.baseDir, opts.Participants)
+	if err != nil {
 		return err
 	}
+	tm.user = user
 
 	return tm.buildTask(evidence, opts)
 }
 
-// loadUserInfo loads the user info from the evidence file.
-func (tm *TaskManager) loadUserInfo(evidence *excelize.File) error {
-	rows, err := evidence.GetRows("Info")
+// ReadUserInfo reads the participant of the evidence in dir. With a registry the participant is the one whose id is
+// the name of dir, or else the one registering an address of the Info sheet, and its registered team, github and
+// addresses prevail over the evidence unless blank. Without, the id and the github handle are the name of dir, the team name if dir is empty.
+func ReadUserInfo(evidence EvidenceSource, dir string, participants *participant.Registry) (UserInfo, error) {
+	user, err := evidence.UserInfo()
 	if err != nil {
-		return errors.New("info sheet not found")
+		return UserInfo{}, err
 	}
 
-	if len(rows) < 2 {
-		return errors.New("info sheet format error")
+	user.Github = user.TeamName
+	if len(dir) > 0 {
+		user.Github = filepath.Base(dir)
 	}
-
-	columns := rows[1]
-	github := columns[0]
-	if len(tm.baseDir) > 0 {
-		paths := strings.Split(tm.baseDir, string(os.PathSeparator))
-		github = paths[len(paths)-1]
+	user.Id = user.Github
+	if participants == nil {
+		return user, nil
 	}
 
-	tm.user = UserInfo{
-		TeamName: columns[0],
-		Github:   github,
-		Address: map[string]string{
-			chain.ChainIdAbbreviationIris:     columns[1],
-			chain.ChainIdAbbreviationStars:    columns[2],
-			chain.ChainIdAbbreviationJuno:     columns[3],
-			chain.ChainIdAbbreviationUptick:   columns[4],
-			chain.ChainIdAbbreviationOmniflix: columns[5],
-		},
+	p, err := participants.Resolve(user.Id, user.Address)
+	if err != nil {
+		return UserInfo{}, err
 	}
-	return nil
+	user.Id = p.Id
+	if len(p.Team) != 0 {
+		user.TeamName = p.Team
+	}
+	if len(p.Github) != 0 {
+		user.Github = p.Github
+	}
+	for abbr, addr := range p.Addresses {
+		user.Address[abbr] = addr
+	}
+	return user, nil
 }
 
-// buildTask builds the task list from the evidence file.
-func (tm *TaskManager) buildTask(evidence *excelize.File, opts *Options) error {
-	taskNos := evidence.GetSheetList()
-	if len(opts.TaskNos) != 0 {
-		taskNos = opts.TaskNos
-	}
-	for _, taskNo := range taskNos {
-		rowsCols, err := evidence.GetRows(taskNo)
-		if err != nil {
-			return err
+// buildTask builds the task list from the records of the evidence.
+func (tm *TaskManager) buildTask(evidence EvidenceSource, opts *Options) error {
+	for _, taskNo := range opts.Stage.TaskNos() {
+		vf := tm.vr.Get(taskNo)
+		task := Task{
+			taskNo: taskNo,
+			point:  opts.Campaign.Points[taskNo],
+			vf:     vf,
 		}
 
-		if len(rowsCols) == 0 {
-			return errors.New("evidence sheet is empty")
+		s := vf.Schema()
+		records, err := evidence.Records(taskNo, s)
+		var headerErr *HeaderError
+		var fieldErr *FieldError
+		switch {
+		case errors.As(err, &headerErr):
+			task.reason = NewReason(ReasonParamsHeaderMissing).Want(strings.Join(headerErr.Missing, " and "), strings.Join(headerErr.Header, " | "))
+			tm.tasks = append(tm.tasks, task)
+			continue
+		case errors.As(err, &fieldErr):
+			task.reason = NewReason(ReasonParamsFormatIncorrect).AtRow(fieldErr.Record).Want(strings.Join(fieldErr.Fields, " | "), fieldErr.Field)
+			tm.tasks = append(tm.tasks, task)
+			continue
+		case err != nil:
+			return err
 		}
 
-		vf := tm.vr.Get(taskNo)
-		params, err := vf.BuildParams(rowsCols[1:])
+		task.rows = s.RowsOf(records)
+		task.params, err = vf.BuildParams(records)
 		if err != nil {
 			return err
 		}
-
-		tm.tasks = append(tm.tasks, Task{
-			taskNo: taskNo,
-			params: params,
-			vf:     vf,
-		})
+		tm.tasks = append(tm.tasks, task)
 	}
 	return nil
 }
-
-func (tm *TaskManager) Close() {
-	for _, v := range tm.cr.GetChains() {
-		v.Close()
-	}
-}
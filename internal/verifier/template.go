@@ -0,0 +1,158 @@
+package verifier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+)
+
+// templateFill is the fill of the cells a participant fills in.
+const templateFill = "FFF2CC"
+
+// WriteTemplate writes a blank evidence workbook with a sheet per task of a stage, of every stage if stageName is empty.
+// Each sheet has the header and as many rows to fill as its verifier reads.
+func WriteTemplate(file string, c *campaign.Campaign, stageName string) error {
+	stages := c.Stages
+	if len(stageName) != 0 {
+		stage, ok := c.Stage(stageName)
+		if !ok {
+			return fmt.Errorf("unknown stage %q", stageName)
+		}
+		stages = []campaign.Stage{*stage}
+	}
+	schemas, err := taskSchemas(c, stages)
+	if err != nil {
+		return err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetName("Sheet1", InfoSheet); err != nil {
+		return err
+	}
+	fill, err := f.NewStyle(&excelize.Style{Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{templateFill}}})
+	if err != nil {
+		return err
+	}
+	if err := writeSheetTemplate(f, InfoSheet, infoSchema, fill); err != nil {
+		return err
+	}
+	for _, ts := range schemas {
+		if _, err := f.NewSheet(ts.taskNo); err != nil {
+			return err
+		}
+		if err := writeSheetTemplate(f, ts.taskNo, ts.schema, fill); err != nil {
+			return fmt.Errorf("task %s: %w", ts.taskNo, err)
+		}
+	}
+	return f.SaveAs(file)
+}
+
+// writeSheetTemplate writes the header of a sheet and validates the rows to fill by the kind of their columns.
+func writeSheetTemplate(f *excelize.File, sheet string, s Schema, fill int) error {
+	header := make([]string, 0, len(s.Columns))
+	for _, col := range s.Columns {
+		header = append(header, col.Name)
+	}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return err
+	}
+
+	rows := s.MinRows
+	if rows < 1 {
+		rows = 1
+	}
+	if err := f.SetCellStyle(sheet, "A2", cellName(len(s.Columns)-1, rows+1), fill); err != nil {
+		return err
+	}
+	for j, col := range s.Columns {
+		if err := f.SetColWidth(sheet, cellColumn(j), cellColumn(j), columnWidth(col)); err != nil {
+			return err
+		}
+		// the first column of a row tells what the row holds
+		if j == 0 && len(s.RowNames) != 0 {
+			for i := 0; i < rows && i < len(s.RowNames); i++ {
+				cell := cellName(j, i+2)
+				if err := addColumnValidation(f, sheet, cell, col, s.RowNames[i]); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := addColumnValidation(f, sheet, cellName(j, 2)+":"+cellName(j, rows+1), col, col.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumnValidation validates the cells of a column by its kind, with a prompt titled title.
+func addColumnValidation(f *excelize.File, sheet, sqref string, col Column, title string) error {
+	dv := excelize.NewDataValidation(col.Optional)
+	dv.SetSqref(sqref)
+	switch col.Kind {
+	case ColumnTxHash:
+		if err := dv.SetRange(64, 64, excelize.DataValidationTypeTextLength, excelize.DataValidationOperatorEqual); err != nil {
+			return err
+		}
+		dv.SetError(excelize.DataValidationErrorStyleStop, col.Name, "64 hex characters")
+	case ColumnIbcClassId:
+		if err := dv.SetRange(68, 68, excelize.DataValidationTypeTextLength, excelize.DataValidationOperatorEqual); err != nil {
+			return err
+		}
+		dv.SetError(excelize.DataValidationErrorStyleStop, col.Name, "ibc/ and 64 hex characters")
+	case ColumnChainId:
+		if err := dv.SetDropList(chainIdsOf(col.Chains)); err != nil {
+			return err
+		}
+		dv.SetError(excelize.DataValidationErrorStyleStop, col.Name, strings.Join(chainIdsOf(col.Chains), " or "))
+	}
+	dv.SetInput(title, columnHint(col))
+	return f.AddDataValidation(sheet, dv)
+}
+
+// columnHint describes what a cell of a column holds.
+func columnHint(col Column) string {
+	hint := ""
+	switch col.Kind {
+	case ColumnTxHash:
+		hint = "hash of the tx: 64 hex characters"
+	case ColumnClassId:
+		hint = "id of the denom"
+	case ColumnIbcClassId:
+		hint = "class of the nft: ibc/ and 64 hex characters"
+	case ColumnContract:
+		hint = "address of the cw721 contract on the chain of the chain id; wasm. prefix allowed"
+	case ColumnTokenId:
+		hint = "id of the nft"
+	case ColumnTeam:
+		hint = "name of the team"
+	case ColumnAddress:
+		hint = "bech32 address starting with " + chain.Bech32Prefixes[col.Chains[0]]
+	case ColumnChainId:
+		hint = "one of " + strings.Join(chainIdsOf(col.Chains), " or ")
+	}
+	if col.Optional {
+		hint += " (optional)"
+	}
+	return hint
+}
+
+// columnWidth is wide enough for the values of a column.
+func columnWidth(col Column) float64 {
+	switch col.Kind {
+	case ColumnTxHash, ColumnIbcClassId, ColumnContract, ColumnAddress:
+		return 72
+	}
+	return 16
+}
+
+// cellColumn returns the name of a zero based column, e.g. B.
+func cellColumn(col int) string {
+	name, _ := excelize.ColumnNumberToName(col + 1)
+	return name
+}
@@ -0,0 +1,83 @@
+package verifier
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+)
+
+func TestWriteTemplate(t *testing.T) {
+	c := &campaign.Campaign{
+		Name:  "test",
+		Races: map[string]campaign.Race{"r1": {Denom: "denom1", Owner: "iaa1owner"}},
+		Stages: []campaign.Stage{
+			{Name: "one", Tasks: []campaign.Task{{No: "A1", Verifier: campaign.VerifierA1}, {No: "A3", Verifier: campaign.VerifierA3}}},
+			{Name: "two", Tasks: []campaign.Task{{No: "B1", Verifier: campaign.VerifierFlow, Flow: "a01"}, {No: "D1", Verifier: campaign.VerifierRace, Race: "r1"}}},
+		},
+	}
+	file := filepath.Join(t.TempDir(), "evidence.xlsx")
+	if err := WriteTemplate(file, c, "nope"); err == nil {
+		t.Fatal("want an error for an unknown stage")
+	}
+	if err := WriteTemplate(file, c, "two"); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if want := []string{InfoSheet, "B1", "D1"}; !reflect.DeepEqual(f.GetSheetList(), want) {
+		t.Fatalf("sheets: want %v, got %v", want, f.GetSheetList())
+	}
+	rows, _ := f.GetRows(InfoSheet)
+	if want := []string{"team", "iris address", "stars address", "juno address", "uptick address", "omniflix address"}; !reflect.DeepEqual(rows[0], want) {
+		t.Errorf("info header: want %v, got %v", want, rows[0])
+	}
+	rows, _ = f.GetRows("B1")
+	if want := []string{"tx hash", "token id"}; !reflect.DeepEqual(rows[0], want) {
+		t.Errorf("flow header: want %v, got %v", want, rows[0])
+	}
+
+	dvs, err := f.GetDataValidations("B1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prompts []string
+	for _, dv := range dvs {
+		if dv.Sqref != "B2:B4" {
+			prompts = append(prompts, dv.Sqref+" "+*dv.PromptTitle)
+		}
+	}
+	if want := []string{"A2 hop 1: iris to stars", "A3 hop 2: stars to juno", "A4 hop 3: juno to iris"}; !reflect.DeepEqual(prompts, want) {
+		t.Errorf("a row per hop: want %v, got %v", want, prompts)
+	}
+
+	if err := WriteTemplate(file, c, "one"); err != nil {
+		t.Fatal(err)
+	}
+	f, err = excelize.OpenFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dvs, err = f.GetDataValidations("A3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dropList string
+	for _, dv := range dvs {
+		if dv.Sqref == "D2:D2" || dv.Sqref == "D2" {
+			dropList = dv.Formula1
+		}
+	}
+	if !strings.Contains(dropList, "elgafar-1,uni-6") {
+		t.Errorf("want a drop list of the chain ids of A3, got %q", dropList)
+	}
+}
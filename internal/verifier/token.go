@@ -0,0 +1,36 @@
+package verifier
+
+import (
+	"strings"
+
+	"github.com/taramakage/gon-verifier/internal/types"
+)
+
+// tokenReason checks a packet, which may carry several tokens, carries a token exactly once.
+func tokenReason(tx types.TxResultIbcNft, tokenId string) *Reason {
+	switch tx.CountToken(tokenId) {
+	case 0:
+		return NewReason(ReasonNftTokenIdNotMatch).Want(tokenId, strings.Join(tx.TokenIds(), ";"))
+	case 1:
+		return nil
+	}
+	return NewReason(ReasonNftTokenAmbiguous).Want(tokenId, strings.Join(tx.TokenIds(), ";"))
+}
+
+// carriedTokens narrows candidate tokens to the ones a packet carries, a candidate carried several times is ambiguous.
+func carriedTokens(tx types.TxResultIbcNft, candidates []string) ([]string, *Reason) {
+	carried := make([]string, 0, len(candidates))
+	for _, id := range candidates {
+		switch tx.CountToken(id) {
+		case 0:
+		case 1:
+			carried = append(carried, id)
+		default:
+			return nil, NewReason(ReasonNftTokenAmbiguous).Want(id, strings.Join(tx.TokenIds(), ";"))
+		}
+	}
+	if len(carried) == 0 {
+		return nil, NewReason(ReasonNftTokenIdNotMatch).Want(strings.Join(candidates, ";"), strings.Join(tx.TokenIds(), ";"))
+	}
+	return carried, nil
+}
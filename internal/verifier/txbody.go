@@ -0,0 +1,45 @@
+package verifier
+
+import (
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/types"
+)
+
+// checkTxBody checks the decoded tx carries a msg of one of the types signed by signer, other msgs may be signed by
+// others. signer must also pay the fee if feePayer.
+// A tx without body, as built by hand rather than queried, is only checked by its events.
+func checkTxBody(body *types.TxBody, signer string, feePayer bool, typeUrls ...string) *Reason {
+	if body == nil {
+		return nil
+	}
+	found := false
+	for _, msg := range body.Msgs {
+		for _, typeUrl := range typeUrls {
+			found = found || msg.TypeUrl == typeUrl
+		}
+	}
+	if !found {
+		return NewReason(ReasonTxMsgTypeNotMatch).Want(strings.Join(typeUrls, ";"), strings.Join(body.MsgTypes(), ";"))
+	}
+	if signers := body.Signers(typeUrls...); !slices.Contains(signers, signer) {
+		return NewReason(ReasonTxMsgSenderNotMatch).Want(signer, strings.Join(signers, ";"))
+	}
+	if feePayer && body.FeePayer != signer {
+		return NewReason(ReasonTxFeePayerNotMatch).Want(signer, body.FeePayer)
+	}
+	return nil
+}
+
+// ibcSendMsgType returns the type of the msg sending nfts over ics-721 from a chain,
+// wasm chains execute send_nft on the cw721 contract of the class.
+func ibcSendMsgType(abbr string) string {
+	switch abbr {
+	case chain.ChainIdAbbreviationStars, chain.ChainIdAbbreviationJuno:
+		return types.MsgTypeWasmExecute
+	}
+	return types.MsgTypeIbcNft
+}
@@ -1,28 +1,58 @@
 package verifier
 
+import "github.com/taramakage/gon-verifier/internal/taskpoint"
+
 type (
 	Request struct {
 		TaskNo string
+		Point  int32 // point awarded when the task is verified
 		User   UserInfo
 		Params any
 	}
 
 	Response struct {
-		TaskNo   string
-		TeamName string
-		Point    int32
-		Reason   string
-		Memo     string
+		TaskNo      string
+		Participant string // id of the participant
+		TeamName    string
+		Github      string
+		Point       int32
+		Reason      *Reason               // nil when the task passes without remark
+		Memo        string                // remark of the verification, e.g. the evidence discovered on chain
+		Chain       string                // abbreviation of the chain the evidence starts on
+		Txs         []CheckedTx           // txs checked in order
+		Race        *taskpoint.RaceResult // set when a race task is finished before the end height
+	}
+
+	// CheckedTx is a tx fetched and checked by a verifier.
+	CheckedTx struct {
+		Chain  string
+		Hash   string
+		Height int64
 	}
 
+	// Verifier verifies a task from the records of its evidence laid out as its schema.
 	Verifier interface {
 		Do(req Request, res chan<- *Response)
-		BuildParams(params [][]string) (any, error)
+		BuildParams(records []EvidenceRecord) (any, error)
+		Schema() Schema
 	}
 
 	UserInfo struct {
+		Id       string // id of the participant, the directory of the evidence without registry
 		TeamName string
 		Github   string
 		Address  map[string]string
 	}
 )
+
+// addTx records a tx checked on a chain, the first one sets the chain of the response.
+func (r *Response) addTx(chainAbbr, hash string, height int64) {
+	if len(r.Chain) == 0 {
+		r.Chain = chainAbbr
+	}
+	r.Txs = append(r.Txs, CheckedTx{
+		Chain:  chainAbbr,
+		Hash:   hash,
+		Height: height,
+	})
+}
@@ -1,10 +1,10 @@
 package verifier
 
-import "fmt"
+import "strconv"
 
-func restrictParamLen(rows [][]string, l int) string {
-	if len(rows) != l {
-		return fmt.Sprintf("params of task wanted %d row(s) but got %d row(s)", l, len(rows))
+func restrictParamLen(records []EvidenceRecord, l int) *Reason {
+	if len(records) != l {
+		return NewReason(ReasonParamsRowsInvalid).Want(strconv.Itoa(l), strconv.Itoa(len(records)))
 	}
-	return ""
+	return nil
 }
@@ -1,50 +1,150 @@
 package verifier
 
-type GonVerifier struct {
-	entrance string
-	options  *Options
-}
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"text/tabwriter"
+
+	"golang.org/x/exp/slog"
 
-const (
-	DenomIdGonIndivRace1 = "gonIndivRace1"
-	DenomIdGonIndivRace2 = "gonIndivRace2"
-	DenomIdGonTeamRace1  = "gonTeamRace1"
-	DenomIdGonTeamRace2  = "gonTeamRace2"
-	DenomIdGonTeamRace3  = "gonTeamRace3"
-	DenomIdGonQuiz       = "gonQuiz"
-
-	StartBlockHeightIndivRace1 = "473000"
-	StartBlockHeightIndivRace2 = "489000"
-	StartBlockHeightTeamRace   = "568000"
-	EndBlockHeightIndivRace    = "516223"
-	EndBlockHeightTeamRace     = "627379"
-	EndBlockHeightGame         = "671700"
-
-	LastOwner = "iaa1488wwr235vka7j722hzacpk0plxw33ksqyneuz"
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/participant"
 )
 
-func NewGonVerifier(entrance string, options *Options) *GonVerifier {
-	return &GonVerifier{
-		entrance: entrance,
-		options:  options,
+// DefaultWorkers is the number of participants verified at the same time in batch mode.
+const DefaultWorkers = 4
+
+type (
+	GonVerifier struct {
+		entrance string
+		stages   []stageVerifier
+	}
+
+	stageVerifier struct {
+		opts *Options
+		vr   *Registry
+	}
+
+	// Outcome is the result of verifying every stage of one participant.
+	Outcome struct {
+		Id       string // id of the participant
+		Github   string
+		Evidence string
+		Tasks    int   // number of tasks verified
+		Passed   int   // number of tasks awarded a point
+		Point    int32 // total point
+		Err      error // the participant is not verified up to the last stage
+	}
+)
+
+// NewGonVerifier builds the verifiers of every stage once, all of them share the chain registry.
+// The results are written in every format of outputs, the evidence is discovered on chain in the discovery mode.
+// The participants of the evidence are identified by the registry if not nil.
+func NewGonVerifier(entrance string, cr *chain.Registry, c *campaign.Campaign, participants *participant.Registry, outputs []string, discovery string) (*GonVerifier, error) {
+	if err := ValidateOutputs(outputs); err != nil {
+		return nil, err
+	}
+	if err := ValidateDiscovery(discovery); err != nil {
+		return nil, err
+	}
+	gv := &GonVerifier{entrance: entrance}
+	for i := range c.Stages {
+		opts := &Options{
+			Campaign:     c,
+			Stage:        &c.Stages[i],
+			Outputs:      outputs,
+			Discovery:    discovery,
+			Participants: participants,
+		}
+		vr, err := NewRegistry(cr, opts)
+		if err != nil {
+			return nil, fmt.Errorf("stage %s: %w", c.Stages[i].Name, err)
+		}
+		gv.stages = append(gv.stages, stageVerifier{opts, vr})
 	}
+	return gv, nil
 }
 
-func (gv *GonVerifier) Verify(file string) error {
-	tm, err := NewTaskManager(file, gv.options)
-	defer func() {
-		if tm != nil {
-			tm.Close()
+// Verify verifies every stage of one participant and writes the result of each stage next to the evidence file.
+func (gv *GonVerifier) Verify(file string) *Outcome {
+	outcome := &Outcome{Evidence: file}
+	for _, s := range gv.stages {
+		tm, err := NewTaskManager(file, s.vr, s.opts)
+		if err != nil {
+			outcome.Err = fmt.Errorf("stage %s: %w", s.opts.Stage.Name, err)
+			return outcome
+		}
+		outcome.Id = tm.user.Id
+		outcome.Github = tm.user.Github
+		if err := tm.Process(s.opts); err != nil {
+			outcome.Err = fmt.Errorf("stage %s: %w", s.opts.Stage.Name, err)
+			return outcome
 		}
-	}()
+		outcome.Tasks += len(tm.tasks)
+		outcome.Passed += tm.passed
+		outcome.Point += tm.point
+	}
+	return outcome
+}
 
+// VerifyAll verifies every evidence file under the entrance, at most workers participants at the same time.
+func (gv *GonVerifier) VerifyAll(workers int) ([]*Outcome, error) {
+	files, err := EvidenceFiles(gv.entrance)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	if workers < 1 {
+		workers = 1
+	}
+	slog.Info("start to verify all", "Participants", len(files), "Workers", workers)
 
-	if tm != nil {
-		tm.Process(gv.options)
+	outcomes := make([]*Outcome, len(files))
+	jobs := make(chan int)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				outcomes[idx] = gv.Verify(files[idx])
+				if outcomes[idx].Err != nil {
+					slog.Error("verify failed", outcomes[idx].Err, "Evidence", files[idx])
+				}
+			}
+		}()
+	}
+	for idx := range files {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	return outcomes, nil
+}
+
+// WriteSummary writes one line per participant followed by the totals, it returns the number of failed participants.
+func WriteSummary(w io.Writer, outcomes []*Outcome) int {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PARTICIPANT\tGITHUB\tTASKS\tPASSED\tPOINT\tRESULT")
+
+	failed := 0
+	for _, o := range outcomes {
+		id, github := o.Id, o.Github
+		if len(id) == 0 {
+			id = filepath.Base(filepath.Dir(o.Evidence))
+		}
+		result := "ok"
+		if o.Err != nil {
+			result = "failed: " + o.Err.Error()
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%s\n", id, github, o.Tasks, o.Passed, o.Point, result)
 	}
+	tw.Flush()
 
-	return nil
+	fmt.Fprintf(w, "\n%d participant(s) verified, %d succeeded, %d failed\n", len(outcomes), len(outcomes)-failed, failed)
+	return failed
 }
@@ -2,6 +2,7 @@ package verifier
 
 import (
 	"encoding/json"
+	"github.com/taramakage/gon-verifier/internal/campaign"
 	"github.com/taramakage/gon-verifier/internal/chain"
 	"github.com/taramakage/gon-verifier/internal/types"
 	"strings"
@@ -11,11 +12,13 @@ type A1Params struct {
 	ChainAbbreviation string
 	TxHash            string
 	ClassId           string
-	ParamErrorMsg     string
+	ParamErr          *Reason
 }
 
 type A1Verifier struct {
-	r *chain.Registry
+	r        *chain.Registry
+	w        *campaign.Window // window of the txs, none if nil
+	feePayer bool             // the user must pay the fees of the txs
 }
 
 type A1ClassData struct {
@@ -34,17 +37,17 @@ func (v A1Verifier) Do(req Request, res chan<- *Response) {
 	// params validation
 	params, ok := req.Params.(A1Params)
 	if !ok {
-		result.Reason = ReasonParamsFormatIncorrect
+		result.Reason = NewReason(ReasonParamsFormatIncorrect)
 		res <- result
 		return
 	}
-	if len(params.ParamErrorMsg) != 0 {
-		result.Reason = params.ParamErrorMsg
+	if params.ParamErr != nil {
+		result.Reason = params.ParamErr
 		res <- result
 		return
 	}
 	if len(params.TxHash) == 0 {
-		result.Reason = ReasonParamsChainIdEmpty
+		result.Reason = NewReason(ReasonParamsChainIdEmpty)
 		res <- result
 		return
 	}
@@ -52,24 +55,36 @@ func (v A1Verifier) Do(req Request, res chan<- *Response) {
 	c := v.r.GetChain(params.ChainAbbreviation)
 	txi, err := c.GetTx(params.TxHash, types.TxResultTypeIssueDenom)
 	if err != nil {
-		result.Reason = ReasonTxResultUnachievable
+		result.Reason = NewTxReason(err)
 		res <- result
 		return
 	}
 	tx, ok := txi.(types.TxResultIssueDenom)
 	if !ok {
-		result.Reason = ReasonTxResultUnexpected
+		result.Reason = NewReason(ReasonTxResultUnexpected)
 		res <- result
 		return
 	}
+	result.addTx(params.ChainAbbreviation, params.TxHash, tx.Height)
 	if tx.TxCode != 0 {
-		result.Reason = ReasonTxResultUnsuccessful
+		result.Reason = NewReason(ReasonTxResultUnsuccessful)
+		res <- result
+		return
+	}
+	if reason := checkWindow(v.r, v.w, params.ChainAbbreviation, tx.Height); reason != nil {
+		result.Reason = reason
+		res <- result
+		return
+	}
+	if reason := checkTxBody(tx.Body, req.User.Address[params.ChainAbbreviation], v.feePayer, types.MsgTypesIssueDenom...); reason != nil {
+		result.Reason = reason
 		res <- result
 		return
 	}
 
-	if req.User.Address[params.ChainAbbreviation] != tx.Sender {
-		result.Reason = ReasonTxMsgSenderNotMatch
+	// the signers of a decoded tx are checked with its body
+	if tx.Body == nil && req.User.Address[params.ChainAbbreviation] != tx.Sender {
+		result.Reason = NewReason(ReasonTxMsgSenderNotMatch).Want(req.User.Address[params.ChainAbbreviation], tx.Sender)
 		res <- result
 		return
 	}
@@ -77,19 +92,19 @@ func (v A1Verifier) Do(req Request, res chan<- *Response) {
 	// query class on chain
 	class, err := c.GetClass(params.ClassId)
 	if err != nil {
-		result.Reason = ReasonClassNotFound
+		result.Reason = NewReason(ReasonClassNotFound)
 		res <- result
 		return
 	}
 
 	if req.User.Address[params.ChainAbbreviation] != class.Creator {
-		result.Reason = ReasonClassCreatorNotMatch
+		result.Reason = NewReason(ReasonClassCreatorNotMatch).Want(req.User.Address[params.ChainAbbreviation], class.Creator)
 		res <- result
 		return
 	}
 
 	if len(class.Uri) == 0 {
-		result.Reason = ReasonClassUrIEmpty
+		result.Reason = NewReason(ReasonClassUrIEmpty)
 		res <- result
 		return
 	}
@@ -97,31 +112,47 @@ func (v A1Verifier) Do(req Request, res chan<- *Response) {
 	var classData A1ClassData
 	err = json.Unmarshal([]byte(class.Data), &classData)
 	if err != nil {
-		result.Reason = ReasonClassDataInvalid
+		result.Reason = NewReason(ReasonClassDataInvalid)
 		res <- result
 		return
 	}
 
-	result.Point = PointMap[req.TaskNo]
+	result.Point = req.Point
 	res <- result
 }
 
-func (v A1Verifier) BuildParams(rows [][]string) (any, error) {
-	errMsg := restrictParamLen(rows, 1)
-	if len(errMsg) != 0 {
+// Schema declares one row of the denom issued on iris.
+func (v A1Verifier) Schema() Schema {
+	return rowsSchema(1, txHashColumn, classIdColumn)
+}
+
+func (v A1Verifier) BuildParams(records []EvidenceRecord) (any, error) {
+	paramErr := restrictParamLen(records, 1)
+	if paramErr != nil {
 		return A1Params{
-			ParamErrorMsg: errMsg,
+			ParamErr: paramErr,
 		}, nil
 	}
 
-	rowFirst := rows[0]
+	record := records[0]
 	return A1Params{
 		ChainAbbreviation: chain.ChainIdAbbreviationIris,
-		TxHash:            rowFirst[0],
-		ClassId:           rowFirst[1],
+		TxHash:            record.TxHash,
+		ClassId:           record.ClassId,
 	}.Trim(), nil
 }
 
+// Discover proposes the latest denom issued on iris by the participant.
+func (v A1Verifier) Discover(user UserInfo) ([][]string, error) {
+	iris := chain.ChainIdAbbreviationIris
+	txs, err := sentTxs(v.r, iris, user.Address[iris], types.EventTypeIssueDenom, types.AttributeDenomId)
+	if err != nil || len(txs) == 0 {
+		return nil, err
+	}
+	tx := txs[len(txs)-1]
+	return [][]string{{tx.Result.Hash, tx.EventAttributeValueByKey(types.EventTypeIssueDenom, types.AttributeDenomId)}}, nil
+}
+
 func (p A1Params) Trim() A1Params {
 	res := p
 	res.TxHash = strings.TrimSpace(res.TxHash)
@@ -1,9 +1,10 @@
 package verifier
 
 import (
-	"fmt"
+	"github.com/taramakage/gon-verifier/internal/campaign"
 	"github.com/taramakage/gon-verifier/internal/chain"
 	"github.com/taramakage/gon-verifier/internal/types"
+	"strconv"
 	"strings"
 )
 
@@ -12,11 +13,13 @@ type A2Params struct {
 	TxHashes          []string
 	ClassIds          []string
 	TokenIds          []string
-	ParamErrorMsg     string
+	ParamErr          *Reason
 }
 
 type A2Verifier struct {
-	r *chain.Registry
+	r        *chain.Registry
+	w        *campaign.Window // window of the txs, none if nil
+	feePayer bool             // the user must pay the fees of the txs
 }
 
 func (v A2Verifier) Do(req Request, res chan<- *Response) {
@@ -28,17 +31,17 @@ func (v A2Verifier) Do(req Request, res chan<- *Response) {
 	// params validation
 	params, ok := req.Params.(A2Params)
 	if !ok {
-		result.Reason = ReasonParamsFormatIncorrect
+		result.Reason = NewReason(ReasonParamsFormatIncorrect)
 		res <- result
 		return
 	}
-	if len(params.ParamErrorMsg) != 0 {
-		result.Reason = params.ParamErrorMsg
+	if params.ParamErr != nil {
+		result.Reason = params.ParamErr
 		res <- result
 		return
 	}
 	if len(params.ChainAbbreviation) == 0 {
-		result.Reason = ReasonParamsChainIdEmpty
+		result.Reason = NewReason(ReasonParamsChainIdEmpty)
 		res <- result
 		return
 	}
@@ -48,18 +51,29 @@ func (v A2Verifier) Do(req Request, res chan<- *Response) {
 	for i := range params.TxHashes {
 		txi, err := c.GetTx(params.TxHashes[i], types.TxResultTypeMintNft)
 		if err != nil {
-			result.Reason = ReasonTxResultUnachievable
+			result.Reason = NewTxReason(err).AtRow(i + 2)
 			res <- result
 			return
 		}
 		tx, ok := txi.(types.TxResultMintNft)
 		if !ok {
-			result.Reason = ReasonTxResultUnexpected
+			result.Reason = NewReason(ReasonTxResultUnexpected).AtRow(i + 2)
 			res <- result
 			return
 		}
+		result.addTx(params.ChainAbbreviation, params.TxHashes[i], tx.Height)
 		if tx.TxCode != 0 {
-			result.Reason = ReasonTxResultUnsuccessful
+			result.Reason = NewReason(ReasonTxResultUnsuccessful).AtRow(i + 2)
+			res <- result
+			return
+		}
+		if reason := checkWindow(v.r, v.w, params.ChainAbbreviation, tx.Height); reason != nil {
+			result.Reason = reason.AtRow(i + 2)
+			res <- result
+			return
+		}
+		if reason := checkTxBody(tx.Body, req.User.Address[params.ChainAbbreviation], v.feePayer, types.MsgTypesMintNft...); reason != nil {
+			result.Reason = reason.AtRow(i + 2)
 			res <- result
 			return
 		}
@@ -67,24 +81,25 @@ func (v A2Verifier) Do(req Request, res chan<- *Response) {
 		// class owner must be the same as register address on iris
 		class, err := c.GetClass(params.ClassIds[i])
 		if err != nil {
-			result.Reason = ReasonClassNotFound
+			result.Reason = NewReason(ReasonClassNotFound).AtRow(i + 2)
 			res <- result
 			return
 		}
 		if req.User.Address[params.ChainAbbreviation] != class.Creator {
-			result.Reason = ReasonClassCreatorNotMatch
+			result.Reason = NewReason(ReasonClassCreatorNotMatch).AtRow(i+2).Want(req.User.Address[params.ChainAbbreviation], class.Creator)
 			res <- result
 			return
 		}
 
-		if req.User.Address[params.ChainAbbreviation] != tx.Sender {
-			result.Reason = ReasonTxMsgSenderNotMatch
+		// the signers of a decoded tx are checked with its body
+		if tx.Body == nil && req.User.Address[params.ChainAbbreviation] != tx.Sender {
+			result.Reason = NewReason(ReasonTxMsgSenderNotMatch).AtRow(i+2).Want(req.User.Address[params.ChainAbbreviation], tx.Sender)
 			res <- result
 			return
 		}
 
 		if req.User.Address[params.ChainAbbreviation] != tx.Recipient {
-			result.Reason = ReasonNftOwnerNotMatch
+			result.Reason = NewReason(ReasonNftOwnerNotMatch).AtRow(i+2).Want(req.User.Address[params.ChainAbbreviation], tx.Recipient)
 			res <- result
 			return
 		}
@@ -92,36 +107,41 @@ func (v A2Verifier) Do(req Request, res chan<- *Response) {
 		// query nft on chain
 		nft, err := c.GetNFT(params.ClassIds[i], params.TokenIds[i])
 		if err != nil {
-			result.Reason = ReasonNftNotFound
+			result.Reason = NewReason(ReasonNftNotFound).AtRow(i + 2)
 			res <- result
 			return
 		}
 
 		if len(nft.URI) == 0 {
-			result.Reason = ReasonNftUriEmpty
+			result.Reason = NewReason(ReasonNftUriEmpty).AtRow(i + 2)
 			res <- result
 			return
 		}
 
 		if len(nft.Data) == 0 {
-			result.Reason = ReasonNftDataEmpty
+			result.Reason = NewReason(ReasonNftDataEmpty).AtRow(i + 2)
 			res <- result
 			return
 		}
 	}
 
-	result.Point = PointMap[req.TaskNo]
+	result.Point = req.Point
 	res <- result
 }
 
-func (v A2Verifier) BuildParams(rows [][]string) (any, error) {
-	if len(rows) < 2 {
+// Schema declares at least two rows of the nfts minted on iris.
+func (v A2Verifier) Schema() Schema {
+	return Schema{Columns: []Column{txHashColumn, classIdColumn, tokenIdColumn}, MinRows: 2, RowNames: []string{"first nft", "second nft"}}
+}
+
+func (v A2Verifier) BuildParams(records []EvidenceRecord) (any, error) {
+	if len(records) < 2 {
 		return A2Params{
-			ParamErrorMsg: fmt.Sprintf("parmas of task wanted at least 2 row(s) , but got %d row(s)", len(rows)),
+			ParamErr: NewReason(ReasonParamsRowsInvalid).Want(">= 2", strconv.Itoa(len(records))),
 		}, nil
-	} else if strings.HasPrefix(strings.TrimSpace(rows[0][0]), "tx") {
+	} else if strings.HasPrefix(strings.TrimSpace(records[0].TxHash), "tx") {
 		return A2Params{
-			ParamErrorMsg: "row 2 should be replaced with evidence rather than left there",
+			ParamErr: NewReason(ReasonParamsExampleRowLeft).AtRow(2),
 		}, nil
 	}
 
@@ -133,19 +153,41 @@ func (v A2Verifier) BuildParams(rows [][]string) (any, error) {
 	}
 
 	// NOTE: only the first two rows are read
-	for i := range rows {
+	for i := range records {
 		if i == 2 {
 			break
 		}
 
-		params.TxHashes = append(params.TxHashes, rows[i][0])
-		params.ClassIds = append(params.ClassIds, rows[i][1])
-		params.TokenIds = append(params.TokenIds, rows[i][2])
+		params.TxHashes = append(params.TxHashes, records[i].TxHash)
+		params.ClassIds = append(params.ClassIds, records[i].ClassId)
+		params.TokenIds = append(params.TokenIds, records[i].TokenId)
 	}
 
 	return params.Trim(), nil
 }
 
+// Discover proposes the latest two nfts minted on iris by the participant, oldest first.
+func (v A2Verifier) Discover(user UserInfo) ([][]string, error) {
+	iris := chain.ChainIdAbbreviationIris
+	txs, err := sentTxs(v.r, iris, user.Address[iris], types.EventTypeNftMint, types.AttributeKeyTokenId)
+	if err != nil {
+		return nil, err
+	}
+	if len(txs) > 2 {
+		txs = txs[len(txs)-2:]
+	}
+
+	rows := make([][]string, 0, len(txs))
+	for _, tx := range txs {
+		rows = append(rows, []string{
+			tx.Result.Hash,
+			tx.EventAttributeValueByKey(types.EventTypeNftMint, types.AttributeDenomId),
+			tx.EventAttributeValueByKey(types.EventTypeNftMint, types.AttributeKeyTokenId),
+		})
+	}
+	return rows, nil
+}
+
 func (p A2Params) Trim() A2Params {
 	res := p
 	for i := range res.TxHashes {
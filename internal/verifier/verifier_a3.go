@@ -1,6 +1,7 @@
 package verifier
 
 import (
+	"github.com/taramakage/gon-verifier/internal/campaign"
 	"github.com/taramakage/gon-verifier/internal/chain"
 	"github.com/taramakage/gon-verifier/internal/types"
 	"strings"
@@ -12,11 +13,13 @@ type A3Params struct {
 	ClassId           string // Wasm Contract Addr
 	TokenId           string
 	ChainId           string // Dest Chain Id
-	ParamErrorMsg     string
+	ParamErr          *Reason
 }
 
 type A3Verifier struct {
-	r *chain.Registry
+	r        *chain.Registry
+	w        *campaign.Window // window of the txs, none if nil
+	feePayer bool             // the user must pay the fees of the txs
 }
 
 func (v A3Verifier) Do(req Request, res chan<- *Response) {
@@ -27,22 +30,22 @@ func (v A3Verifier) Do(req Request, res chan<- *Response) {
 
 	params, ok := req.Params.(A3Params)
 	if !ok {
-		result.Reason = ReasonParamsFormatIncorrect
+		result.Reason = NewReason(ReasonParamsFormatIncorrect)
 		res <- result
 		return
 	}
-	if len(params.ParamErrorMsg) != 0 {
-		result.Reason = params.ParamErrorMsg
+	if params.ParamErr != nil {
+		result.Reason = params.ParamErr
 		res <- result
 		return
 	}
 	if len(params.ChainAbbreviation) == 0 {
-		result.Reason = ReasonParamsChainIdEmpty
+		result.Reason = NewReason(ReasonParamsChainIdEmpty)
 		res <- result
 		return
 	}
 	if len(params.ChainAbbreviation) == 0 {
-		result.Reason = ReasonParamsChainIdError
+		result.Reason = NewReason(ReasonParamsChainIdError)
 		res <- result
 		return
 	}
@@ -50,70 +53,135 @@ func (v A3Verifier) Do(req Request, res chan<- *Response) {
 	iris := v.r.GetChain(chain.ChainIdAbbreviationIris)
 	txi, err := iris.GetTx(params.TxHash, types.TxResultTypeIbcNft)
 	if err != nil {
-		result.Reason = ReasonTxResultUnachievable
+		result.Reason = NewTxReason(err)
 		res <- result
 		return
 	}
 	tx, ok := txi.(types.TxResultIbcNft)
 	if !ok {
-		result.Reason = ReasonTxResultUnexpected
+		result.Reason = NewReason(ReasonTxResultUnexpected)
 		res <- result
 		return
 	}
+	result.addTx(chain.ChainIdAbbreviationIris, params.TxHash, tx.Height)
 	if tx.TxCode != 0 {
-		result.Reason = ReasonTxResultUnsuccessful
+		result.Reason = NewReason(ReasonTxResultUnsuccessful)
+		res <- result
+		return
+	}
+	if reason := checkWindow(v.r, v.w, chain.ChainIdAbbreviationIris, tx.Height); reason != nil {
+		result.Reason = reason
+		res <- result
+		return
+	}
+	if reason := checkTxBody(tx.Body, req.User.Address[chain.ChainIdAbbreviationIris], v.feePayer, types.MsgTypeIbcNft); reason != nil {
+		result.Reason = reason
 		res <- result
 		return
 	}
 
-	// query cw-721 addr on chain
+	// the cw-721 addr must be the one instantiated by the ics721 bridge for the class
 	destChain := v.r.GetChain(params.ChainAbbreviation)
+	if bridge, ok := destChain.(chain.ICS721); ok {
+		contract, err := bridge.NftContract(tx.DestClassTrace())
+		if err != nil {
+			result.Reason = NewReason(ReasonClassNotFound)
+			res <- result
+			return
+		}
+		if contract != params.ClassId {
+			result.Reason = NewReason(ReasonIbcClassNotMatch).Want(contract, params.ClassId)
+			res <- result
+			return
+		}
+	}
+
+	// query cw-721 addr on chain
 	if ok := destChain.HasClass(params.ClassId); !ok {
-		result.Reason = ReasonClassNotFound
+		result.Reason = NewReason(ReasonClassNotFound)
 		res <- result
 		return
 	}
 
-	if req.User.Address[chain.ChainIdAbbreviationIris] != tx.Sender {
-		result.Reason = ReasonTxMsgSenderNotMatch
+	// the signers of a decoded tx are checked with its body
+	if tx.Body == nil && req.User.Address[chain.ChainIdAbbreviationIris] != tx.Sender {
+		result.Reason = NewReason(ReasonTxMsgSenderNotMatch).Want(req.User.Address[chain.ChainIdAbbreviationIris], tx.Sender)
 		res <- result
 		return
 	}
 
 	if req.User.Address[params.ChainAbbreviation] != tx.Receiver {
-		result.Reason = ReasonNftRecipientNotMatch
+		result.Reason = NewReason(ReasonNftRecipientNotMatch).Want(req.User.Address[params.ChainAbbreviation], tx.Receiver)
 		res <- result
 		return
 	}
 
-	if tx.TokenId != params.TokenId {
-		result.Reason = ReasonNftTokenIdNotMatch
+	if reason := tokenReason(tx, params.TokenId); reason != nil {
+		result.Reason = reason
 		res <- result
 		return
 	}
 
-	result.Point = PointMap[req.TaskNo]
+	// query cw-721 token owner on chain
+	nft, err := destChain.GetNFT(params.ClassId, params.TokenId)
+	if err != nil {
+		result.Reason = NewReason(ReasonNftNotFound)
+		res <- result
+		return
+	}
+	if req.User.Address[params.ChainAbbreviation] != nft.Owner {
+		result.Reason = NewReason(ReasonNftOwnerNotMatch).Want(req.User.Address[params.ChainAbbreviation], nft.Owner)
+		res <- result
+		return
+	}
+
+	result.Point = req.Point
 	res <- result
 }
 
-func (v A3Verifier) BuildParams(rows [][]string) (any, error) {
-	errMsg := restrictParamLen(rows, 1)
-	if len(errMsg) != 0 {
+// Schema declares one row of the nft sent to stars or juno with its cw721 contract on dest.
+func (v A3Verifier) Schema() Schema {
+	return rowsSchema(1, txHashColumn, contractColumn, tokenIdColumn, chainIdColumn(chain.ChainIdAbbreviationStars, chain.ChainIdAbbreviationJuno))
+}
+
+func (v A3Verifier) BuildParams(records []EvidenceRecord) (any, error) {
+	paramErr := restrictParamLen(records, 1)
+	if paramErr != nil {
 		return A3Params{
-			ParamErrorMsg: errMsg,
+			ParamErr: paramErr,
 		}, nil
 	}
 
-	param := rows[0]
+	record := records[0]
 	return A3Params{
 		ChainAbbreviation: "",
-		TxHash:            param[0],
-		ClassId:           param[1],
-		TokenId:           param[2],
-		ChainId:           param[3],
+		TxHash:            record.TxHash,
+		ClassId:           record.Contract,
+		TokenId:           record.TokenId,
+		ChainId:           record.ChainId,
 	}.Trim(), nil
 }
 
+// Discover proposes the latest nft sent from iris to stars or juno by the participant with its cw721 contract on dest.
+func (v A3Verifier) Discover(user UserInfo) ([][]string, error) {
+	sent, err := sentNfts(v.r, user, chain.ChainIdAbbreviationIris, chain.ChainIdAbbreviationStars, chain.ChainIdAbbreviationJuno)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range sent {
+		dest, ok := v.r.GetChain(tx.dest).(chain.ICS721)
+		if !ok {
+			continue
+		}
+		contract, err := dest.NftContract(tx.DestClassTrace())
+		if err != nil {
+			continue
+		}
+		return [][]string{{tx.hash, contract, tx.TokenId, chainIdValues[tx.dest]}}, nil
+	}
+	return nil, nil
+}
+
 func (p A3Params) Trim() A3Params {
 	res := p
 	res.TxHash = strings.TrimSpace(res.TxHash)
@@ -1,6 +1,7 @@
 package verifier
 
 import (
+	"github.com/taramakage/gon-verifier/internal/campaign"
 	"github.com/taramakage/gon-verifier/internal/chain"
 	"github.com/taramakage/gon-verifier/internal/types"
 	"strings"
@@ -12,11 +13,13 @@ type A4Params struct {
 	ClassId           string // IBC Class
 	TokenId           string
 	ChainId           string // Dest Chain Id
-	ParamErrorMsg     string
+	ParamErr          *Reason
 }
 
 type A4Verifier struct {
-	r *chain.Registry
+	r        *chain.Registry
+	w        *campaign.Window // window of the txs, none if nil
+	feePayer bool             // the user must pay the fees of the txs
 }
 
 func (v A4Verifier) Do(req Request, res chan<- *Response) {
@@ -27,22 +30,22 @@ func (v A4Verifier) Do(req Request, res chan<- *Response) {
 
 	params, ok := req.Params.(A4Params)
 	if !ok {
-		result.Reason = ReasonParamsFormatIncorrect
+		result.Reason = NewReason(ReasonParamsFormatIncorrect)
 		res <- result
 		return
 	}
-	if len(params.ParamErrorMsg) != 0 {
-		result.Reason = params.ParamErrorMsg
+	if params.ParamErr != nil {
+		result.Reason = params.ParamErr
 		res <- result
 		return
 	}
 	if len(params.ChainAbbreviation) == 0 {
-		result.Reason = ReasonParamsChainIdEmpty
+		result.Reason = NewReason(ReasonParamsChainIdEmpty)
 		res <- result
 		return
 	}
 	if len(params.ChainAbbreviation) == 0 {
-		result.Reason = ReasonParamsChainIdError
+		result.Reason = NewReason(ReasonParamsChainIdError)
 		res <- result
 		return
 	}
@@ -50,18 +53,29 @@ func (v A4Verifier) Do(req Request, res chan<- *Response) {
 	iris := v.r.GetChain(chain.ChainIdAbbreviationIris)
 	txi, err := iris.GetTx(params.TxHash, types.TxResultTypeIbcNft)
 	if err != nil {
-		result.Reason = ReasonTxResultUnachievable
+		result.Reason = NewTxReason(err)
 		res <- result
 		return
 	}
 	tx, ok := txi.(types.TxResultIbcNft)
 	if !ok {
-		result.Reason = ReasonTxResultUnexpected
+		result.Reason = NewReason(ReasonTxResultUnexpected)
 		res <- result
 		return
 	}
+	result.addTx(chain.ChainIdAbbreviationIris, params.TxHash, tx.Height)
 	if tx.TxCode != 0 {
-		result.Reason = ReasonTxResultUnsuccessful
+		result.Reason = NewReason(ReasonTxResultUnsuccessful)
+		res <- result
+		return
+	}
+	if reason := checkWindow(v.r, v.w, chain.ChainIdAbbreviationIris, tx.Height); reason != nil {
+		result.Reason = reason
+		res <- result
+		return
+	}
+	if reason := checkTxBody(tx.Body, req.User.Address[chain.ChainIdAbbreviationIris], v.feePayer, types.MsgTypeIbcNft); reason != nil {
+		result.Reason = reason
 		res <- result
 		return
 	}
@@ -69,50 +83,66 @@ func (v A4Verifier) Do(req Request, res chan<- *Response) {
 	// query ibc class on chain
 	destChain := v.r.GetChain(params.ChainAbbreviation)
 	if ok := destChain.HasClass(params.ClassId); !ok {
-		result.Reason = ReasonClassNotFound
+		result.Reason = NewReason(ReasonClassNotFound)
 		res <- result
 		return
 	}
 
-	if req.User.Address[chain.ChainIdAbbreviationIris] != tx.Sender {
-		result.Reason = ReasonTxMsgSenderNotMatch
+	// the signers of a decoded tx are checked with its body
+	if tx.Body == nil && req.User.Address[chain.ChainIdAbbreviationIris] != tx.Sender {
+		result.Reason = NewReason(ReasonTxMsgSenderNotMatch).Want(req.User.Address[chain.ChainIdAbbreviationIris], tx.Sender)
 		res <- result
 		return
 	}
 	if req.User.Address[params.ChainAbbreviation] != tx.Receiver {
-		result.Reason = ReasonNftRecipientNotMatch
+		result.Reason = NewReason(ReasonNftRecipientNotMatch).Want(req.User.Address[params.ChainAbbreviation], tx.Receiver)
 		res <- result
 		return
 	}
 
-	if tx.TokenId != params.TokenId {
-		result.Reason = ReasonNftTokenIdNotMatch
+	if reason := tokenReason(tx, params.TokenId); reason != nil {
+		result.Reason = reason
 		res <- result
 		return
 	}
 
-	result.Point = PointMap[req.TaskNo]
+	result.Point = req.Point
 	res <- result
 }
 
-func (v A4Verifier) BuildParams(rows [][]string) (any, error) {
-	errMsg := restrictParamLen(rows, 1)
-	if len(errMsg) != 0 {
+// Schema declares one row of the nft sent to uptick or omniflix with its ibc class on dest.
+func (v A4Verifier) Schema() Schema {
+	return rowsSchema(1, txHashColumn, ibcClassIdColumn, tokenIdColumn, chainIdColumn(chain.ChainIdAbbreviationUptick, chain.ChainIdAbbreviationOmniflix))
+}
+
+func (v A4Verifier) BuildParams(records []EvidenceRecord) (any, error) {
+	paramErr := restrictParamLen(records, 1)
+	if paramErr != nil {
 		return A4Params{
-			ParamErrorMsg: errMsg,
+			ParamErr: paramErr,
 		}, nil
 	}
 
-	param := rows[0]
+	record := records[0]
 	return A4Params{
 		ChainAbbreviation: "",
-		TxHash:            param[0],
-		ClassId:           param[1],
-		TokenId:           param[2],
-		ChainId:           param[3],
+		TxHash:            record.TxHash,
+		ClassId:           record.IbcClassId,
+		TokenId:           record.TokenId,
+		ChainId:           record.ChainId,
 	}.Trim(), nil
 }
 
+// Discover proposes the latest nft sent from iris to uptick or omniflix by the participant with its ibc class on dest.
+func (v A4Verifier) Discover(user UserInfo) ([][]string, error) {
+	sent, err := sentNfts(v.r, user, chain.ChainIdAbbreviationIris, chain.ChainIdAbbreviationUptick, chain.ChainIdAbbreviationOmniflix)
+	if err != nil || len(sent) == 0 {
+		return nil, err
+	}
+	tx := sent[0]
+	return [][]string{{tx.hash, chain.IbcClassId(tx.DestClassTrace()), tx.TokenId, chainIdValues[tx.dest]}}, nil
+}
+
 func (p A4Params) Trim() A4Params {
 	res := p
 	res.TxHash = strings.TrimSpace(res.TxHash)
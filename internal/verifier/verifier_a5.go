@@ -1,6 +1,7 @@
 package verifier
 
 import (
+	"github.com/taramakage/gon-verifier/internal/campaign"
 	"github.com/taramakage/gon-verifier/internal/chain"
 	"github.com/taramakage/gon-verifier/internal/types"
 	"strings"
@@ -12,11 +13,13 @@ type A5Params struct {
 	ClassId           string // Wasm Contract Addr
 	TokenId           string
 	ChainId           string // Dest Chain ID
-	ParamErrorMsg     string
+	ParamErr          *Reason
 }
 
 type A5Verifier struct {
-	r *chain.Registry
+	r        *chain.Registry
+	w        *campaign.Window // window of the txs, none if nil
+	feePayer bool             // the user must pay the fees of the txs
 }
 
 func (v A5Verifier) Do(req Request, res chan<- *Response) {
@@ -27,22 +30,22 @@ func (v A5Verifier) Do(req Request, res chan<- *Response) {
 
 	params, ok := req.Params.(A5Params)
 	if !ok {
-		result.Reason = ReasonParamsFormatIncorrect
+		result.Reason = NewReason(ReasonParamsFormatIncorrect)
 		res <- result
 		return
 	}
-	if len(params.ParamErrorMsg) != 0 {
-		result.Reason = params.ParamErrorMsg
+	if params.ParamErr != nil {
+		result.Reason = params.ParamErr
 		res <- result
 		return
 	}
 	if len(params.TxHash) == 0 {
-		result.Reason = ReasonParamsChainIdEmpty
+		result.Reason = NewReason(ReasonParamsChainIdEmpty)
 		res <- result
 		return
 	}
 	if len(params.ChainAbbreviation) == 0 {
-		result.Reason = ReasonParamsChainIdError
+		result.Reason = NewReason(ReasonParamsChainIdError)
 		res <- result
 		return
 	}
@@ -50,36 +53,48 @@ func (v A5Verifier) Do(req Request, res chan<- *Response) {
 	srcChain := v.r.GetChain(params.ChainAbbreviation)
 	txi, err := srcChain.GetTx(params.TxHash, types.TxResultTypeIbcNft)
 	if err != nil {
-		result.Reason = ReasonTxResultUnachievable
+		result.Reason = NewTxReason(err)
 		res <- result
 		return
 	}
 	tx, ok := txi.(types.TxResultIbcNft)
 	if !ok {
-		result.Reason = ReasonTxResultUnexpected
+		result.Reason = NewReason(ReasonTxResultUnexpected)
 		res <- result
 		return
 	}
+	result.addTx(params.ChainAbbreviation, params.TxHash, tx.Height)
 	if tx.TxCode != 0 {
-		result.Reason = ReasonTxResultUnsuccessful
+		result.Reason = NewReason(ReasonTxResultUnsuccessful)
+		res <- result
+		return
+	}
+	if reason := checkWindow(v.r, v.w, params.ChainAbbreviation, tx.Height); reason != nil {
+		result.Reason = reason
+		res <- result
+		return
+	}
+	if reason := checkTxBody(tx.Body, req.User.Address[params.ChainAbbreviation], v.feePayer, ibcSendMsgType(params.ChainAbbreviation)); reason != nil {
+		result.Reason = reason
 		res <- result
 		return
 	}
 
 	// query cw-721 addr on chain
 	if !srcChain.HasClass(params.ClassId) {
-		result.Reason = ReasonClassNotFound
+		result.Reason = NewReason(ReasonClassNotFound)
 		res <- result
 		return
 	}
 
-	if req.User.Address[params.ChainAbbreviation] != tx.Sender {
-		result.Reason = ReasonTxMsgSenderNotMatch
+	// the signers of a decoded tx are checked with its body
+	if tx.Body == nil && req.User.Address[params.ChainAbbreviation] != tx.Sender {
+		result.Reason = NewReason(ReasonTxMsgSenderNotMatch).Want(req.User.Address[params.ChainAbbreviation], tx.Sender)
 		res <- result
 		return
 	}
 	if req.User.Address[chain.ChainIdAbbreviationIris] != tx.Receiver {
-		result.Reason = ReasonNftRecipientNotMatch
+		result.Reason = NewReason(ReasonNftRecipientNotMatch).Want(req.User.Address[chain.ChainIdAbbreviationIris], tx.Receiver)
 		res <- result
 		return
 	}
@@ -87,33 +102,61 @@ func (v A5Verifier) Do(req Request, res chan<- *Response) {
 	iris := v.r.GetChain(chain.ChainIdAbbreviationIris)
 	originalClassId := tx.OriginalClass()
 	if !iris.HasNFT(originalClassId, params.TokenId) {
-		result.Reason = ReasonNftNotFound
+		result.Reason = NewReason(ReasonNftNotFound)
 		res <- result
 		return
 	}
 
-	result.Point = PointMap[req.TaskNo]
+	result.Point = req.Point
 	res <- result
 }
 
-func (v A5Verifier) BuildParams(rows [][]string) (any, error) {
-	errMsg := restrictParamLen(rows, 1)
-	if len(errMsg) != 0 {
+// Schema declares one row of the nft sent back from stars or juno with its cw721 contract on the source.
+func (v A5Verifier) Schema() Schema {
+	return rowsSchema(1, txHashColumn, contractColumn, tokenIdColumn, chainIdColumn(chain.ChainIdAbbreviationStars, chain.ChainIdAbbreviationJuno))
+}
+
+func (v A5Verifier) BuildParams(records []EvidenceRecord) (any, error) {
+	paramErr := restrictParamLen(records, 1)
+	if paramErr != nil {
 		return A5Params{
-			ParamErrorMsg: errMsg,
+			ParamErr: paramErr,
 		}, nil
 	}
 
-	param := rows[0]
+	record := records[0]
 	return A5Params{
 		ChainAbbreviation: "",
-		TxHash:            param[0],
-		ClassId:           param[1], // Wasm Contract Addr
-		TokenId:           param[2],
-		ChainId:           param[3],
+		TxHash:            record.TxHash,
+		ClassId:           record.Contract, // Wasm Contract Addr
+		TokenId:           record.TokenId,
+		ChainId:           record.ChainId,
 	}.Trim(), nil
 }
 
+// Discover proposes the latest nft sent back to iris from stars, or else juno, by the participant
+// with its cw721 contract on the source.
+func (v A5Verifier) Discover(user UserInfo) ([][]string, error) {
+	for _, src := range []string{chain.ChainIdAbbreviationStars, chain.ChainIdAbbreviationJuno} {
+		sent, err := sentNfts(v.r, user, src, chain.ChainIdAbbreviationIris)
+		if err != nil {
+			return nil, err
+		}
+		c, ok := v.r.GetChain(src).(chain.ICS721)
+		if !ok {
+			continue
+		}
+		for _, tx := range sent {
+			contract, err := c.NftContract(tx.ClassId)
+			if err != nil {
+				continue
+			}
+			return [][]string{{tx.hash, contract, tx.TokenId, chainIdValues[src]}}, nil
+		}
+	}
+	return nil, nil
+}
+
 func (p A5Params) Trim() A5Params {
 	res := p
 	res.TxHash = strings.TrimSpace(res.TxHash)
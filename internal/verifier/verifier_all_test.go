@@ -0,0 +1,187 @@
+package verifier
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/chain/fake"
+	"github.com/taramakage/gon-verifier/internal/participant"
+	"github.com/taramakage/gon-verifier/internal/scorecard"
+)
+
+// writeEvidence writes <dir>/<github>/evidence.xlsx with the given sheets.
+func writeEvidence(t *testing.T, dir, github string, sheets map[string][][]string) {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	for name, rows := range sheets {
+		if _, err := f.NewSheet(name); err != nil {
+			t.Fatal(err)
+		}
+		for i, row := range rows {
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			if err := f.SetSheetRow(name, cell, &row); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, github), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SaveAs(filepath.Join(dir, github, scorecard.DefaultEvidenceFile)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyAll(t *testing.T) {
+	c := &campaign.Campaign{
+		Name:   "test",
+		Points: map[string]int32{"A1": 10},
+		Stages: []campaign.Stage{{
+			Name:          "one",
+			TaskPointFile: "taskpoint1.xlsx",
+			Tasks:         []campaign.Task{{No: "A1", Verifier: campaign.VerifierA1}},
+		}},
+	}
+	n := fake.NewNetwork()
+	iris := n.Chain(chain.ChainIdAbbreviationIris)
+	info := []string{"team", "iaa1user", "stars1user", "juno1user", "uptick1user", "omniflix1user"}
+	issue := func(sender string) string {
+		return iris.IssueDenom(chain.Class{ID: "denom" + sender, Creator: sender, Uri: "ipfs://class", Data: testClassData}, sender)
+	}
+
+	dir := t.TempDir()
+	writeEvidence(t, dir, "alice", map[string][][]string{
+		"Info": {{"team_name"}, info},
+		"A1":   {{"tx_hash", "class_id"}, {issue("iaa1user"), "denomiaa1user"}},
+	})
+	writeEvidence(t, dir, "bob", map[string][][]string{
+		"A1": {{"tx_hash", "class_id"}, {issue("iaa1user"), "denomiaa1user"}},
+	})
+	writeEvidence(t, dir, "carol", map[string][][]string{
+		"Info": {{"team_name"}, info},
+		"A1":   {{"tx_hash", "class_id"}, {issue("iaa1other"), "denomiaa1other"}},
+	})
+
+	gv, err := NewGonVerifier(dir, n.Registry(), c, nil, []string{OutputXlsx, OutputJSON, OutputCSV}, DiscoveryOff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcomes, err := gv.VerifyAll(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcomes) != 3 {
+		t.Fatalf("want 3 outcomes, got %d", len(outcomes))
+	}
+
+	alice, bob, carol := outcomes[0], outcomes[1], outcomes[2]
+	if alice.Err != nil || alice.Github != "alice" || alice.Tasks != 1 || alice.Passed != 1 || alice.Point != 10 {
+		t.Errorf("alice: unexpected outcome %+v", alice)
+	}
+	if bob.Err == nil {
+		t.Errorf("bob: want error without info sheet")
+	}
+	if carol.Err != nil || carol.Tasks != 1 || carol.Passed != 0 || carol.Point != 0 {
+		t.Errorf("carol: unexpected outcome %+v", carol)
+	}
+	for _, github := range []string{"alice", "carol"} {
+		if _, err := os.Stat(filepath.Join(dir, github, "taskpoint1.xlsx")); err != nil {
+			t.Errorf("%s: task point file not written: %v", github, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if failed := WriteSummary(&buf, outcomes); failed != 1 {
+		t.Errorf("want 1 failed participant, got %d", failed)
+	}
+	if !strings.Contains(buf.String(), "3 participant(s) verified, 2 succeeded, 1 failed") {
+		t.Errorf("unexpected summary:\n%s", buf.String())
+	}
+}
+
+func TestVerifyAllParticipants(t *testing.T) {
+	c := &campaign.Campaign{
+		Name:   "test",
+		Points: map[string]int32{"A1": 10},
+		Stages: []campaign.Stage{{
+			Name:          "one",
+			TaskPointFile: "points-one.xlsx",
+			Tasks:         []campaign.Task{{No: "A1", Verifier: campaign.VerifierA1}},
+		}},
+	}
+	n := fake.NewNetwork()
+	iris := n.Chain(chain.ChainIdAbbreviationIris)
+	evidence := func(sender string) map[string][][]string {
+		hash := iris.IssueDenom(chain.Class{ID: "denom" + sender, Creator: sender, Uri: "ipfs://class", Data: testClassData}, sender)
+		return map[string][][]string{
+			"Info": {{"team", "iris"}, {"team-fake", sender}},
+			"A1":   {{"tx_hash", "class_id"}, {hash, "denom" + sender}},
+		}
+	}
+	participants, err := participant.NewRegistry([]participant.Participant{
+		{Id: "p1", Github: "alice-gh", Discord: "alice#1", Team: "Wolves", Addresses: map[string]string{chain.ChainIdAbbreviationIris: "iaa1alice"}},
+		{Id: "bob", Github: "bob-gh", Team: "Bears"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	// alice is found by her address, bob by his directory
+	writeEvidence(t, dir, "alice", evidence("iaa1alice"))
+	writeEvidence(t, dir, "bob", evidence("iaa1bob"))
+
+	gv, err := NewGonVerifier(dir, n.Registry(), c, participants, []string{OutputXlsx}, DiscoveryOff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcomes, err := gv.VerifyAll(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"p1", "bob"} {
+		if o := outcomes[i]; o.Err != nil || o.Id != want || o.Point != 10 {
+			t.Errorf("%s: unexpected outcome %+v", want, o)
+		}
+	}
+
+	if err := scorecard.NewScoreCard(c, dir, participants).Generate(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(filepath.Join(dir, scorecard.DefaultScoreCardFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows(scorecard.DefaultScoreCardSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"rank", "team_name", "task_completed", "final_score", "update_time", "failed_reason", "github_account", "participant_id", "discord"},
+		{"1", "Bears", "A1", "10", "", "", "@bob-gh", "bob"},
+		{"1", "Wolves", "A1", "10", "", "", "@alice-gh", "p1", "alice#1"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("want scorecard %v, got %v", want, rows)
+	}
+
+	writeEvidence(t, dir, "carol", evidence("iaa1carol"))
+	writeEvidence(t, dir, "dave", evidence("iaa1alice"))
+	conflicts, err := EvidenceConflicts(dir, participants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 2 || !strings.Contains(conflicts[0], "carol") || !strings.Contains(conflicts[1], "participant p1 already has evidence") {
+		t.Errorf("unexpected conflicts %q", conflicts)
+	}
+}
@@ -1,6 +1,9 @@
 package verifier
 
 import (
+	"errors"
+	"fmt"
+	"github.com/taramakage/gon-verifier/internal/campaign"
 	"github.com/taramakage/gon-verifier/internal/chain"
 	"github.com/taramakage/gon-verifier/internal/types"
 	"strings"
@@ -8,32 +11,32 @@ import (
 
 type FlowParams struct {
 	TxHashes        []string
-	IbcClassId      string // ibc/hash on iris
-	OriginalClassId string // original class id
-	TokenId         string // token-id
-	ParamErrorMsg   string
+	IbcClassId      string   // ibc/hash on iris
+	OriginalClassId string   // original class id
+	TokenId         string   // token-id
+	TokenIds        []string // candidate token-ids of a batch packet, resolved by the hops
+	ParamErr        *Reason
 }
 
 type FlowVerifier struct {
-	r   *chain.Registry
-	f   *chain.Flow
-	ngb bool
+	r        *chain.Registry
+	f        *chain.Flow
+	ngb      bool
+	strict   bool             // a never-go-back task fails when the route traced isn't the flow
+	w        *campaign.Window // window of the txs of every hop, none if nil
+	feePayer bool             // the user must pay the fees of the txs of every hop
 }
 
-func NewFlowVerifier(r *chain.Registry, flowId string, ngb bool) *FlowVerifier {
-	flowStr, ok := chain.FlowStrMap[flowId]
-	if !ok {
-		return nil
-	}
-	f, err := chain.NewFlow(flowStr)
+func NewFlowVerifier(r *chain.Registry, flowId string, ngb bool) (*FlowVerifier, error) {
+	f, err := chain.LookupFlow(flowId)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 	return &FlowVerifier{
 		r:   r,
 		f:   f,
 		ngb: ngb,
-	}
+	}, nil
 }
 
 func (v FlowVerifier) Do(req Request, res chan<- *Response) {
@@ -44,18 +47,18 @@ func (v FlowVerifier) Do(req Request, res chan<- *Response) {
 
 	params, ok := req.Params.(FlowParams)
 	if !ok {
-		result.Reason = ReasonParamsFormatIncorrect
+		result.Reason = NewReason(ReasonParamsFormatIncorrect)
 		res <- result
 		return
 	}
-	if len(params.ParamErrorMsg) != 0 {
-		result.Reason = params.ParamErrorMsg
+	if params.ParamErr != nil {
+		result.Reason = params.ParamErr
 		res <- result
 		return
 	}
 
 	if !v.ngb {
-		if ok, reason := v.ValidateByTxHash(&params, &req); !ok {
+		if ok, reason := v.ValidateByTxHash(&params, &req, result); !ok {
 			result.Reason = reason
 			res <- result
 			return
@@ -68,12 +71,20 @@ func (v FlowVerifier) Do(req Request, res chan<- *Response) {
 		return
 	}
 
-	result.Point = PointMap[req.TaskNo]
+	if v.ngb {
+		if ok, reason := v.ValidateByTrace(&params, &req, result); !ok {
+			result.Reason = reason
+			res <- result
+			return
+		}
+	}
+
+	result.Point = req.Point
 	res <- result
 }
 
 // ValidateByIbcClass check the owner of nft under ibc class on last destination
-func (v FlowVerifier) ValidateByIbcClass(param *FlowParams, req *Request) (bool, string) {
+func (v FlowVerifier) ValidateByIbcClass(param *FlowParams, req *Request) (bool, *Reason) {
 	// check nft existence
 	iris := v.r.GetChain(chain.ChainIdAbbreviationIris)
 
@@ -86,66 +97,191 @@ func (v FlowVerifier) ValidateByIbcClass(param *FlowParams, req *Request) (bool,
 
 	nft, err := iris.GetNFT(classId, param.TokenId)
 	if err != nil {
-		return false, ReasonNftNotFound
+		return false, NewReason(ReasonNftNotFound)
 	}
 	// check owner of nft
 	if req.User.Address[chain.ChainIdAbbreviationIris] != nft.Owner {
-		return false, ReasonNftOwnerNotMatch
+		return false, NewReason(ReasonNftOwnerNotMatch).Want(req.User.Address[chain.ChainIdAbbreviationIris], nft.Owner)
 	}
 	// ibc class trace match the flow
 	hash, _ := v.f.GetFinalIbcHash(param.OriginalClassId)
 	ibc := "ibc/" + hash.String()
 	if ibc != param.IbcClassId {
-		return false, ReasonIbcClassNotMatch
+		return false, NewReason(ReasonIbcClassNotMatch).Want(ibc, param.IbcClassId)
 	}
-	return true, ""
+	return true, nil
+}
+
+// ValidateByTrace follows the nft from its latest send on iris and reports the route taken in the memo with the
+// flows it matches, another route may end with the same ibc class. The sends are traced up to the heights of the stage.
+// The ibc class decides the task: a route untraceable, e.g. by a node without tx search, or another route only fails
+// a strict task. The hops of the flow traced are checked against the window.
+func (v FlowVerifier) ValidateByTrace(param *FlowParams, req *Request, result *Response) (bool, *Reason) {
+	fail := func(reason *Reason) (bool, *Reason) {
+		if v.strict {
+			return false, reason
+		}
+		return true, nil
+	}
+	if _, ok := v.r.GetChain(chain.ChainIdAbbreviationIris).(chain.TxSearcher); !ok {
+		result.Memo = "route: untraceable, iris can't search txs"
+		return fail(NewReason(ReasonIbcRouteUntraceable).Want("tx search on iris", "unsupported"))
+	}
+	trace, err := chain.TraceToken(v.r, req.User.Address[chain.ChainIdAbbreviationIris], param.OriginalClassId, param.TokenId, nil)
+	if err != nil {
+		result.Memo = fmt.Sprintf("route: untraceable, %v", err)
+		return fail(NewReason(ReasonIbcRouteUntraceable))
+	}
+
+	ids := chain.MatchFlowIds(trace.Flow)
+	if len(ids) == 0 {
+		ids = []string{"no flow"}
+	}
+	result.Memo = fmt.Sprintf("route: %s, matches %s", trace.Flow, strings.Join(ids, ", "))
+	if !trace.Flow.SameRoute(v.f) {
+		return fail(NewReason(ReasonIbcRouteNotMatch).Want(v.f.String(), trace.Flow.String()))
+	}
+	for i, height := range trace.Heights {
+		if reason := checkWindow(v.r, v.w, v.f.GetSrcChainAbbr(i), height); reason != nil {
+			return false, reason.AtHop(i + 1)
+		}
+	}
+	return true, nil
 }
 
 // ValidateByTxHash validate each tx hash according the flow
-func (v FlowVerifier) ValidateByTxHash(param *FlowParams, req *Request) (bool, string) {
+func (v FlowVerifier) ValidateByTxHash(param *FlowParams, req *Request, result *Response) (bool, *Reason) {
 	for i, txHash := range param.TxHashes {
 		// get tx result
 		srcChain := v.r.GetChain(v.f.GetSrcChainAbbr(i))
 		txi, err := srcChain.GetTx(txHash, types.TxResultTypeIbcNft)
 		if err != nil {
-			return false, ReasonTxResultUnachievable + "" + v.f.GetSrcChainAbbr(i)
+			return false, NewTxReason(err).AtHop(i + 1)
 		}
 		tx, ok := txi.(types.TxResultIbcNft)
 		if !ok {
-			return false, ReasonTxResultUnexpected
+			return false, NewReason(ReasonTxResultUnexpected).AtHop(i + 1)
 		}
+		result.addTx(v.f.GetSrcChainAbbr(i), txHash, tx.Height)
 		if tx.TxCode != 0 {
-			return false, ReasonTxResultUnsuccessful
+			return false, NewReason(ReasonTxResultUnsuccessful).AtHop(i + 1)
+		}
+		if reason := checkWindow(v.r, v.w, v.f.GetSrcChainAbbr(i), tx.Height); reason != nil {
+			return false, reason.AtHop(i + 1)
+		}
+		if reason := checkTxBody(tx.Body, req.User.Address[v.f.GetSrcChainAbbr(i)], v.feePayer, ibcSendMsgType(v.f.GetSrcChainAbbr(i))); reason != nil {
+			return false, reason.AtHop(i + 1)
 		}
 
 		pcp := v.f.GetPortChanPairByIdx(i)
 		dpc := pcp.GetDestPortChan()
 
 		if tx.DestPort != dpc.Port {
-			return false, ReasonIbcDestPortNotMatch
+			return false, NewReason(ReasonIbcDestPortNotMatch).AtHop(i+1).Want(dpc.Port, tx.DestPort)
 		}
 		if tx.DestChan != dpc.Channel {
-			return false, ReasonIbcDestChanNotMatch
+			return false, NewReason(ReasonIbcDestChanNotMatch).AtHop(i+1).Want(dpc.Channel, tx.DestChan)
 		}
-		if tx.Sender != req.User.Address[v.f.GetSrcChainAbbr(i)] {
-			return false, ReasonTxMsgSenderNotMatch
+		// the signers of a decoded tx are checked with its body
+		if tx.Body == nil && tx.Sender != req.User.Address[v.f.GetSrcChainAbbr(i)] {
+			return false, NewReason(ReasonTxMsgSenderNotMatch).AtHop(i+1).Want(req.User.Address[v.f.GetSrcChainAbbr(i)], tx.Sender)
 		}
 		if tx.Receiver != req.User.Address[v.f.GetDestChainAbbr(i)] {
-			return false, ReasonNftRecipientNotMatch
+			return false, NewReason(ReasonNftRecipientNotMatch).AtHop(i+1).Want(req.User.Address[v.f.GetDestChainAbbr(i)], tx.Receiver)
 		}
-		if tx.TokenId != param.TokenId {
-			return false, ReasonNftTokenIdNotMatch
+		if len(param.TokenId) != 0 {
+			if reason := tokenReason(tx, param.TokenId); reason != nil {
+				return false, reason.AtHop(i + 1)
+			}
+		} else {
+			carried, reason := carriedTokens(tx, param.TokenIds)
+			if reason != nil {
+				return false, reason.AtHop(i + 1)
+			}
+			param.TokenIds = carried
 		}
+		if ok, reason := v.ValidatePacket(i, tx); !ok {
+			return false, reason
+		}
+	}
+
+	if len(param.TokenId) == 0 {
+		if len(param.TokenIds) != 1 {
+			return false, NewReason(ReasonNftTokenAmbiguous).Want("", strings.Join(param.TokenIds, ";"))
+		}
+		param.TokenId = param.TokenIds[0]
+	}
+	return true, nil
+}
+
+// ValidatePacket follows the packet of a hop: it must not time out, must be received on the destination
+// with a successful ack and the ack must be relayed back to the source.
+// Hops between chains which can't trace packets are not checked.
+func (v FlowVerifier) ValidatePacket(i int, tx types.TxResultIbcNft) (bool, *Reason) {
+	src, ok := v.r.GetChain(v.f.GetSrcChainAbbr(i)).(chain.PacketTracer)
+	if !ok {
+		return true, nil
+	}
+	dest, ok := v.r.GetChain(v.f.GetDestChainAbbr(i)).(chain.PacketTracer)
+	if !ok {
+		return true, nil
+	}
+	packet := chain.PacketOf(tx)
+
+	_, err := src.GetPacketTx(types.EventTypeIbcTimeoutPacket, packet)
+	if err == nil {
+		return false, NewReason(ReasonIbcPacketTimeout).AtHop(i + 1)
+	}
+	if !errors.Is(err, chain.ErrPacketTxNotFound) {
+		return false, NewReason(ReasonTxResultUnachievable).AtHop(i + 1)
+	}
+
+	recv, err := dest.GetPacketTx(types.EventTypeIbcRecvPacket, packet)
+	if errors.Is(err, chain.ErrPacketTxNotFound) {
+		return false, NewReason(ReasonIbcPacketNotReceived).AtHop(i + 1)
+	}
+	if err != nil {
+		return false, NewReason(ReasonTxResultUnachievable).AtHop(i + 1)
+	}
+	ack, err := recv.IbcAck()
+	if err != nil {
+		return false, NewReason(ReasonTxResultUnexpected).AtHop(i + 1)
+	}
+	if len(ack.Error) != 0 {
+		// the scorecard splits reasons on ","
+		return false, NewReason(ReasonIbcPacketAckError).AtHop(i+1).Want("", strings.ReplaceAll(ack.Error, ",", ";"))
 	}
 
-	return true, ""
+	_, err = src.GetPacketTx(types.EventTypeIbcAckPacket, packet)
+	if errors.Is(err, chain.ErrPacketTxNotFound) {
+		return false, NewReason(ReasonIbcPacketNotAcknowledged).AtHop(i + 1)
+	}
+	if err != nil {
+		return false, NewReason(ReasonTxResultUnachievable).AtHop(i + 1)
+	}
+	return true, nil
 }
 
-func (v FlowVerifier) BuildParams(rows [][]string) (any, error) {
+// Schema declares the class the nft ends with for the never-go-back tasks, or a row per hop
+// with the token id next to the first tx hash when the tx sends several tokens.
+func (v FlowVerifier) Schema() Schema {
 	if v.ngb {
-		return v.buildParamsNgb(rows)
+		return rowsSchema(1, ibcClassIdColumn, tokenIdColumn)
 	}
-	return v.buildParams(rows)
+	hops := v.f.GetFlowHops()
+	s := rowsSchema(hops, txHashColumn, Column{Name: "token id", Kind: ColumnTokenId, Optional: true})
+	for i := 0; i < hops; i++ {
+		src, dest := chain.ChainNames[v.f.GetSrcChainAbbr(i)], chain.ChainNames[v.f.GetDestChainAbbr(i)]
+		s.RowNames = append(s.RowNames, fmt.Sprintf("hop %d: %s to %s", i+1, src, dest))
+	}
+	return s
+}
+
+func (v FlowVerifier) BuildParams(records []EvidenceRecord) (any, error) {
+	if v.ngb {
+		return v.buildParamsNgb(records)
+	}
+	return v.buildParams(records)
 }
 
 // buildParamsNgb build params from never-go-back transfer evidence
@@ -153,19 +289,18 @@ func (v FlowVerifier) BuildParams(rows [][]string) (any, error) {
 // - ibcClassId: provided by rows
 // - tokenId: provided by rows
 // - originalClassId:
-func (v FlowVerifier) buildParamsNgb(rows [][]string) (any, error) {
-	errMsg := restrictParamLen(rows, 1)
-	if len(errMsg) != 0 {
+func (v FlowVerifier) buildParamsNgb(records []EvidenceRecord) (any, error) {
+	paramErr := restrictParamLen(records, 1)
+	if paramErr != nil {
 		return FlowParams{
-			ParamErrorMsg: errMsg,
+			ParamErr: paramErr,
 		}, nil
 	}
 
 	params := FlowParams{
-		TxHashes:      nil,
-		IbcClassId:    rows[0][0],
-		TokenId:       rows[0][1],
-		ParamErrorMsg: "",
+		TxHashes:   nil,
+		IbcClassId: records[0].IbcClassId,
+		TokenId:    records[0].TokenId,
 	}
 	return params.Trim().AddOriginalClassId(&v), nil
 }
@@ -173,26 +308,51 @@ func (v FlowVerifier) buildParamsNgb(rows [][]string) (any, error) {
 // buildParams build params from non never-go-back transfer evidence
 // - txHashes: provided by rows
 // - ibcClassId: calculated by flow-id and the first txHash
-// - tokenId: calculated until the first txHash is used
-func (v FlowVerifier) buildParams(rows [][]string) (any, error) {
+// - tokenId: calculated until the first txHash is used, or provided next to it when the tx sends several tokens
+func (v FlowVerifier) buildParams(records []EvidenceRecord) (any, error) {
 	maxHop := v.f.GetFlowHops()
-	errMsg := restrictParamLen(rows, maxHop)
-	if len(errMsg) != 0 {
+	paramErr := restrictParamLen(records, maxHop)
+	if paramErr != nil {
 		return FlowParams{
-			ParamErrorMsg: errMsg,
+			ParamErr: paramErr,
 		}, nil
 	}
 
 	params := FlowParams{
 		TxHashes: make([]string, maxHop),
 	}
-	for i := range rows {
-		params.TxHashes[i] = rows[i][0]
+	for i := range records {
+		params.TxHashes[i] = records[i].TxHash
 	}
+	params.TokenId = records[0].TokenId
 
 	return params.Trim().AddThreeKindId(&v), nil
 }
 
+// Discover proposes the send txs of the latest route from iris taking the flow,
+// or the class the nft ends with for the never-go-back tasks.
+func (v FlowVerifier) Discover(user UserInfo) ([][]string, error) {
+	sent, err := sentNfts(v.r, user, chain.ChainIdAbbreviationIris, v.f.GetDestChainAbbr(0))
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range sent {
+		trace, err := chain.TraceFlow(v.r, tx.hash)
+		if err != nil || !trace.Flow.SameRoute(v.f) {
+			continue
+		}
+		if v.ngb {
+			return [][]string{{chain.IbcClassId(trace.ClassId), trace.TokenId}}, nil
+		}
+		rows := make([][]string, 0, len(trace.TxHashes))
+		for _, hash := range trace.TxHashes {
+			rows = append(rows, []string{hash})
+		}
+		return rows, nil
+	}
+	return nil, nil
+}
+
 func (p FlowParams) Trim() FlowParams {
 	res := p
 	res.TokenId = strings.TrimSpace(res.TokenId)
@@ -205,14 +365,14 @@ func (p FlowParams) Trim() FlowParams {
 
 func (p FlowParams) AddOriginalClassId(v *FlowVerifier) FlowParams {
 	irisi := v.r.GetChain(chain.ChainIdAbbreviationIris)
-	iris, ok := irisi.(*chain.Iris)
+	iris, ok := irisi.(chain.ClassTracer)
 	if !ok {
-		p.ParamErrorMsg = ReasonParamsFormatIncorrect
+		p.ParamErr = NewReason(ReasonParamsFormatIncorrect)
 		return p
 	}
 	originalClassId, err := iris.GetOriginalClassId(p.IbcClassId)
 	if err != nil {
-		p.ParamErrorMsg = ReasonIbcOriginalClassIdNotMatch
+		p.ParamErr = NewReason(ReasonIbcOriginalClassIdNotMatch)
 		return p
 	}
 	p.OriginalClassId = originalClassId
@@ -224,19 +384,29 @@ func (p FlowParams) AddThreeKindId(v *FlowVerifier) FlowParams {
 	srcChain := v.r.GetChain(chain.ChainIdAbbreviationIris)
 	txi, err := srcChain.GetTx(p.TxHashes[0], types.TxResultTypeIbcNft)
 	if err != nil {
-		p.ParamErrorMsg = ReasonTxResultUnachievable
+		p.ParamErr = NewTxReason(err).AtHop(1)
 		return p
 	}
 	tx, ok := txi.(types.TxResultIbcNft)
 	if !ok {
-		p.ParamErrorMsg = ReasonTxResultUnexpected
+		p.ParamErr = NewReason(ReasonTxResultUnexpected).AtHop(1)
 		return p
 	}
 	if tx.TxCode != 0 {
-		p.ParamErrorMsg = ReasonTxResultUnsuccessful
+		p.ParamErr = NewReason(ReasonTxResultUnsuccessful).AtHop(1)
 		return p
 	}
-	p.TokenId = tx.TokenId
+	switch {
+	case len(p.TokenId) != 0:
+		if reason := tokenReason(tx, p.TokenId); reason != nil {
+			p.ParamErr = reason.AtHop(1)
+			return p
+		}
+	case len(tx.Tokens) == 1:
+		p.TokenId = tx.TokenId
+	default:
+		p.TokenIds = tx.TokenIds()
+	}
 	p.OriginalClassId = tx.OriginalClass()
 	// NOTE: ibc class id is not provided by user, so we need to calculate it
 	hash, _ := v.f.GetFinalIbcHash(p.OriginalClassId)
@@ -0,0 +1,451 @@
+package verifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/chain/fake"
+)
+
+// hop is a transfer of a flow, e.g. {"i", "s", "1"} for "i --(1)--> s".
+type hop [3]string
+
+var (
+	hopsA01 = []hop{{"i", "s", "1"}, {"s", "j", "1"}, {"j", "i", "1"}}
+	hopsB01 = []hop{{"i", "s", "1"}, {"s", "u", "1"}, {"u", "s", "1"}, {"s", "i", "2"}}
+	hopsC01 = []hop{{"i", "s", "1"}, {"s", "j", "1"}, {"j", "s", "1"}, {"s", "i", "1"}}
+)
+
+// playFlow mints an nft on iris and transfers it along the hops between the addresses of the test user.
+// It returns the hash of each transfer and the class id of the nft back on iris.
+func playFlow(n *fake.Network, hops []hop) ([]string, string) {
+	iris := chain.ChainIdAbbreviationIris
+	issueAndMint(n.Chain(iris), "denom1", "nft1", testUser.Address[iris])
+
+	hashes := make([]string, 0, len(hops))
+	classID := "denom1"
+	for _, h := range hops {
+		var hash string
+		hash, classID = n.Transfer(h[0], h[1], h[2], classID, "nft1", testUser.Address[h[0]], testUser.Address[h[1]])
+		hashes = append(hashes, hash)
+	}
+	return hashes, classID
+}
+
+// playBatchFlow mints nft1 and nft2 on iris and sends both in one packet on the first hop, the next hops
+// send nft2 alone, or both if batch. It returns the hash of each transfer.
+func playBatchFlow(n *fake.Network, hops []hop, batch bool) []string {
+	iris := chain.ChainIdAbbreviationIris
+	issueAndMint(n.Chain(iris), "denom1", "nft1", testUser.Address[iris])
+
+	hashes := make([]string, 0, len(hops))
+	classID := "denom1"
+	tokenIds := []string{"nft1", "nft2"}
+	for i, h := range hops {
+		if i == 1 && !batch {
+			tokenIds = tokenIds[1:]
+		}
+		var hash string
+		hash, classID = n.TransferBatch(h[0], h[1], h[2], classID, tokenIds, testUser.Address[h[0]], testUser.Address[h[1]])
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+func txRows(hashes []string) [][]string {
+	rows := make([][]string, 0, len(hashes))
+	for _, hash := range hashes {
+		rows = append(rows, []string{hash})
+	}
+	return rows
+}
+
+// flowVerifier returns the constructor of the verifier of a flow.
+func flowVerifier(t *testing.T, flowId string, ngb bool) func(r *chain.Registry) Verifier {
+	return func(r *chain.Registry) Verifier {
+		vf, err := NewFlowVerifier(r, flowId, ngb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return vf
+	}
+}
+
+func TestFlowVerifierNgb(t *testing.T) {
+	newVerifier := flowVerifier(t, "a01", true)
+
+	runVerifierCases(t, newVerifier, []verifierCase{
+		{
+			name: "pass",
+			build: func(n *fake.Network) [][]string {
+				_, classID := playFlow(n, hopsA01)
+				return [][]string{{classID, "nft1"}}
+			},
+		},
+		{
+			name: "another route",
+			build: func(n *fake.Network) [][]string {
+				_, classID := playFlow(n, []hop{{"i", "s", "1"}, {"s", "j", "3"}, {"j", "i", "1"}})
+				return [][]string{{classID, "nft1"}}
+			},
+			reason: ReasonIbcClassNotMatch,
+		},
+		{
+			name: "owned by another address",
+			build: func(n *fake.Network) [][]string {
+				_, classID := playFlow(n, hopsA01)
+				n.Chain(chain.ChainIdAbbreviationIris).TransferNFT(classID, "nft1", testUser.Address["i"], "iaa1other")
+				return [][]string{{classID, "nft1"}}
+			},
+			reason: ReasonNftOwnerNotMatch,
+		},
+		{
+			name: "unknown ibc class",
+			build: func(n *fake.Network) [][]string {
+				playFlow(n, hopsA01)
+				return [][]string{{"ibc/FAKE", "nft1"}}
+			},
+			reason: ReasonIbcOriginalClassIdNotMatch,
+		},
+		{
+			name: "nft not found",
+			build: func(n *fake.Network) [][]string {
+				_, classID := playFlow(n, hopsA01)
+				return [][]string{{classID, "nft2"}}
+			},
+			reason: ReasonNftNotFound,
+		},
+	})
+}
+
+func TestFlowVerifier(t *testing.T) {
+	for _, fc := range []struct {
+		flowId string
+		hops   []hop
+	}{
+		{"a01", hopsA01},
+		{"b01", hopsB01},
+		{"c01", hopsC01},
+	} {
+		fc := fc
+		t.Run(fc.flowId, func(t *testing.T) {
+			newVerifier := flowVerifier(t, fc.flowId, false)
+
+			runVerifierCases(t, newVerifier, []verifierCase{
+				{
+					name: "pass",
+					build: func(n *fake.Network) [][]string {
+						hashes, _ := playFlow(n, fc.hops)
+						return txRows(hashes)
+					},
+				},
+				{
+					name: "missing hop",
+					build: func(n *fake.Network) [][]string {
+						hashes, _ := playFlow(n, fc.hops)
+						return txRows(hashes[:len(hashes)-1])
+					},
+					reason: ReasonParamsRowsInvalid,
+				},
+				{
+					name: "first tx not found",
+					build: func(n *fake.Network) [][]string {
+						hashes, _ := playFlow(n, fc.hops)
+						hashes[0] = "ABCDEF"
+						return txRows(hashes)
+					},
+					reason: ReasonTxResultUnachievable,
+					hop:    1,
+				},
+				{
+					name: "hops out of order",
+					build: func(n *fake.Network) [][]string {
+						hashes, _ := playFlow(n, fc.hops)
+						hashes[1], hashes[2] = hashes[2], hashes[1]
+						return txRows(hashes)
+					},
+					reason: ReasonTxResultUnachievable,
+					hop:    2,
+				},
+			})
+		})
+	}
+
+	newVerifier := flowVerifier(t, "a01", false)
+	runVerifierCases(t, newVerifier, []verifierCase{
+		{
+			name: "another channel",
+			build: func(n *fake.Network) [][]string {
+				hashes, _ := playFlow(n, []hop{{"i", "s", "1"}, {"s", "j", "3"}, {"j", "i", "1"}})
+				return txRows(hashes)
+			},
+			reason: ReasonIbcDestChanNotMatch,
+			hop:    2,
+		},
+		{
+			name: "received by another address",
+			build: func(n *fake.Network) [][]string {
+				hashes, classID := playFlow(n, hopsA01[:1])
+				hash, classID := n.Transfer("s", "j", "1", classID, "nft1", testUser.Address["s"], "juno1other")
+				hashes = append(hashes, hash)
+				hash, _ = n.Transfer("j", "i", "1", classID, "nft1", "juno1other", testUser.Address["i"])
+				return txRows(append(hashes, hash))
+			},
+			reason: ReasonNftRecipientNotMatch,
+		},
+		{
+			name: "timed out then sent again",
+			build: func(n *fake.Network) [][]string {
+				hashes, classID := playFlow(n, hopsA01[:1])
+				timedOut := n.TransferTimeout("s", "j", "1", classID, "nft1", testUser.Address["s"], testUser.Address["j"])
+				_, classID = n.Transfer("s", "j", "1", classID, "nft1", testUser.Address["s"], testUser.Address["j"])
+				hash, _ := n.Transfer("j", "i", "1", classID, "nft1", testUser.Address["j"], testUser.Address["i"])
+				return txRows(append(hashes, timedOut, hash))
+			},
+			reason: ReasonIbcPacketTimeout,
+			hop:    2,
+		},
+		{
+			name: "acknowledged with error",
+			build: func(n *fake.Network) [][]string {
+				hashes, classID := playFlow(n, hopsA01[:2])
+				hash := n.TransferAckError("j", "i", "1", classID, "nft1", testUser.Address["j"], testUser.Address["i"], "class not allowed")
+				return txRows(append(hashes, hash))
+			},
+			reason: ReasonIbcPacketAckError,
+			hop:    3,
+		},
+		{
+			name: "proof not match the header",
+			build: func(n *fake.Network) [][]string {
+				hashes, _ := playFlow(n, hopsA01)
+				n.Chain("s").VerifyProofs()
+				n.Chain("s").SetDataHash(hashes[1], make([]byte, 32))
+				return txRows(hashes)
+			},
+			reason: ReasonTxProofInvalid,
+			hop:    2,
+		},
+	})
+}
+
+func TestFlowVerifierBatch(t *testing.T) {
+	// rows names the token of the batch next to the first hash
+	rows := func(hashes []string, tokenId string) [][]string {
+		rows := txRows(hashes)
+		rows[0] = append(rows[0], tokenId)
+		return rows
+	}
+
+	runVerifierCases(t, flowVerifier(t, "a01", false), []verifierCase{
+		{
+			name: "token resolved by the hops",
+			build: func(n *fake.Network) [][]string {
+				return txRows(playBatchFlow(n, hopsA01, false))
+			},
+		},
+		{
+			name: "token named",
+			build: func(n *fake.Network) [][]string {
+				return rows(playBatchFlow(n, hopsA01, false), "nft2")
+			},
+		},
+		{
+			name: "named token of the whole batch",
+			build: func(n *fake.Network) [][]string {
+				return rows(playBatchFlow(n, hopsA01, true), "nft1")
+			},
+		},
+		{
+			name: "whole batch without token named",
+			build: func(n *fake.Network) [][]string {
+				return txRows(playBatchFlow(n, hopsA01, true))
+			},
+			reason: ReasonNftTokenAmbiguous,
+		},
+		{
+			name: "named token left behind",
+			build: func(n *fake.Network) [][]string {
+				return rows(playBatchFlow(n, hopsA01, false), "nft1")
+			},
+			reason: ReasonNftTokenIdNotMatch,
+			hop:    2,
+		},
+		{
+			name: "named token not in the batch",
+			build: func(n *fake.Network) [][]string {
+				return rows(playBatchFlow(n, hopsA01, false), "nft3")
+			},
+			reason: ReasonNftTokenIdNotMatch,
+			hop:    1,
+		},
+	})
+}
+
+func TestFlowVerifierCheckedTxs(t *testing.T) {
+	n := fake.NewNetwork()
+	hashes, _ := playFlow(n, hopsA01)
+	res := verify(t, flowVerifier(t, "a01", false)(n.Registry()), txRows(hashes))
+	if res.Reason != nil {
+		t.Fatalf("unexpected reason %q", res.Reason)
+	}
+
+	if res.Chain != "i" {
+		t.Errorf("chain: want i, got %s", res.Chain)
+	}
+	if len(res.Txs) != len(hopsA01) {
+		t.Fatalf("want %d txs, got %d", len(hopsA01), len(res.Txs))
+	}
+	for i, tx := range res.Txs {
+		if tx.Chain != hopsA01[i][0] || tx.Hash != hashes[i] || tx.Height == 0 {
+			t.Errorf("tx %d: unexpected %+v", i, tx)
+		}
+	}
+}
+
+func TestTraceFlow(t *testing.T) {
+	n := fake.NewNetwork()
+	iris := chain.ChainIdAbbreviationIris
+	issueAndMint(n.Chain(iris), "denom1", "nft1", testUser.Address[iris])
+
+	// the first send to juno times out, the nft is sent again from stars after coming back
+	hashes := make([]string, 0, 4)
+	hash, classID := n.Transfer("i", "s", "1", "denom1", "nft1", testUser.Address["i"], testUser.Address["s"])
+	hashes = append(hashes, hash)
+	n.TransferTimeout("s", "j", "1", classID, "nft1", testUser.Address["s"], testUser.Address["j"])
+	for _, h := range hopsA01[1:] {
+		hash, classID = n.Transfer(h[0], h[1], h[2], classID, "nft1", testUser.Address[h[0]], testUser.Address[h[1]])
+		hashes = append(hashes, hash)
+	}
+
+	trace, err := chain.TraceToken(n.Registry(), testUser.Address[iris], "denom1", "nft1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trace.Flow.String() != chain.FlowA01 {
+		t.Errorf("want route %q, got %q", chain.FlowA01, trace.Flow)
+	}
+	if ids := chain.MatchFlowIds(trace.Flow); len(ids) != 1 || ids[0] != "a01" {
+		t.Errorf("want flow a01, got %v", ids)
+	}
+	if !reflect.DeepEqual(trace.TxHashes, hashes) {
+		t.Errorf("want txs %v, got %v", hashes, trace.TxHashes)
+	}
+	if trace.ChainId != iris || fake.IbcClassId(trace.ClassId) != classID {
+		t.Errorf("unexpected position %s %s", trace.ChainId, trace.ClassId)
+	}
+}
+
+func TestTraceFlowBatch(t *testing.T) {
+	n := fake.NewNetwork()
+	hashes := playBatchFlow(n, hopsA01, false)
+
+	if _, err := chain.TraceFlow(n.Registry(), hashes[0]); err == nil {
+		t.Error("want an error tracing a batch without token")
+	}
+	trace, err := chain.TraceFlowToken(n.Registry(), hashes[0], "nft2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trace.Flow.String() != chain.FlowA01 || !reflect.DeepEqual(trace.TxHashes, hashes) {
+		t.Errorf("unexpected trace %s %v", trace.Flow, trace.TxHashes)
+	}
+	if _, err := chain.TraceFlowToken(n.Registry(), hashes[0], "nft3"); err == nil {
+		t.Error("want an error tracing a token not sent")
+	}
+}
+
+func TestTraceFlowUntil(t *testing.T) {
+	n := fake.NewNetwork()
+	hashes, classID := playFlow(n, hopsA01[:1])
+	height := n.Chain("s").Height() - 1
+	for _, h := range hopsA01[1:] {
+		var hash string
+		hash, classID = n.Transfer(h[0], h[1], h[2], classID, "nft1", testUser.Address[h[0]], testUser.Address[h[1]])
+		hashes = append(hashes, hash)
+	}
+
+	for name, tc := range map[string]struct {
+		r     *chain.Registry
+		until map[string]int64
+	}{
+		"window":      {n.Registry(), map[string]int64{"s": height}},
+		"stage":       {n.Registry().AtHeights(map[string]int64{"s": height}), nil},
+		"lower stage": {n.Registry().AtHeights(map[string]int64{"s": height}), map[string]int64{"s": height + 100}},
+	} {
+		trace, err := chain.TraceToken(tc.r, testUser.Address["i"], "denom1", "nft1", tc.until)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if trace.ChainId != "s" || !reflect.DeepEqual(trace.TxHashes, hashes[:1]) {
+			t.Errorf("%s: want the trace to stop on s, got %s %v", name, trace.Flow, trace.TxHashes)
+		}
+	}
+
+	if _, err := chain.TraceToken(n.Registry(), testUser.Address["i"], "denom1", "nft1", map[string]int64{"i": 1}); err == nil {
+		t.Error("want an error without send before the height of iris")
+	}
+}
+
+// searchless hides the tx search of a chain.
+type searchless struct {
+	chain.Chain
+	chain.ClassTracer
+}
+
+func TestFlowVerifierNgbWithoutSearch(t *testing.T) {
+	n := fake.NewNetwork()
+	_, classID := playFlow(n, hopsA01)
+	chains := n.Registry().GetChains()
+	iris := n.Chain(chain.ChainIdAbbreviationIris)
+	chains[chain.ChainIdAbbreviationIris] = searchless{iris, iris}
+	r := chain.NewRegistryFromChains(chains)
+
+	// the ibc class decides unless the route is strict
+	res := verify(t, ngbVerifier(t, r, false), [][]string{{classID, "nft1"}})
+	if res.Reason != nil || res.Point != testPoint || !strings.HasPrefix(res.Memo, "route: untraceable") {
+		t.Errorf("want a pass with an untraceable route, got %q %d %q", res.Reason, res.Point, res.Memo)
+	}
+	res = verify(t, ngbVerifier(t, r, true), [][]string{{classID, "nft1"}})
+	if res.Reason.GetCode() != ReasonIbcRouteUntraceable {
+		t.Errorf("want %s, got %q", ReasonIbcRouteUntraceable, res.Reason)
+	}
+}
+
+func TestFlowVerifierNgbRoute(t *testing.T) {
+	detour := []hop{{"i", "s", "1"}, {"s", "u", "1"}, {"u", "s", "1"}, {"s", "j", "1"}, {"j", "i", "1"}}
+	for _, tc := range []struct {
+		name   string
+		hops   []hop
+		strict bool
+		reason ReasonCode
+		memo   string
+	}{
+		{"flow", hopsA01, false, "", "route: " + chain.FlowA01 + ", matches a01"},
+		{"strict flow", hopsA01, true, "", "route: " + chain.FlowA01 + ", matches a01"},
+		{"detour", detour, false, "", "route: i --(1)--> s --(1)--> u --(1)--> s --(1)--> j --(1)--> i, matches no flow"},
+		{"strict detour", detour, true, ReasonIbcRouteNotMatch, "route: i --(1)--> s --(1)--> u --(1)--> s --(1)--> j --(1)--> i, matches no flow"},
+	} {
+		n := fake.NewNetwork()
+		_, classID := playFlow(n, tc.hops)
+		res := verify(t, ngbVerifier(t, n.Registry(), tc.strict), [][]string{{classID, "nft1"}})
+		if res.Reason.GetCode() != tc.reason {
+			t.Errorf("%s: want reason %q, got %q", tc.name, tc.reason, res.Reason)
+		}
+		if res.Memo != tc.memo {
+			t.Errorf("%s: want memo %q, got %q", tc.name, tc.memo, res.Memo)
+		}
+	}
+}
+
+// ngbVerifier returns the never-go-back verifier of a01.
+func ngbVerifier(t *testing.T, r *chain.Registry, strict bool) Verifier {
+	vf, err := NewFlowVerifier(r, "a01", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vf.strict = strict
+	return vf
+}
@@ -1,8 +1,9 @@
 package verifier
 
 import (
-	"fmt"
+	"errors"
 	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/taskpoint"
 	"github.com/taramakage/gon-verifier/internal/types"
 	"strconv"
 	"strings"
@@ -11,7 +12,7 @@ import (
 type RaceParam struct {
 	firstTransfer string
 	lastTransfer  string
-	ParamErrorMsg string
+	ParamErr *Reason
 }
 
 // RaceVerifier validates whether a participant has completed task B1,B2,B5,B6,B7.
@@ -20,11 +21,12 @@ type RaceVerifier struct {
 	f  *chain.Flow
 	originalClassId string
 	designatedOwner string
-	startBlockHeight string
-	endBlockHeight string
+	startBlockHeight int64
+	endBlockHeight int64
+	feePayer bool // the user must pay the fees of the transfers
 }
 
-func NewRaceVerifier(r *chain.Registry, originalClassId string, designatedOwner string, startBlockHeight, endBlockHeight string) *RaceVerifier {
+func NewRaceVerifier(r *chain.Registry, originalClassId string, designatedOwner string, startBlockHeight, endBlockHeight int64) *RaceVerifier {
 	// flow init is delayed to verify
 	return &RaceVerifier{
 		r:   r,
@@ -43,12 +45,12 @@ func (v RaceVerifier) Do(req Request, res chan<- *Response) {
 
 	params, ok := req.Params.(RaceParam)
 	if !ok {
-		result.Reason = ReasonParamsFormatIncorrect
+		result.Reason = NewReason(ReasonParamsFormatIncorrect)
 		res <- result
 		return
 	}
-	if len(params.ParamErrorMsg) != 0 {
-		result.Reason = params.ParamErrorMsg
+	if params.ParamErr != nil {
+		result.Reason = params.ParamErr
 		res <- result
 		return
 	}
@@ -56,103 +58,165 @@ func (v RaceVerifier) Do(req Request, res chan<- *Response) {
 	iris := v.r.GetChain(chain.ChainIdAbbreviationIris)
 	txi1, err := iris.GetTx(params.firstTransfer, types.TxResultTypeRaw)
 	if err != nil {
-		result.Reason = ReasonTxResultUnachievable
+		result.Reason = NewTxReason(err).AtRow(2)
 		res <- result
 		return
 	}
 	tx1, ok := txi1.(types.TxResponse)
 	if !ok {
-		result.Reason = ReasonTxResultUnexpected
+		result.Reason = NewReason(ReasonTxResultUnexpected).AtRow(2)
 		res <- result
 		return
 	}
-
-	// build flow according to flow-id
-	race, _ := tx1.GetIbcPkgRaceData()
-	f, _ := chain.NewFlow(chain.FlowStrMap[race.Flow])
-	v.f = f
+	result.addTx(chain.ChainIdAbbreviationIris, params.firstTransfer, tx1.BlockHeight())
 
 	txi2, err := iris.GetTx(params.lastTransfer, types.TxResultTypeRaw)
 	if err != nil {
-		result.Reason = ReasonTxResultUnachievable
+		result.Reason = NewTxReason(err).AtRow(3)
 		res <- result
 		return
 	}
 	tx2, ok := txi2.(types.TxResponse)
 	if !ok {
-		result.Reason = ReasonTxResultUnexpected
+		result.Reason = NewReason(ReasonTxResultUnexpected).AtRow(3)
 		res <- result
 		return
 	}
+	result.addTx(chain.ChainIdAbbreviationIris, params.lastTransfer, tx2.BlockHeight())
 
 	first, _ := tx1.GetFirstRace()
 	last, _ := tx2.GetLastRace()
 
+	// build flow according to the flow-id of the token sent back by the last transfer
+	race, err := tx1.GetIbcPkgRaceDataOf(last.TokenId)
+	if errors.Is(err, types.ErrTokenNotFound) {
+		result.Reason = NewReason(ReasonNftTokenIdNotMatch).AtRow(2).Want(last.TokenId, strings.Join(first.TokenIds, ";"))
+		res <- result
+		return
+	}
+	if errors.Is(err, types.ErrTokenAmbiguous) {
+		result.Reason = NewReason(ReasonNftTokenAmbiguous).AtRow(2).Want(last.TokenId, strings.Join(first.TokenIds, ";"))
+		res <- result
+		return
+	}
+	f, err := chain.LookupFlow(race.Flow)
+	if err != nil {
+		result.Reason = NewReason(ReasonRaceUnexpectedFlowPath).AtRow(2)
+		res <- result
+		return
+	}
+	v.f = f
+
 	hash, _ := v.f.GetFinalIbcHash(v.originalClassId)
 	ibcClass := "ibc/" + hash.String()
 	if ibcClass != last.ClassId {
-		result.Reason = ReasonRaceUnexpectedFlowPath
+		result.Reason = NewReason(ReasonRaceUnexpectedFlowPath).Want(ibcClass, last.ClassId)
 		res <- result
 		return
 	}
 
 	if first.Sender != last.Sender {
-		result.Reason = ReasonRaceFirstLastSenderNotMatch
+		result.Reason = NewReason(ReasonRaceFirstLastSenderNotMatch).Want(first.Sender, last.Sender)
 		res <- result
 		return
 	}
 
 	if first.Sender !=  req.User.Address[chain.ChainIdAbbreviationIris] {
-		result.Reason = ReasonTxMsgSenderNotMatch
+		result.Reason = NewReason(ReasonTxMsgSenderNotMatch).Want(req.User.Address[chain.ChainIdAbbreviationIris], first.Sender)
 		res <- result
 		return
 	}
 
+	// the transfers must be signed by the sender of the packet and the nft, a tx may carry other sender attributes
+	for i, transfer := range []struct {
+		tx      types.TxResponse
+		msgType string
+	}{{tx1, types.MsgTypeIbcNft}, {tx2, types.MsgTypeTransferNft}} {
+		body, err := transfer.tx.Body()
+		if err != nil {
+			result.Reason = NewReason(ReasonTxResultUnexpected).AtRow(i + 2)
+			res <- result
+			return
+		}
+		if reason := checkTxBody(body, req.User.Address[chain.ChainIdAbbreviationIris], v.feePayer, transfer.msgType); reason != nil {
+			result.Reason = reason.AtRow(i + 2)
+			res <- result
+			return
+		}
+	}
+
 	nft, err := iris.GetNFT(last.ClassId, last.TokenId)
 	if err != nil {
-		result.Reason = ReasonNftNotFound
+		result.Reason = NewReason(ReasonNftNotFound)
 		res <- result
 		return
 	}
 
 	if nft.Owner != v.designatedOwner {
-		result.Reason = ReasonNftOwnerNotMatch
+		result.Reason = NewReason(ReasonNftOwnerNotMatch).Want(v.designatedOwner, nft.Owner)
+		res <- result
+		return
+	}
+
+	startHeight, err := strconv.ParseInt(race.StartHeight, 10, 64)
+	if err != nil {
+		result.Reason = NewReason(ReasonRaceDataUnachievable)
+		res <- result
+		return
+	}
+	if startHeight < v.startBlockHeight {
+		result.Reason = NewReason(ReasonRaceStartTooEarly).Want(strconv.FormatInt(v.startBlockHeight, 10), race.StartHeight)
 		res <- result
 		return
 	}
 
-	if race.StartHeight < v.startBlockHeight {
-		result.Reason = ReasonRaceStartTooEarly
+	lastHeight, err := strconv.ParseInt(last.Height, 10, 64)
+	if err != nil {
+		result.Reason = NewReason(ReasonRaceDataUnachievable)
 		res <- result
 		return
 	}
 
-	result.Point = PointMap[req.TaskNo]
-	if last.Height <= v.endBlockHeight {
-		result.Reason = v.BuildRaceResult(first.Height, last.Height)
+	result.Point = req.Point
+	if lastHeight <= v.endBlockHeight {
+		result.Race = v.BuildRaceResult(race.Flow, first, last, nft.Owner)
 	}
 
 	res <- result
 }
 
-func (v RaceVerifier) BuildRaceResult(first, last string) string {
-	l, _ := strconv.Atoi(last)
-	f, _ := strconv.Atoi(first)
-	diff := l - f
-	return fmt.Sprintf("race/%s/%s/%s", first, last, strconv.Itoa(diff))
+// BuildRaceResult builds the result ranked by the individual and team races.
+func (v RaceVerifier) BuildRaceResult(flow string, first, last types.RaceResult, owner string) *taskpoint.RaceResult {
+	f, _ := strconv.ParseInt(first.Height, 10, 64)
+	l, _ := strconv.ParseInt(last.Height, 10, 64)
+	return &taskpoint.RaceResult{
+		Flow:        flow,
+		TokenId:     last.TokenId,
+		StartHeight: f,
+		EndHeight:   l,
+		Diff:        l - f,
+		Owner:       owner,
+	}
+}
+
+// Schema declares the rows of the first and the last transfer of the race.
+func (v RaceVerifier) Schema() Schema {
+	s := rowsSchema(2, txHashColumn)
+	s.RowNames = []string{"first transfer", "last transfer"}
+	return s
 }
 
-func (v RaceVerifier) BuildParams(rows [][]string) (any, error) {
-	errMsg := restrictParamLen(rows, 2)
-	if len(errMsg) != 0 {
+func (v RaceVerifier) BuildParams(records []EvidenceRecord) (any, error) {
+	paramErr := restrictParamLen(records, 2)
+	if paramErr != nil {
 		return RaceParam{
-			ParamErrorMsg: errMsg,
+			ParamErr: paramErr,
 		}, nil
 	}
 
 	params := RaceParam{
-		firstTransfer: rows[0][0],
-		lastTransfer:  rows[1][0],
+		firstTransfer: records[0].TxHash,
+		lastTransfer:  records[1].TxHash,
 	}
 	return params.Trim(), nil
 }
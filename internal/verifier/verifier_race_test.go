@@ -0,0 +1,196 @@
+package verifier
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/chain/fake"
+	"github.com/taramakage/gon-verifier/internal/taskpoint"
+)
+
+const (
+	raceClass = "gonIndivRace1"
+	raceOwner = "iaa1designated"
+)
+
+// playRace runs flow a01 with a race nft minted at height start, the nft is handed to owner from sender at height end.
+func playRace(n *fake.Network, data string, start, end int64, sender, owner string) [][]string {
+	iris := n.Chain(chain.ChainIdAbbreviationIris)
+	user := testUser.Address[chain.ChainIdAbbreviationIris]
+	iris.IssueDenom(chain.Class{ID: raceClass, Creator: user, Uri: "ipfs://race", Data: testClassData}, user)
+	iris.MintNFT(raceClass, chain.NFT{ID: "nft1", URI: "ipfs://nft", Data: data, Owner: user}, user)
+
+	iris.SetHeight(start)
+	classID := raceClass
+	hashes := make([]string, 0, len(hopsA01)+1)
+	for _, h := range hopsA01 {
+		var hash string
+		hash, classID = n.Transfer(h[0], h[1], h[2], classID, "nft1", testUser.Address[h[0]], testUser.Address[h[1]])
+		hashes = append(hashes, hash)
+	}
+
+	iris.SetHeight(end)
+	last := iris.TransferNFT(classID, "nft1", sender, owner)
+	return [][]string{{hashes[0]}, {last}}
+}
+
+func TestRaceVerifier(t *testing.T) {
+	user := testUser.Address[chain.ChainIdAbbreviationIris]
+	data := `{"flow":"a01","start_height":"150"}`
+
+	for _, tc := range []struct {
+		name   string
+		build  func(n *fake.Network) [][]string
+		point  int32
+		reason ReasonCode
+		race   *taskpoint.RaceResult
+	}{
+		{
+			name:  "pass",
+			build: func(n *fake.Network) [][]string { return playRace(n, data, 150, 180, user, raceOwner) },
+			point: testPoint,
+			race:  &taskpoint.RaceResult{Flow: "a01", TokenId: "nft1", StartHeight: 150, EndHeight: 180, Diff: 30, Owner: raceOwner},
+		},
+		{
+			name:  "finished after the race",
+			build: func(n *fake.Network) [][]string { return playRace(n, data, 150, 250, user, raceOwner) },
+			point: testPoint,
+		},
+		{
+			name:   "one row",
+			build:  func(n *fake.Network) [][]string { return playRace(n, data, 150, 180, user, raceOwner)[:1] },
+			reason: ReasonParamsRowsInvalid,
+		},
+		{
+			name: "started too early",
+			build: func(n *fake.Network) [][]string {
+				return playRace(n, `{"flow":"a01","start_height":"50"}`, 150, 180, user, raceOwner)
+			},
+			reason: ReasonRaceStartTooEarly,
+		},
+		{
+			name: "unexpected flow",
+			build: func(n *fake.Network) [][]string {
+				return playRace(n, `{"flow":"a02","start_height":"150"}`, 150, 180, user, raceOwner)
+			},
+			reason: ReasonRaceUnexpectedFlowPath,
+		},
+		{
+			name:   "handed by another address",
+			build:  func(n *fake.Network) [][]string { return playRace(n, data, 150, 180, "iaa1other", raceOwner) },
+			reason: ReasonRaceFirstLastSenderNotMatch,
+		},
+		{
+			name:   "not handed to the designated owner",
+			build:  func(n *fake.Network) [][]string { return playRace(n, data, 150, 180, user, "iaa1other") },
+			reason: ReasonNftOwnerNotMatch,
+		},
+		{
+			name: "last tx not found",
+			build: func(n *fake.Network) [][]string {
+				rows := playRace(n, data, 150, 180, user, raceOwner)
+				rows[1][0] = "ABCDEF"
+				return rows
+			},
+			reason: ReasonTxResultUnachievable,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			n := fake.NewNetwork()
+			rows := tc.build(n)
+			res := verify(t, NewRaceVerifier(n.Registry(), raceClass, raceOwner, 100, 200), rows)
+
+			if res.Reason.GetCode() != tc.reason {
+				t.Fatalf("reason: want %q, got %q", tc.reason, res.Reason)
+			}
+			if !reflect.DeepEqual(res.Race, tc.race) {
+				t.Fatalf("race: want %+v, got %+v", tc.race, res.Race)
+			}
+			if res.Point != tc.point {
+				t.Fatalf("point: want %d, got %d", tc.point, res.Point)
+			}
+		})
+	}
+}
+
+func TestRaceVerifierBatch(t *testing.T) {
+	user := testUser.Address[chain.ChainIdAbbreviationIris]
+
+	// the first transfer sends nft0, racing another flow, and nft1 in one packet, then handed is given to the designated owner
+	play := func(n *fake.Network, batch []string, handed string) [][]string {
+		iris := n.Chain(chain.ChainIdAbbreviationIris)
+		iris.IssueDenom(chain.Class{ID: raceClass, Creator: user, Uri: "ipfs://race", Data: testClassData}, user)
+		iris.MintNFT(raceClass, chain.NFT{ID: "nft0", URI: "ipfs://nft", Data: `{"flow":"a02","start_height":"150"}`, Owner: user}, user)
+		iris.MintNFT(raceClass, chain.NFT{ID: "nft1", URI: "ipfs://nft", Data: `{"flow":"a01","start_height":"150"}`, Owner: user}, user)
+
+		iris.SetHeight(150)
+		first, classID := n.TransferBatch("i", "s", "1", raceClass, batch, user, testUser.Address["s"])
+		for _, h := range hopsA01[1:] {
+			_, classID = n.Transfer(h[0], h[1], h[2], classID, "nft1", testUser.Address[h[0]], testUser.Address[h[1]])
+		}
+		iris.SetHeight(180)
+		last := iris.TransferNFT(classID, handed, user, raceOwner)
+		return [][]string{{first}, {last}}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		batch  []string
+		handed string
+		reason ReasonCode
+	}{
+		{name: "token of the batch", batch: []string{"nft0", "nft1"}, handed: "nft1"},
+		{name: "token not in the batch", batch: []string{"nft0", "nft1"}, handed: "nft2", reason: ReasonNftTokenIdNotMatch},
+		{name: "token twice in the batch", batch: []string{"nft1", "nft1"}, handed: "nft1", reason: ReasonNftTokenAmbiguous},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			n := fake.NewNetwork()
+			rows := play(n, tc.batch, tc.handed)
+			res := verify(t, NewRaceVerifier(n.Registry(), raceClass, raceOwner, 100, 200), rows)
+			if res.Reason.GetCode() != tc.reason {
+				t.Fatalf("reason: want %q, got %q", tc.reason, res.Reason)
+			}
+			if len(tc.reason) == 0 && (res.Race == nil || res.Race.Flow != "a01" || res.Race.TokenId != "nft1") {
+				t.Fatalf("unexpected race %+v", res.Race)
+			}
+		})
+	}
+}
+
+func TestRaceVerifierAtHeight(t *testing.T) {
+	user := testUser.Address[chain.ChainIdAbbreviationIris]
+	data := `{"flow":"a01","start_height":"150"}`
+
+	for _, tc := range []struct {
+		name   string
+		owner  string // owner handed the nft at height 180
+		later  string // owner handed the nft at height 300
+		height int64  // height the ownership is evaluated at
+		reason ReasonCode
+	}{
+		{name: "moved after the end", owner: raceOwner, later: "iaa1other", height: 250},
+		{name: "moved before the end", owner: raceOwner, later: "iaa1other", height: 350, reason: ReasonNftOwnerNotMatch},
+		{name: "moved at the latest height", owner: raceOwner, later: "iaa1other", reason: ReasonNftOwnerNotMatch},
+		{name: "regained after the end", owner: "iaa1other", later: raceOwner, height: 250, reason: ReasonNftOwnerNotMatch},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			n := fake.NewNetwork()
+			rows := playRace(n, data, 150, 180, user, tc.owner)
+			f, _ := chain.LookupFlow("a01")
+			hash, _ := f.GetFinalIbcHash(raceClass)
+			iris := n.Chain(chain.ChainIdAbbreviationIris)
+			iris.SetHeight(300)
+			iris.TransferNFT("ibc/"+hash.String(), "nft1", tc.owner, tc.later)
+
+			r := n.Registry().AtHeights(map[string]int64{chain.ChainIdAbbreviationIris: tc.height})
+			res := verify(t, NewRaceVerifier(r, raceClass, raceOwner, 100, 200), rows)
+			if res.Reason.GetCode() != tc.reason {
+				t.Fatalf("reason: want %q, got %q", tc.reason, res.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,600 @@
+package verifier
+
+import (
+	"testing"
+
+	proto "github.com/gogo/protobuf/proto"
+	nfttypes "github.com/irisnet/irismod/modules/nft/types"
+
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/chain/fake"
+	"github.com/taramakage/gon-verifier/internal/types"
+)
+
+const testPoint = 7
+
+var (
+	testUser = UserInfo{
+		TeamName: "team-fake",
+		Github:   "fake",
+		Address: map[string]string{
+			chain.ChainIdAbbreviationIris:     "iaa1user",
+			chain.ChainIdAbbreviationStars:    "stars1user",
+			chain.ChainIdAbbreviationJuno:     "juno1user",
+			chain.ChainIdAbbreviationUptick:   "uptick1user",
+			chain.ChainIdAbbreviationOmniflix: "omniflix1user",
+		},
+	}
+
+	testClassData = `{"github_username":"fake","team_name":"team-fake"}`
+)
+
+// verifierCase builds a scenario on a fresh network and returns the evidence rows of the task.
+type verifierCase struct {
+	name   string
+	build  func(n *fake.Network) [][]string
+	reason ReasonCode // empty if the task passes
+	hop    int        // hop of the reason, not checked if 0
+}
+
+func runVerifierCases(t *testing.T, newVerifier func(r *chain.Registry) Verifier, cases []verifierCase) {
+	t.Helper()
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			n := fake.NewNetwork()
+			rows := tc.build(n)
+			res := verify(t, newVerifier(n.Registry()), rows)
+
+			if res.Reason.GetCode() != tc.reason {
+				t.Fatalf("reason: want %q, got %q", tc.reason, res.Reason)
+			}
+			if tc.hop != 0 && res.Reason.Hop != tc.hop {
+				t.Fatalf("hop: want %d, got %q", tc.hop, res.Reason)
+			}
+			wantPoint := int32(0)
+			if len(tc.reason) == 0 {
+				wantPoint = testPoint
+			}
+			if res.Point != wantPoint {
+				t.Fatalf("point: want %d, got %d", wantPoint, res.Point)
+			}
+		})
+	}
+}
+
+func verify(t *testing.T, vf Verifier, rows [][]string) *Response {
+	t.Helper()
+	params, err := vf.BuildParams(vf.Schema().Records(rows))
+	if err != nil {
+		t.Fatalf("build params: %v", err)
+	}
+	res := make(chan *Response, 1)
+	vf.Do(Request{
+		TaskNo: "T",
+		Point:  testPoint,
+		User:   testUser,
+		Params: params,
+	}, res)
+	return <-res
+}
+
+// issueAndMint issues a denom and mints an nft owned by owner on a chain.
+func issueAndMint(c *fake.Chain, classID, nftID, owner string) {
+	c.IssueDenom(chain.Class{ID: classID, Creator: owner, Uri: "ipfs://class", Data: testClassData}, owner)
+	c.MintNFT(classID, chain.NFT{ID: nftID, URI: "ipfs://nft", Data: "{}", Owner: owner}, owner)
+}
+
+func TestA1Verifier(t *testing.T) {
+	iris := chain.ChainIdAbbreviationIris
+	user := testUser.Address[iris]
+	issue := func(n *fake.Network, class chain.Class, sender string) [][]string {
+		return [][]string{{n.Chain(iris).IssueDenom(class, sender), class.ID}}
+	}
+	// record stores denom1 and a tx carrying msg whose first sender attribute is eventSender
+	record := func(n *fake.Network, msg proto.Message, eventSender string) [][]string {
+		n.Chain(iris).AddClass(chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData})
+		hash := n.Chain(iris).Record([]proto.Message{msg},
+			fake.NewEvent(types.EventTypeMessage, types.AttributeMsgSender, eventSender, types.AttributeMsgSender, user),
+			fake.NewEvent(types.EventTypeIssueDenom, types.AttributeDenomId, "denom1", types.AttributeDenomCreator, user),
+		)
+		return [][]string{{hash, "denom1"}}
+	}
+
+	runVerifierCases(t, func(r *chain.Registry) Verifier { return A1Verifier{r: r} }, []verifierCase{
+		{
+			name: "pass",
+			build: func(n *fake.Network) [][]string {
+				return issue(n, chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+			},
+		},
+		{
+			name: "evidence spaces are trimmed",
+			build: func(n *fake.Network) [][]string {
+				rows := issue(n, chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+				rows[0][0] = " " + rows[0][0] + " "
+				return rows
+			},
+		},
+		{
+			name:   "too many rows",
+			build:  func(n *fake.Network) [][]string { return [][]string{{"a", "b"}, {"c", "d"}} },
+			reason: ReasonParamsRowsInvalid,
+		},
+		{
+			name:   "tx not found",
+			build:  func(n *fake.Network) [][]string { return [][]string{{"ABCDEF", "denom1"}} },
+			reason: ReasonTxResultUnachievable,
+		},
+		{
+			name: "tx failed",
+			build: func(n *fake.Network) [][]string {
+				rows := issue(n, chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+				n.Chain(iris).SetTxCode(rows[0][0], 5)
+				return rows
+			},
+			reason: ReasonTxResultUnsuccessful,
+		},
+		{
+			name: "proof verified",
+			build: func(n *fake.Network) [][]string {
+				n.Chain(iris).VerifyProofs()
+				return issue(n, chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+			},
+		},
+		{
+			name: "proof not match the header",
+			build: func(n *fake.Network) [][]string {
+				rows := issue(n, chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+				n.Chain(iris).VerifyProofs()
+				n.Chain(iris).SetDataHash(rows[0][0], make([]byte, 32))
+				return rows
+			},
+			reason: ReasonTxProofInvalid,
+		},
+		{
+			name: "sender is not registered",
+			build: func(n *fake.Network) [][]string {
+				return issue(n, chain.Class{ID: "denom1", Creator: "iaa1other", Uri: "ipfs://class", Data: testClassData}, "iaa1other")
+			},
+			reason: ReasonTxMsgSenderNotMatch,
+		},
+		{
+			name: "signed by the user among several sender attributes",
+			build: func(n *fake.Network) [][]string {
+				return record(n, &nfttypes.MsgIssueDenom{Id: "denom1", Sender: user}, "iaa1feecollector")
+			},
+		},
+		{
+			name: "signed by another address with the user in the events",
+			build: func(n *fake.Network) [][]string {
+				return record(n, &nfttypes.MsgIssueDenom{Id: "denom1", Sender: "iaa1other"}, user)
+			},
+			reason: ReasonTxMsgSenderNotMatch,
+		},
+		{
+			name: "another msg type",
+			build: func(n *fake.Network) [][]string {
+				return record(n, &nfttypes.MsgMintNFT{Id: "nft1", DenomId: "denom1", Sender: user}, user)
+			},
+			reason: ReasonTxMsgTypeNotMatch,
+		},
+		{
+			name: "fees paid by another address",
+			build: func(n *fake.Network) [][]string {
+				n.Chain(iris).PayFees("iaa1other")
+				return issue(n, chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+			},
+		},
+		{
+			name: "signed by the user after another signer",
+			build: func(n *fake.Network) [][]string {
+				n.Chain(iris).AddClass(chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData})
+				hash := n.Chain(iris).Record([]proto.Message{
+					&nfttypes.MsgIssueDenom{Id: "denom0", Sender: "iaa1other"},
+					&nfttypes.MsgIssueDenom{Id: "denom1", Sender: user},
+				},
+					fake.NewEvent(types.EventTypeMessage, types.AttributeMsgSender, user),
+					fake.NewEvent(types.EventTypeIssueDenom, types.AttributeDenomId, "denom1", types.AttributeDenomCreator, user),
+				)
+				return [][]string{{hash, "denom1"}}
+			},
+		},
+		{
+			name: "class not found",
+			build: func(n *fake.Network) [][]string {
+				rows := issue(n, chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+				rows[0][1] = "denom2"
+				return rows
+			},
+			reason: ReasonClassNotFound,
+		},
+		{
+			name: "class uri is empty",
+			build: func(n *fake.Network) [][]string {
+				return issue(n, chain.Class{ID: "denom1", Creator: user, Data: testClassData}, user)
+			},
+			reason: ReasonClassUrIEmpty,
+		},
+		{
+			name: "class data is not json",
+			build: func(n *fake.Network) [][]string {
+				return issue(n, chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: "fake"}, user)
+			},
+			reason: ReasonClassDataInvalid,
+		},
+	})
+
+	runVerifierCases(t, func(r *chain.Registry) Verifier { return A1Verifier{r: r, feePayer: true} }, []verifierCase{
+		{
+			name: "fees paid by the user",
+			build: func(n *fake.Network) [][]string {
+				return issue(n, chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+			},
+		},
+		{
+			name: "fees of the task paid by another address",
+			build: func(n *fake.Network) [][]string {
+				n.Chain(iris).PayFees("iaa1other")
+				return issue(n, chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+			},
+			reason: ReasonTxFeePayerNotMatch,
+		},
+	})
+}
+
+func TestA2Verifier(t *testing.T) {
+	iris := chain.ChainIdAbbreviationIris
+	user := testUser.Address[iris]
+	mint := func(n *fake.Network, nfts ...chain.NFT) [][]string {
+		c := n.Chain(iris)
+		c.IssueDenom(chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user)
+		rows := make([][]string, 0, len(nfts))
+		for _, nft := range nfts {
+			rows = append(rows, []string{c.MintNFT("denom1", nft, user), "denom1", nft.ID})
+		}
+		return rows
+	}
+
+	runVerifierCases(t, func(r *chain.Registry) Verifier { return A2Verifier{r: r} }, []verifierCase{
+		{
+			name: "pass",
+			build: func(n *fake.Network) [][]string {
+				return mint(n,
+					chain.NFT{ID: "nft1", URI: "ipfs://1", Data: "{}", Owner: user},
+					chain.NFT{ID: "nft2", URI: "ipfs://2", Data: "{}", Owner: user},
+				)
+			},
+		},
+		{
+			name: "one row",
+			build: func(n *fake.Network) [][]string {
+				return mint(n, chain.NFT{ID: "nft1", URI: "ipfs://1", Data: "{}", Owner: user})
+			},
+			reason: ReasonParamsRowsInvalid,
+		},
+		{
+			name: "example row left",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{{"tx hash", "class id", "token id"}, {"a", "b", "c"}}
+			},
+			reason: ReasonParamsExampleRowLeft,
+		},
+		{
+			name: "nft minted to another owner",
+			build: func(n *fake.Network) [][]string {
+				return mint(n,
+					chain.NFT{ID: "nft1", URI: "ipfs://1", Data: "{}", Owner: user},
+					chain.NFT{ID: "nft2", URI: "ipfs://2", Data: "{}", Owner: "iaa1other"},
+				)
+			},
+			reason: ReasonNftOwnerNotMatch,
+		},
+		{
+			name: "nft uri is empty",
+			build: func(n *fake.Network) [][]string {
+				return mint(n,
+					chain.NFT{ID: "nft1", Data: "{}", Owner: user},
+					chain.NFT{ID: "nft2", URI: "ipfs://2", Data: "{}", Owner: user},
+				)
+			},
+			reason: ReasonNftUriEmpty,
+		},
+		{
+			name: "nft data is empty",
+			build: func(n *fake.Network) [][]string {
+				return mint(n,
+					chain.NFT{ID: "nft1", URI: "ipfs://1", Data: "{}", Owner: user},
+					chain.NFT{ID: "nft2", URI: "ipfs://2", Owner: user},
+				)
+			},
+			reason: ReasonNftDataEmpty,
+		},
+		{
+			name: "nft not found",
+			build: func(n *fake.Network) [][]string {
+				rows := mint(n,
+					chain.NFT{ID: "nft1", URI: "ipfs://1", Data: "{}", Owner: user},
+					chain.NFT{ID: "nft2", URI: "ipfs://2", Data: "{}", Owner: user},
+				)
+				rows[1][2] = "nft3"
+				return rows
+			},
+			reason: ReasonNftNotFound,
+		},
+	})
+}
+
+// transferFromIris mints on iris and transfers to dest, it returns the row of the transfer evidence.
+func transferFromIris(n *fake.Network, dest, receiver, chainId string) []string {
+	iris := chain.ChainIdAbbreviationIris
+	issueAndMint(n.Chain(iris), "denom1", "nft1", testUser.Address[iris])
+	hash, classID := n.Transfer(iris, dest, "1", "denom1", "nft1", testUser.Address[iris], receiver)
+	return []string{hash, classID, "nft1", chainId}
+}
+
+// batchFromIris mints on iris and transfers the tokens to dest in one packet,
+// it returns the row of the transfer evidence for nft1.
+func batchFromIris(n *fake.Network, dest, chainId string, tokenIds ...string) []string {
+	iris := chain.ChainIdAbbreviationIris
+	issueAndMint(n.Chain(iris), "denom1", "nft1", testUser.Address[iris])
+	hash, classID := n.TransferBatch(iris, dest, "1", "denom1", tokenIds, testUser.Address[iris], testUser.Address[dest])
+	return []string{hash, classID, "nft1", chainId}
+}
+
+// transferToIris mints on iris, transfers to src and back, it returns the row of the transfer back.
+func transferToIris(n *fake.Network, src, sender, chainId string) []string {
+	iris := chain.ChainIdAbbreviationIris
+	issueAndMint(n.Chain(iris), "denom1", "nft1", testUser.Address[iris])
+	_, classID := n.Transfer(iris, src, "1", "denom1", "nft1", testUser.Address[iris], sender)
+	hash, _ := n.Transfer(src, iris, "1", classID, "nft1", sender, testUser.Address[iris])
+	return []string{hash, classID, "nft1", chainId}
+}
+
+func TestA3Verifier(t *testing.T) {
+	stars := chain.ChainIdAbbreviationStars
+	juno := chain.ChainIdAbbreviationJuno
+
+	runVerifierCases(t, func(r *chain.Registry) Verifier { return A3Verifier{r: r} }, []verifierCase{
+		{
+			name: "pass to stargaze",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferFromIris(n, stars, testUser.Address[stars], chain.ChainIdValueStars)}
+			},
+		},
+		{
+			name: "pass to juno with wasm prefix",
+			build: func(n *fake.Network) [][]string {
+				row := transferFromIris(n, juno, testUser.Address[juno], chain.ChainIdValueJuno)
+				row[1] = "wasm." + row[1]
+				return [][]string{row}
+			},
+		},
+		{
+			name: "unknown chain id",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferFromIris(n, stars, testUser.Address[stars], chain.ChainIdValueUptick)}
+			},
+			reason: ReasonParamsChainIdEmpty,
+		},
+		{
+			name: "another collection on dest",
+			build: func(n *fake.Network) [][]string {
+				row := transferFromIris(n, stars, testUser.Address[stars], chain.ChainIdValueStars)
+				row[1] = "stars1contract"
+				return [][]string{row}
+			},
+			reason: ReasonIbcClassNotMatch,
+		},
+		{
+			name: "collection of another class on dest",
+			build: func(n *fake.Network) [][]string {
+				iris := n.Chain(chain.ChainIdAbbreviationIris)
+				issueAndMint(iris, "denom2", "nft2", testUser.Address[chain.ChainIdAbbreviationIris])
+				_, other := n.Transfer(chain.ChainIdAbbreviationIris, stars, "1", "denom2", "nft2", testUser.Address[chain.ChainIdAbbreviationIris], testUser.Address[stars])
+				row := transferFromIris(n, stars, testUser.Address[stars], chain.ChainIdValueStars)
+				row[1] = other
+				return [][]string{row}
+			},
+			reason: ReasonIbcClassNotMatch,
+		},
+		{
+			name: "received by another address",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferFromIris(n, stars, "stars1other", chain.ChainIdValueStars)}
+			},
+			reason: ReasonNftRecipientNotMatch,
+		},
+		{
+			name: "handed to another address on dest",
+			build: func(n *fake.Network) [][]string {
+				row := transferFromIris(n, juno, testUser.Address[juno], chain.ChainIdValueJuno)
+				n.Chain(juno).TransferNFT(row[1], row[2], testUser.Address[juno], "juno1other")
+				return [][]string{row}
+			},
+			reason: ReasonNftOwnerNotMatch,
+		},
+		{
+			name: "token id not match",
+			build: func(n *fake.Network) [][]string {
+				row := transferFromIris(n, stars, testUser.Address[stars], chain.ChainIdValueStars)
+				row[2] = "nft2"
+				return [][]string{row}
+			},
+			reason: ReasonNftTokenIdNotMatch,
+		},
+		{
+			name: "batch carrying the token",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{batchFromIris(n, stars, chain.ChainIdValueStars, "nft0", "nft1", "nft2")}
+			},
+		},
+		{
+			name: "batch not carrying the token",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{batchFromIris(n, stars, chain.ChainIdValueStars, "nft0", "nft2")}
+			},
+			reason: ReasonNftTokenIdNotMatch,
+		},
+		{
+			name: "batch carrying the token twice",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{batchFromIris(n, juno, chain.ChainIdValueJuno, "nft1", "nft1")}
+			},
+			reason: ReasonNftTokenAmbiguous,
+		},
+	})
+}
+
+func TestA4Verifier(t *testing.T) {
+	uptick := chain.ChainIdAbbreviationUptick
+	omniflix := chain.ChainIdAbbreviationOmniflix
+
+	runVerifierCases(t, func(r *chain.Registry) Verifier { return A4Verifier{r: r} }, []verifierCase{
+		{
+			name: "pass to uptick",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferFromIris(n, uptick, testUser.Address[uptick], chain.ChainIdValueUptick)}
+			},
+		},
+		{
+			name: "pass to omniflix",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferFromIris(n, omniflix, testUser.Address[omniflix], chain.ChainIdValueOmniflix)}
+			},
+		},
+		{
+			name: "wasm chain id",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferFromIris(n, uptick, testUser.Address[uptick], chain.ChainIdValueStars)}
+			},
+			reason: ReasonParamsChainIdEmpty,
+		},
+		{
+			name: "ibc class not found",
+			build: func(n *fake.Network) [][]string {
+				row := transferFromIris(n, uptick, testUser.Address[uptick], chain.ChainIdValueUptick)
+				row[1] = "ibc/FAKE"
+				return [][]string{row}
+			},
+			reason: ReasonClassNotFound,
+		},
+		{
+			name: "received by another address",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferFromIris(n, omniflix, "omniflix1other", chain.ChainIdValueOmniflix)}
+			},
+			reason: ReasonNftRecipientNotMatch,
+		},
+		{
+			name: "batch carrying the token",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{batchFromIris(n, uptick, chain.ChainIdValueUptick, "nft1", "nft2")}
+			},
+		},
+		{
+			name: "batch carrying the token twice",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{batchFromIris(n, omniflix, chain.ChainIdValueOmniflix, "nft1", "nft2", "nft1")}
+			},
+			reason: ReasonNftTokenAmbiguous,
+		},
+		{
+			name: "fees paid by another address",
+			build: func(n *fake.Network) [][]string {
+				n.Chain(chain.ChainIdAbbreviationIris).PayFees("iaa1other")
+				return [][]string{transferFromIris(n, uptick, testUser.Address[uptick], chain.ChainIdValueUptick)}
+			},
+		},
+	})
+
+	runVerifierCases(t, func(r *chain.Registry) Verifier { return A4Verifier{r: r, feePayer: true} }, []verifierCase{
+		{
+			name: "fees of the task paid by another address",
+			build: func(n *fake.Network) [][]string {
+				n.Chain(chain.ChainIdAbbreviationIris).PayFees("iaa1other")
+				return [][]string{transferFromIris(n, uptick, testUser.Address[uptick], chain.ChainIdValueUptick)}
+			},
+			reason: ReasonTxFeePayerNotMatch,
+		},
+	})
+}
+
+func TestA5Verifier(t *testing.T) {
+	stars := chain.ChainIdAbbreviationStars
+	juno := chain.ChainIdAbbreviationJuno
+
+	runVerifierCases(t, func(r *chain.Registry) Verifier { return A5Verifier{r: r} }, []verifierCase{
+		{
+			name: "pass from stargaze",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferToIris(n, stars, testUser.Address[stars], chain.ChainIdValueStars)}
+			},
+		},
+		{
+			name: "pass from juno",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferToIris(n, juno, testUser.Address[juno], chain.ChainIdValueJuno)}
+			},
+		},
+		{
+			name: "sent by another address",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferToIris(n, stars, "stars1other", chain.ChainIdValueStars)}
+			},
+			reason: ReasonTxMsgSenderNotMatch,
+		},
+		{
+			name: "tx on another chain",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferToIris(n, stars, testUser.Address[stars], chain.ChainIdValueJuno)}
+			},
+			reason: ReasonTxResultUnachievable,
+		},
+		{
+			name: "nft not back on iris",
+			build: func(n *fake.Network) [][]string {
+				row := transferToIris(n, stars, testUser.Address[stars], chain.ChainIdValueStars)
+				row[2] = "nft2"
+				return [][]string{row}
+			},
+			reason: ReasonNftNotFound,
+		},
+	})
+}
+
+func TestA6Verifier(t *testing.T) {
+	uptick := chain.ChainIdAbbreviationUptick
+	omniflix := chain.ChainIdAbbreviationOmniflix
+
+	runVerifierCases(t, func(r *chain.Registry) Verifier { return A6Verifier{r: r} }, []verifierCase{
+		{
+			name: "pass from uptick",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferToIris(n, uptick, testUser.Address[uptick], chain.ChainIdValueUptick)}
+			},
+		},
+		{
+			name: "pass from omniflix",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferToIris(n, omniflix, testUser.Address[omniflix], chain.ChainIdValueOmniflix)}
+			},
+		},
+		{
+			name: "class not found on src",
+			build: func(n *fake.Network) [][]string {
+				row := transferToIris(n, uptick, testUser.Address[uptick], chain.ChainIdValueUptick)
+				row[1] = "ibc/FAKE"
+				return [][]string{row}
+			},
+			reason: ReasonClassNotFound,
+		},
+		{
+			name: "sent by another address",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{transferToIris(n, omniflix, "omniflix1other", chain.ChainIdValueOmniflix)}
+			},
+			reason: ReasonTxMsgSenderNotMatch,
+		},
+	})
+}
@@ -0,0 +1,70 @@
+package verifier
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+)
+
+// checkWindow checks a tx at a height of a chain is inside the window of its task, nil if it is or there is no window.
+// The block time is only queried when the window has a time bound.
+func checkWindow(r *chain.Registry, w *campaign.Window, abbr string, height int64) *Reason {
+	if w == nil {
+		return nil
+	}
+	if start, ok := w.StartHeights[abbr]; ok && height < start {
+		return NewReason(ReasonTxTooEarly).Want(strconv.FormatInt(start, 10), strconv.FormatInt(height, 10))
+	}
+	if end, ok := w.EndHeights[abbr]; ok && height > end {
+		return NewReason(ReasonTxTooLate).Want(strconv.FormatInt(end, 10), strconv.FormatInt(height, 10))
+	}
+	if w.StartTime.IsZero() && w.EndTime.IsZero() {
+		return nil
+	}
+
+	timer, ok := r.GetChain(abbr).(chain.BlockTimer)
+	if !ok {
+		return NewReason(ReasonTxResultUnachievable)
+	}
+	t, err := timer.BlockTime(height)
+	if err != nil {
+		return NewReason(ReasonTxResultUnachievable)
+	}
+	if !w.StartTime.IsZero() && t.Before(w.StartTime) {
+		return NewReason(ReasonTxTooEarly).Want(formatTime(w.StartTime), formatTime(t))
+	}
+	if !w.EndTime.IsZero() && t.After(w.EndTime) {
+		return NewReason(ReasonTxTooLate).Want(formatTime(w.EndTime), formatTime(t))
+	}
+	return nil
+}
+
+// resolveWindow returns the window with the times of its EndTimeAt blocks folded into its end time,
+// the window itself if it has none.
+func resolveWindow(r *chain.Registry, w *campaign.Window) (*campaign.Window, error) {
+	if w == nil || len(w.EndTimeAt) == 0 {
+		return w, nil
+	}
+	resolved := *w
+	for abbr, height := range w.EndTimeAt {
+		timer, ok := r.GetChain(abbr).(chain.BlockTimer)
+		if !ok {
+			return nil, fmt.Errorf("chain %s can't tell the time of block %d", abbr, height)
+		}
+		t, err := timer.BlockTime(height)
+		if err != nil {
+			return nil, fmt.Errorf("time of block %d of chain %s: %w", height, abbr, err)
+		}
+		if resolved.EndTime.IsZero() || t.Before(resolved.EndTime) {
+			resolved.EndTime = t
+		}
+	}
+	return &resolved, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
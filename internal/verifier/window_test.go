@@ -0,0 +1,143 @@
+package verifier
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/taramakage/gon-verifier/internal/campaign"
+	"github.com/taramakage/gon-verifier/internal/chain"
+	"github.com/taramakage/gon-verifier/internal/chain/fake"
+)
+
+func TestCheckWindow(t *testing.T) {
+	iris := chain.ChainIdAbbreviationIris
+	at := func(height int64) time.Time { return fake.GenesisTime.Add(time.Duration(height) * fake.BlockInterval) }
+
+	for _, tc := range []struct {
+		name   string
+		w      *campaign.Window
+		abbr   string
+		height int64
+		reason ReasonCode
+	}{
+		{name: "no window", height: 100},
+		{name: "inside heights", w: &campaign.Window{StartHeights: map[string]int64{iris: 100}, EndHeights: map[string]int64{iris: 200}}, abbr: iris, height: 200},
+		{name: "before start height", w: &campaign.Window{StartHeights: map[string]int64{iris: 100}}, abbr: iris, height: 99, reason: ReasonTxTooEarly},
+		{name: "after end height", w: &campaign.Window{EndHeights: map[string]int64{iris: 200}}, abbr: iris, height: 201, reason: ReasonTxTooLate},
+		{name: "heights of another chain", w: &campaign.Window{EndHeights: map[string]int64{iris: 200}}, abbr: chain.ChainIdAbbreviationStars, height: 201},
+		{name: "inside times", w: &campaign.Window{StartTime: at(100), EndTime: at(200)}, abbr: iris, height: 100},
+		{name: "before start time", w: &campaign.Window{StartTime: at(100)}, abbr: iris, height: 99, reason: ReasonTxTooEarly},
+		{name: "after end time", w: &campaign.Window{EndTime: at(200)}, abbr: chain.ChainIdAbbreviationJuno, height: 201, reason: ReasonTxTooLate},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			reason := checkWindow(fake.NewNetwork().Registry(), tc.w, tc.abbr, tc.height)
+			if reason.GetCode() != tc.reason {
+				t.Fatalf("reason: want %q, got %q", tc.reason, reason)
+			}
+		})
+	}
+}
+
+func TestResolveWindow(t *testing.T) {
+	iris, juno := chain.ChainIdAbbreviationIris, chain.ChainIdAbbreviationJuno
+	at := func(height int64) time.Time { return fake.GenesisTime.Add(time.Duration(height) * fake.BlockInterval) }
+	r := fake.NewNetwork().Registry()
+
+	for _, tc := range []struct {
+		name string
+		w    *campaign.Window
+		end  time.Time
+	}{
+		{name: "no window"},
+		{name: "end time only", w: &campaign.Window{EndTime: at(200)}, end: at(200)},
+		{name: "time of a block", w: &campaign.Window{EndTimeAt: map[string]int64{iris: 200}}, end: at(200)},
+		{name: "earlier end time", w: &campaign.Window{EndTime: at(100), EndTimeAt: map[string]int64{iris: 200}}, end: at(100)},
+		{name: "earliest block", w: &campaign.Window{EndTimeAt: map[string]int64{iris: 200, juno: 150}}, end: at(150)},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := resolveWindow(r, tc.w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.w == nil {
+				if w != nil {
+					t.Fatalf("want no window, got %+v", w)
+				}
+				return
+			}
+			if !w.EndTime.Equal(tc.end) {
+				t.Fatalf("end time: want %s, got %s", tc.end, w.EndTime)
+			}
+		})
+	}
+
+	// a hop on another chain after the time of the iris block is late
+	w, err := resolveWindow(r, &campaign.Window{EndTimeAt: map[string]int64{iris: 200}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason := checkWindow(r, w, juno, 201); reason.GetCode() != ReasonTxTooLate {
+		t.Fatalf("reason: want %q, got %q", ReasonTxTooLate, reason)
+	}
+}
+
+func TestVerifierWindow(t *testing.T) {
+	iris := chain.ChainIdAbbreviationIris
+	user := testUser.Address[iris]
+	late := &campaign.Window{EndHeights: map[string]int64{iris: 50, chain.ChainIdAbbreviationStars: 50}}
+
+	runVerifierCases(t, func(r *chain.Registry) Verifier { return A1Verifier{r: r, w: late} }, []verifierCase{
+		{
+			name: "issued in time",
+			build: func(n *fake.Network) [][]string {
+				return [][]string{{n.Chain(iris).IssueDenom(chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user), "denom1"}}
+			},
+		},
+		{
+			name: "issued after the end",
+			build: func(n *fake.Network) [][]string {
+				n.Chain(iris).SetHeight(60)
+				return [][]string{{n.Chain(iris).IssueDenom(chain.Class{ID: "denom1", Creator: user, Uri: "ipfs://class", Data: testClassData}, user), "denom1"}}
+			},
+			reason: ReasonTxTooLate,
+		},
+	})
+
+	for _, ngb := range []bool{false, true} {
+		ngb := ngb
+		newVerifier := func(r *chain.Registry) Verifier {
+			vf, err := NewFlowVerifier(r, "a01", ngb)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vf.w = late
+			return vf
+		}
+		rows := func(hashes []string, classID string) [][]string {
+			if ngb {
+				return [][]string{{classID, "nft1"}}
+			}
+			return txRows(hashes)
+		}
+		runVerifierCases(t, newVerifier, []verifierCase{
+			{
+				name: fmt.Sprintf("flow in time ngb=%v", ngb),
+				build: func(n *fake.Network) [][]string {
+					return rows(playFlow(n, hopsA01))
+				},
+			},
+			{
+				name: fmt.Sprintf("second hop after the end ngb=%v", ngb),
+				build: func(n *fake.Network) [][]string {
+					n.Chain(chain.ChainIdAbbreviationStars).SetHeight(60)
+					return rows(playFlow(n, hopsA01))
+				},
+				reason: ReasonTxTooLate,
+				hop:    2,
+			},
+		})
+	}
+}